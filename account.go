@@ -0,0 +1,58 @@
+package d3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RateLimit represents the API rate limit applied to the account
+type RateLimit struct {
+	RequestsPerMinute int       `json:"requests_per_minute"`
+	Remaining         int       `json:"remaining"`
+	ResetAt           time.Time `json:"reset_at,omitempty"`
+}
+
+// Usage represents account usage and quota information
+type Usage struct {
+	CreditsRemaining  int64 `json:"credits_remaining"`
+	ConversionsUsed   int64 `json:"conversions_used"`
+	ConversionsLimit  int64 `json:"conversions_limit"`
+	StorageBytesUsed  int64 `json:"storage_bytes_used"`
+	StorageBytesLimit int64 `json:"storage_bytes_limit"`
+	// MaxFileSize is the largest file the plan allows uploading, in bytes,
+	// or 0 if unlimited
+	MaxFileSize int64     `json:"max_file_size"`
+	PeriodStart time.Time `json:"period_start,omitempty"`
+	PeriodEnd   time.Time `json:"period_end,omitempty"`
+	RateLimit   RateLimit `json:"rate_limit"`
+}
+
+// ConversionsRemaining returns the conversions left in the current billing
+// period, or -1 if the plan has no monthly limit
+func (u *Usage) ConversionsRemaining() int64 {
+	if u.ConversionsLimit <= 0 {
+		return -1
+	}
+	if u.ConversionsUsed >= u.ConversionsLimit {
+		return 0
+	}
+	return u.ConversionsLimit - u.ConversionsUsed
+}
+
+// GetUsage gets the account's remaining credits, monthly usage, storage
+// consumption and rate limits
+func (c *Dragdropdo) GetUsage() (*Usage, error) {
+	return c.getUsage(context.Background())
+}
+
+func (c *Dragdropdo) getUsage(ctx context.Context) (*Usage, error) {
+	resp, err := fetch[Usage](c.newRequest().SetContext(ctx), http.MethodGet, "/v1/biz/usage")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/biz/usage" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"credits_remaining":  1200,
+				"conversions_used":   950,
+				"conversions_limit":  1000,
+				"storage_bytes_used": 5 * 1024 * 1024,
+				"rate_limit": map[string]interface{}{
+					"requests_per_minute": 60,
+					"remaining":           59,
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	usage, err := client.GetUsage()
+	if err != nil {
+		t.Fatalf("Failed to get usage: %v", err)
+	}
+
+	if usage.CreditsRemaining != 1200 {
+		t.Errorf("Expected 1200 credits remaining, got %d", usage.CreditsRemaining)
+	}
+	if usage.ConversionsRemaining() != 50 {
+		t.Errorf("Expected 50 conversions remaining, got %d", usage.ConversionsRemaining())
+	}
+	if usage.RateLimit.RequestsPerMinute != 60 {
+		t.Errorf("Expected 60 requests per minute, got %d", usage.RateLimit.RequestsPerMinute)
+	}
+}
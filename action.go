@@ -0,0 +1,26 @@
+package d3
+
+// Action is the kind of operation applied to files, see OperationOptions.
+// Actions the client has no constant for can be given as Action("name").
+type Action string
+
+// Actions supported by the API
+const (
+	// ActionConvert converts files to the format in the convert_to parameter
+	ActionConvert Action = "convert"
+	// ActionCompress compresses files at the level in the
+	// compression_value parameter
+	ActionCompress Action = "compress"
+	// ActionMerge merges files into one
+	ActionMerge Action = "merge"
+	// ActionZip creates a ZIP archive of files
+	ActionZip Action = "zip"
+	// ActionShare generates shareable links to files
+	ActionShare Action = "share"
+	// ActionLock protects PDFs with the password parameter
+	ActionLock Action = "lock"
+	// ActionUnlock removes the password parameter from PDFs
+	ActionUnlock Action = "unlock"
+	// ActionResetPassword replaces the old_password of PDFs with new_password
+	ActionResetPassword Action = "reset_password"
+)
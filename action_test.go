@@ -0,0 +1,47 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_ConvenienceMethodActions(t *testing.T) {
+	var got []Action
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Action Action `json:"action"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body.Action)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	keys := []FileKey{"file-key-123"}
+	client.Convert(keys, "pdf", nil)
+	client.Compress(keys, "", nil)
+	client.Merge(keys, nil)
+	client.Zip(keys, nil)
+	client.Share(keys, nil)
+	client.LockPdf(keys, "Secure-password-1", nil)
+	client.UnlockPdf(keys, "old-password", nil)
+	client.ResetPdfPassword(keys, "old-password", "Secure-password-1", nil)
+
+	want := []Action{ActionConvert, ActionCompress, ActionMerge, ActionZip, ActionShare, ActionLock, ActionUnlock, ActionResetPassword}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d operations, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected action %q, got %q", want[i], got[i])
+		}
+	}
+}
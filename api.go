@@ -0,0 +1,71 @@
+package d3
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -source=api.go -destination=mocks/mock_d3api.go -package=mocks
+
+var _ D3API = (*Dragdropdo)(nil)
+
+// D3API is the set of D3 API calls made by *Dragdropdo. Accept it instead
+// of the concrete client so tests can inject a fake such as d3mock.Client.
+// Methods that configure a client (Clone, SetAPIKey, SetHeader, SetTimeout
+// and the On* hooks) are not part of it.
+type D3API interface {
+	// Uploads
+	UploadFile(options UploadFileOptions) (*UploadResponse, error)
+	UploadFileContext(ctx context.Context, options UploadFileOptions) (*UploadResponse, error)
+	ListPendingUploads() ([]PendingUpload, error)
+	AbortUpload(options AbortUploadOptions) error
+	AbortStaleUploads(olderThan time.Duration) ([]PendingUpload, error)
+	MaxFileSize() (int64, error)
+
+	// Operations
+	CheckSupportedOperation(options SupportedOperationOptions) (*SupportedOperationResponse, error)
+	GetSupportedOperations() (*OperationCatalog, error)
+	Capabilities() *OperationCatalog
+	RefreshCapabilities(ctx context.Context) error
+	CreateOperation(options OperationOptions) (*OperationResponse, error)
+	Convert(fileKeys []FileKey, convertTo string, notes map[string]string) (*OperationResponse, error)
+	Compress(fileKeys []FileKey, compressionValue string, notes map[string]string) (*OperationResponse, error)
+	Merge(fileKeys []FileKey, notes map[string]string) (*OperationResponse, error)
+	Zip(fileKeys []FileKey, notes map[string]string) (*OperationResponse, error)
+	Share(fileKeys []FileKey, notes map[string]string) (*OperationResponse, error)
+	LockPdf(fileKeys []FileKey, password string, notes map[string]string) (*OperationResponse, error)
+	UnlockPdf(fileKeys []FileKey, password string, notes map[string]string) (*OperationResponse, error)
+	ResetPdfPassword(fileKeys []FileKey, oldPassword, newPassword string, notes map[string]string) (*OperationResponse, error)
+	GetStatus(options StatusOptions) (*StatusResponse, error)
+	PollStatus(options PollStatusOptions) (*StatusResponse, error)
+	WaitForOperation(ctx context.Context, mainTaskID MainTaskID, opts WaitOptions) ([]Result, error)
+	Results(ctx context.Context, status *StatusResponse) ([]Result, error)
+
+	// Jobs
+	Submit(ctx context.Context, options OperationOptions) (*Job, error)
+	Job(mainTaskID MainTaskID) *Job
+	PendingJobs(ctx context.Context) ([]*Job, error)
+
+	// Files
+	TagFile(fileKey FileKey, tags []string) (*FileInfo, error)
+	SetFileExpiry(fileKey FileKey, ttl time.Duration) (*FileInfo, error)
+	DeleteFile(fileKey FileKey) error
+	DeleteFiles(fileKeys []FileKey) DeleteResults
+	SearchFiles(options SearchOptions) (*SearchFilesResponse, error)
+	IterateFiles(options SearchOptions) *FileIterator
+	StorageStats() (*StorageStats, error)
+	CreateShareLink(fileKey FileKey, options ShareOptions) (*ShareLink, error)
+
+	// Folders
+	CreateFolder(options CreateFolderOptions) (*Folder, error)
+	MoveFile(options MoveFileOptions) (*FileInfo, error)
+	ListFolder(options ListFolderOptions) (*ListFolderResponse, error)
+
+	// Account
+	GetUsage() (*Usage, error)
+	Ping(ctx context.Context) error
+	HealthCheck(ctx context.Context) (*HealthReport, error)
+
+	// Close shuts the client down, see (*Dragdropdo).Close
+	Close(ctx context.Context) error
+}
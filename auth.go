@@ -0,0 +1,25 @@
+package d3
+
+import "fmt"
+
+// AuthScheme selects how the API key is sent to the API gateway
+type AuthScheme string
+
+const (
+	// AuthBearer sends "Authorization: Bearer <key>" (the default)
+	AuthBearer AuthScheme = "bearer"
+	// AuthAPIKeyHeader sends "X-API-Key: <key>"
+	AuthAPIKeyHeader AuthScheme = "api_key_header"
+	// AuthQueryParam sends the key as the api_key query parameter. Prefer a
+	// header scheme where possible, since URLs tend to end up in logs.
+	AuthQueryParam AuthScheme = "query"
+)
+
+// validate reports whether the scheme is known; empty means AuthBearer
+func (s AuthScheme) validate() error {
+	switch s {
+	case "", AuthBearer, AuthAPIKeyHeader, AuthQueryParam:
+		return nil
+	}
+	return fmt.Errorf("unknown auth scheme %q", string(s))
+}
@@ -0,0 +1,74 @@
+package d3
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_AuthScheme(t *testing.T) {
+	var gotAuth, gotAPIKey, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotAPIKey = r.Header.Get("X-API-Key")
+		gotQuery = r.URL.Query().Get("api_key")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		scheme                     AuthScheme
+		wantAuth, wantKey, wantQry string
+	}{
+		{"", "Bearer test-key", "", ""},
+		{AuthBearer, "Bearer test-key", "", ""},
+		{AuthAPIKeyHeader, "", "test-key", ""},
+		{AuthQueryParam, "", "", "test-key"},
+	}
+
+	for _, tt := range tests {
+		client, err := NewClient("test-key", WithBaseURL(server.URL), WithAuthScheme(tt.scheme))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if gotAuth != tt.wantAuth || gotAPIKey != tt.wantKey || gotQuery != tt.wantQry {
+			t.Errorf("Scheme %q: got Authorization=%q X-API-Key=%q api_key=%q", tt.scheme, gotAuth, gotAPIKey, gotQuery)
+		}
+	}
+
+	if _, err := NewClient("test-key", WithAuthScheme("basic")); err == nil {
+		t.Error("Expected error for unknown auth scheme")
+	}
+}
+
+func TestClient_AuthScheme_Redacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	for _, scheme := range []AuthScheme{AuthAPIKeyHeader, AuthQueryParam} {
+		var buf bytes.Buffer
+		client, err := NewClient("secret-api-key",
+			WithBaseURL(server.URL),
+			WithAuthScheme(scheme),
+			WithCurlWriter(&buf),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if strings.Contains(buf.String(), "secret-api-key") {
+			t.Errorf("Scheme %q leaked the API key: %s", scheme, buf.String())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+//go:build !d3_stdlib
+
+package d3
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// restyBackend sends API requests through resty. The resty client is
+// configured once and only used to create requests, which is safe for
+// concurrent use; per-request settings are passed to do.
+type restyBackend struct {
+	client *resty.Client
+}
+
+func newAPIBackend(httpClient *http.Client) apiBackend {
+	return &restyBackend{client: resty.NewWithClient(httpClient)}
+}
+
+func (b *restyBackend) do(ctx context.Context, method, url string, header http.Header, body []byte) (*apiResponse, error) {
+	req := b.client.R().
+		SetContext(ctx).
+		SetHeaderMultiValues(header)
+	if body != nil {
+		req.SetBody(body)
+	}
+
+	resp, err := req.Execute(method, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiResponse{
+		StatusCode: resp.StatusCode(),
+		Header:     resp.Header(),
+		Body:       resp.Body(),
+	}, nil
+}
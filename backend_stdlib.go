@@ -0,0 +1,49 @@
+//go:build d3_stdlib
+
+package d3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// stdlibBackend sends API requests with net/http only
+type stdlibBackend struct {
+	client *http.Client
+}
+
+func newAPIBackend(httpClient *http.Client) apiBackend {
+	return &stdlibBackend{client: httpClient}
+}
+
+func (b *stdlibBackend) do(ctx context.Context, method, url string, header http.Header, body []byte) (*apiResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}, nil
+}
@@ -0,0 +1,478 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBulkUploadConcurrency is the number of files a BulkProcessor
+// uploads at once (BulkOptions.UploadConcurrency)
+const DefaultBulkUploadConcurrency = 4
+
+// UploadOrder orders the uploads of a BulkProcessor's files of equal
+// priority
+type UploadOrder int
+
+const (
+	// UploadPathOrder uploads files in path order
+	UploadPathOrder UploadOrder = iota
+	// UploadSmallestFirst uploads the smallest files first, so quick
+	// uploads finish early
+	UploadSmallestFirst
+	// UploadLargestFirst uploads the largest files first, so the longest
+	// uploads do not hold up the end of the run
+	UploadLargestFirst
+)
+
+// BulkOptions configures a BulkProcessor
+type BulkOptions struct {
+	// Source is the directory whose files are processed
+	Source string
+	// Pattern selects the files by name, e.g. "*.docx"; empty selects all
+	Pattern string
+	// Recursive includes the files of Source's sub-directories
+	Recursive bool
+	// Action, Parameters and Notes describe the operation applied
+	Action     Action
+	Parameters map[string]interface{}
+	Notes      map[string]string
+	// Destination is the directory outputs are written to. Outputs of a
+	// file in a sub-directory of Source go to the same sub-directory.
+	Destination string
+	// UploadConcurrency is the number of files uploaded at once. Defaults
+	// to DefaultBulkUploadConcurrency.
+	UploadConcurrency int
+	// Priority returns the priority of an input file: files of a higher
+	// priority are uploaded first, and each operation is submitted to the
+	// Manager with the highest priority of its files. Defaults to
+	// PriorityNormal for every file; see also BulkProcessor.Prioritize.
+	Priority func(path string, info fs.FileInfo) Priority
+	// UploadOrder orders the uploads of files of equal priority. Defaults
+	// to UploadPathOrder.
+	UploadOrder UploadOrder
+	// BatchSize is the number of files per operation, defaulting to one.
+	// Merge and zip combine each batch into one output.
+	BatchSize int
+	// Manager configures how the operations are run
+	Manager ManagerOptions
+}
+
+// BulkProcessor applies an operation to every file of a directory: it
+// uploads them, runs the operations in batches, waits for them and
+// downloads the outputs.
+//
+//	report, err := d3.NewBulkProcessor(client, d3.BulkOptions{
+//		Source:      "invoices",
+//		Pattern:     "*.docx",
+//		Action:      d3.ActionConvert,
+//		Parameters:  map[string]interface{}{"convert_to": "pdf"},
+//		Destination: "out",
+//	}).Run(ctx)
+type BulkProcessor struct {
+	client  *Dragdropdo
+	options BulkOptions
+
+	// mu guards priorities, the priorities set with Prioritize
+	mu         sync.Mutex
+	priorities map[string]Priority
+}
+
+// BulkFileResult is the outcome for one input file of a BulkProcessor run
+type BulkFileResult struct {
+	// Path is the input file
+	Path string
+	// FileKey is the uploaded file's key, empty if the upload failed
+	FileKey FileKey
+	// MainTaskID is the operation the file was part of, if created
+	MainTaskID MainTaskID
+	// Outputs are the files written for this input. The output of a merge
+	// or zip is listed for every file of its batch.
+	Outputs []string
+	// Err is why the file was not processed, nil if it was
+	Err error
+}
+
+// BulkReport summarizes a BulkProcessor run
+type BulkReport struct {
+	// Files are the input files in path order
+	Files     []BulkFileResult
+	Succeeded int
+	Failed    int
+	Duration  time.Duration
+}
+
+// Err returns the errors of the failed files joined, nil if none failed
+func (r *BulkReport) Err() error {
+	var errs []error
+	for _, file := range r.Files {
+		if file.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.Path, file.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// String returns a summary of the run followed by each failed file's error
+func (r *BulkReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "processed %d files in %v: %d succeeded, %d failed",
+		len(r.Files), r.Duration.Round(time.Millisecond), r.Succeeded, r.Failed)
+	for _, file := range r.Files {
+		if file.Err != nil {
+			fmt.Fprintf(&b, "\n  %s: %v", file.Path, file.Err)
+		}
+	}
+	return b.String()
+}
+
+// NewBulkProcessor returns a BulkProcessor running on client
+func NewBulkProcessor(client *Dragdropdo, options BulkOptions) *BulkProcessor {
+	if options.UploadConcurrency < 1 {
+		options.UploadConcurrency = DefaultBulkUploadConcurrency
+	}
+	if options.BatchSize < 1 {
+		options.BatchSize = 1
+	}
+	return &BulkProcessor{client: client, options: options, priorities: map[string]Priority{}}
+}
+
+// Prioritize sets the priority of the input file path, as listed in
+// BulkFileResult.Path, over BulkOptions.Priority. It may be called while
+// Run is in progress, e.g. to move a file a user is waiting on to the front
+// of the queue; it applies if the file has not started uploading, or its
+// operation has not been submitted.
+func (p *BulkProcessor) Prioritize(path string, priority Priority) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.priorities[path] = priority
+}
+
+// Run processes the files and reports the outcome for each. A file that
+// fails does not stop the others; Run only fails if the source cannot be
+// read or the destination created.
+func (p *BulkProcessor) Run(ctx context.Context) (*BulkReport, error) {
+	start := time.Now()
+	if p.options.Action == "" {
+		return nil, NewD3ValidationError("action is required", nil)
+	}
+	paths, err := p.inputFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(p.options.Destination, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	report := &BulkReport{Files: make([]BulkFileResult, len(paths))}
+	for i, path := range paths {
+		report.Files[i].Path = path
+	}
+	queue := p.uploadQueue(report.Files)
+	priorities := make([]Priority, len(queue))
+	for _, upload := range queue {
+		priorities[upload.index] = upload.priority
+	}
+	p.uploadAll(ctx, report.Files, queue)
+
+	run := &bulkRun{processor: p, files: report.Files, used: map[string]bool{}}
+	var wg sync.WaitGroup
+	manager := NewManager(p.client, p.options.Manager)
+	for _, batch := range p.batches(report.Files) {
+		priority := p.priority(report.Files[batch[0]].Path, priorities[batch[0]])
+		for _, index := range batch[1:] {
+			priority = max(priority, p.priority(report.Files[index].Path, priorities[index]))
+		}
+		op := manager.SubmitPriority(ctx, OperationOptions{
+			Action:     p.options.Action,
+			FileKeys:   run.fileKeys(batch),
+			Parameters: p.options.Parameters,
+			Notes:      p.options.Notes,
+		}, priority)
+		wg.Add(1)
+		go func(batch []int) {
+			defer wg.Done()
+			run.finish(ctx, batch, op)
+		}(batch)
+	}
+	wg.Wait()
+
+	for _, file := range report.Files {
+		if file.Err != nil {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// inputFiles lists the files of Source matching Pattern, in path order
+func (p *BulkProcessor) inputFiles() ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(p.options.Source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != p.options.Source && !p.options.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if p.options.Pattern != "" {
+			matched, err := filepath.Match(p.options.Pattern, entry.Name())
+			if err != nil {
+				return err
+			}
+			if !matched {
+				return nil
+			}
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source: %w", err)
+	}
+	return paths, nil
+}
+
+// bulkUpload is a file waiting in a BulkProcessor's upload queue
+type bulkUpload struct {
+	index    int
+	size     int64
+	priority Priority
+}
+
+// uploadQueue returns the uploads of files with their sizes and
+// BulkOptions.Priority. A file that cannot be stat'd keeps PriorityNormal;
+// its upload reports the error.
+func (p *BulkProcessor) uploadQueue(files []BulkFileResult) []bulkUpload {
+	queue := make([]bulkUpload, len(files))
+	for i, file := range files {
+		queue[i] = bulkUpload{index: i}
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			continue
+		}
+		queue[i].size = info.Size()
+		if p.options.Priority != nil {
+			queue[i].priority = p.options.Priority(file.Path, info)
+		}
+	}
+	return queue
+}
+
+// priority returns the priority of path: the one set with Prioritize, if
+// any, or else base
+func (p *BulkProcessor) priority(path string, base Priority) Priority {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if priority, ok := p.priorities[path]; ok {
+		return priority
+	}
+	return base
+}
+
+// nextUpload removes and returns the next upload of queue: the first in
+// UploadOrder of the highest priority
+func (p *BulkProcessor) nextUpload(files []BulkFileResult, queue *[]bulkUpload) (bulkUpload, bool) {
+	if len(*queue) == 0 {
+		return bulkUpload{}, false
+	}
+	uploads := *queue
+	next, nextPriority := 0, p.priority(files[uploads[0].index].Path, uploads[0].priority)
+	for i, upload := range uploads[1:] {
+		priority := p.priority(files[upload.index].Path, upload.priority)
+		if priority > nextPriority || priority == nextPriority && p.uploadsBefore(upload, uploads[next]) {
+			next, nextPriority = i+1, priority
+		}
+	}
+	upload := uploads[next]
+	*queue = append(uploads[:next], uploads[next+1:]...)
+	return upload, true
+}
+
+// uploadsBefore reports whether a is uploaded before b of equal priority
+func (p *BulkProcessor) uploadsBefore(a, b bulkUpload) bool {
+	switch {
+	case p.options.UploadOrder == UploadSmallestFirst && a.size != b.size:
+		return a.size < b.size
+	case p.options.UploadOrder == UploadLargestFirst && a.size != b.size:
+		return a.size > b.size
+	}
+	return a.index < b.index
+}
+
+// uploadAll uploads the files of queue, UploadConcurrency at a time, taking
+// the next from the queue as each finishes
+func (p *BulkProcessor) uploadAll(ctx context.Context, files []BulkFileResult, queue []bulkUpload) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := min(p.options.UploadConcurrency, len(queue))
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				upload, ok := p.nextUpload(files, &queue)
+				mu.Unlock()
+				if !ok {
+					return
+				}
+				file := &files[upload.index]
+				resp, err := p.client.UploadFileContext(ctx, UploadFileOptions{
+					File:     file.Path,
+					FileName: filepath.Base(file.Path),
+				})
+				if err != nil {
+					file.Err = err
+					continue
+				}
+				file.FileKey = resp.FileKey
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// batches groups the indexes of the uploaded files into batches of
+// BatchSize
+func (p *BulkProcessor) batches(files []BulkFileResult) [][]int {
+	var batches [][]int
+	var batch []int
+	for i, file := range files {
+		if file.Err != nil {
+			continue
+		}
+		batch = append(batch, i)
+		if len(batch) == p.options.BatchSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// bulkRun is the state of a BulkProcessor run shared by its operations
+type bulkRun struct {
+	processor *BulkProcessor
+	files     []BulkFileResult
+
+	// mu guards used, the output paths taken so far
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+func (r *bulkRun) fileKeys(batch []int) []FileKey {
+	keys := make([]FileKey, len(batch))
+	for i, index := range batch {
+		keys[i] = r.files[index].FileKey
+	}
+	return keys
+}
+
+// finish waits for the operation of batch and downloads its outputs,
+// recording the outcome in the batch's files
+func (r *bulkRun) finish(ctx context.Context, batch []int, op *ManagedOperation) {
+	results, err := op.Wait(ctx)
+	if job := op.Job(); job != nil {
+		for _, index := range batch {
+			r.files[index].MainTaskID = job.ID()
+		}
+	}
+	if results == nil {
+		for _, index := range batch {
+			r.files[index].Err = err
+		}
+		return
+	}
+
+	// Merge and zip produce one output for the whole batch
+	combined := r.processor.options.Action == ActionMerge || r.processor.options.Action == ActionZip
+	for _, result := range results {
+		targets := batch
+		if !combined {
+			targets = nil
+			for _, index := range batch {
+				if r.files[index].FileKey == result.FileKey {
+					targets = append(targets, index)
+				}
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+
+		if result.Status != StatusCompleted {
+			err := fmt.Errorf("operation %s: %s", result.Status, result.ErrorMessage)
+			for _, index := range targets {
+				r.files[index].Err = err
+			}
+			continue
+		}
+
+		dir := r.processor.options.Destination
+		if !combined {
+			dir = r.outputDir(r.files[targets[0]].Path)
+		}
+		dest, err := r.download(ctx, result, dir)
+		for _, index := range targets {
+			if err != nil {
+				r.files[index].Err = err
+			} else {
+				r.files[index].Outputs = append(r.files[index].Outputs, dest)
+			}
+		}
+	}
+
+	for _, index := range batch {
+		if file := &r.files[index]; file.Err == nil && len(file.Outputs) == 0 {
+			file.Err = fmt.Errorf("operation %s returned no output for %s", file.MainTaskID, file.FileKey)
+		}
+	}
+}
+
+// outputDir returns the directory the outputs of the input path go to,
+// mirroring its place under Source
+func (r *bulkRun) outputDir(path string) string {
+	rel, err := filepath.Rel(r.processor.options.Source, filepath.Dir(path))
+	if err != nil {
+		return r.processor.options.Destination
+	}
+	return filepath.Join(r.processor.options.Destination, rel)
+}
+
+// download writes result into dir under a name no other output of the run
+// has taken
+func (r *bulkRun) download(ctx context.Context, result Result, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	ext := filepath.Ext(result.Name)
+	dest := filepath.Join(dir, result.Name)
+	for n := 2; r.used[dest]; n++ {
+		dest = filepath.Join(dir, fmt.Sprintf("%s-%d%s", strings.TrimSuffix(result.Name, ext), n, ext))
+	}
+	r.used[dest] = true
+	r.mu.Unlock()
+
+	return result.DownloadContext(ctx, dest)
+}
@@ -0,0 +1,163 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBulkProcessor_InputFiles(t *testing.T) {
+	source := t.TempDir()
+	for _, name := range []string{"b.docx", "a.docx", "notes.txt", "sub/c.docx"} {
+		path := filepath.Join(source, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte("x"), 0o644)
+	}
+
+	p := NewBulkProcessor(nil, BulkOptions{Source: source, Pattern: "*.docx"})
+	files, err := p.inputFiles()
+	if err != nil {
+		t.Fatalf("Failed to list input files: %v", err)
+	}
+	want := []string{filepath.Join(source, "a.docx"), filepath.Join(source, "b.docx")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Expected %v, got %v", want, files)
+	}
+
+	p.options.Recursive = true
+	if files, _ = p.inputFiles(); len(files) != 3 {
+		t.Errorf("Expected 3 files with sub-directories, got %v", files)
+	}
+}
+
+func TestBulkProcessor_Batches(t *testing.T) {
+	p := NewBulkProcessor(nil, BulkOptions{BatchSize: 2})
+	files := []BulkFileResult{{}, {Err: errors.New("upload failed")}, {}, {}, {}}
+	want := [][]int{{0, 2}, {3, 4}}
+	if got := p.batches(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestBulkProcessor_UploadOrder(t *testing.T) {
+	source := t.TempDir()
+	files := make([]BulkFileResult, 4)
+	for i, name := range []string{"a.pdf", "b.pdf", "c.pdf", "d.pdf"} {
+		files[i].Path = filepath.Join(source, name)
+		os.WriteFile(files[i].Path, []byte(strings.Repeat("x", 10-i)), 0o644)
+	}
+
+	p := NewBulkProcessor(nil, BulkOptions{
+		UploadOrder: UploadSmallestFirst,
+		Priority: func(path string, info fs.FileInfo) Priority {
+			if info.Name() == "b.pdf" {
+				return PriorityInteractive
+			}
+			return PriorityNormal
+		},
+	})
+	queue := p.uploadQueue(files)
+	var order []string
+	for upload, ok := p.nextUpload(files, &queue); ok; upload, ok = p.nextUpload(files, &queue) {
+		order = append(order, filepath.Base(files[upload.index].Path))
+		if len(order) == 1 {
+			// A file a user opens jumps the queue while uploads are running
+			p.Prioritize(files[0].Path, PriorityInteractive)
+		}
+	}
+	if want := "b.pdf a.pdf d.pdf c.pdf"; strings.Join(order, " ") != want {
+		t.Errorf("Expected %q, got %q", want, strings.Join(order, " "))
+	}
+}
+
+func TestBulkProcessor_UploadAll(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			var body struct {
+				FileName string `json:"file_name"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			fmt.Fprintf(w, `{"data":{"file_key":"key-%s","upload_id":"upload-id","presigned_urls":["%s/part/%s"]}}`, body.FileName, server.URL, body.FileName)
+		case "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		default:
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			w.Header().Set("ETag", `"etag"`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	source := t.TempDir()
+	files := make([]BulkFileResult, 12)
+	for i := range files {
+		files[i].Path = filepath.Join(source, fmt.Sprintf("%02d.pdf", i))
+		os.WriteFile(files[i].Path, []byte(strings.Repeat("x", i+1)), 0o644)
+	}
+
+	p := NewBulkProcessor(client, BulkOptions{UploadConcurrency: 3, UploadOrder: UploadLargestFirst})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range files {
+			p.Prioritize(filepath.Join(source, fmt.Sprintf("%02d.pdf", i)), PriorityInteractive)
+		}
+	}()
+	p.uploadAll(context.Background(), files, p.uploadQueue(files))
+	<-done
+
+	for _, file := range files {
+		if file.Err != nil || file.FileKey != FileKey("key-"+filepath.Base(file.Path)) {
+			t.Errorf("Expected %s uploaded, got key %q and error %v", file.Path, file.FileKey, file.Err)
+		}
+	}
+	if peak != 3 {
+		t.Errorf("Expected 3 uploads at once, got %d", peak)
+	}
+}
+
+func TestBulkReport_String(t *testing.T) {
+	report := &BulkReport{
+		Files: []BulkFileResult{
+			{Path: "a.docx", Outputs: []string{"out/a.pdf"}},
+			{Path: "b.docx", Err: errors.New("corrupt input")},
+		},
+		Succeeded: 1,
+		Failed:    1,
+	}
+	if s := report.String(); !strings.Contains(s, "1 succeeded, 1 failed") || !strings.Contains(s, "b.docx: corrupt input") {
+		t.Errorf("Unexpected summary: %s", s)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "b.docx") {
+		t.Errorf("Expected the failure in Err, got %v", err)
+	}
+}
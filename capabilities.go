@@ -0,0 +1,104 @@
+package d3
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// bundledCatalogJSON is a snapshot of GET /v1/biz/supported-operations,
+// with updated_at set to when it was taken
+//
+//go:embed capabilities.json
+var bundledCatalogJSON []byte
+
+var (
+	bundledCatalogOnce sync.Once
+	bundledCatalog     *OperationCatalog
+)
+
+// BundledCatalog returns the snapshot of the operation catalog embedded in
+// the package, available without a network call. It may lag behind the
+// live catalog; see (*Dragdropdo).Capabilities. It must not be modified.
+func BundledCatalog() *OperationCatalog {
+	bundledCatalogOnce.Do(func() {
+		bundledCatalog = &OperationCatalog{}
+		if err := json.Unmarshal(bundledCatalogJSON, bundledCatalog); err != nil {
+			panic(fmt.Sprintf("d3: invalid bundled capabilities.json: %v", err))
+		}
+	})
+	return bundledCatalog
+}
+
+// Capabilities returns the operation catalog without a network call: the
+// one last fetched by GetSupportedOperations or RefreshCapabilities, even if
+// its cache expired, else BundledCatalog. Call RefreshCapabilities at
+// startup or periodically to converge on the live catalog. The result must
+// not be modified.
+func (c *Dragdropdo) Capabilities() *OperationCatalog {
+	c.catalog.mu.Lock()
+	defer c.catalog.mu.Unlock()
+	if c.catalog.catalog != nil {
+		return c.catalog.catalog
+	}
+	return BundledCatalog()
+}
+
+// RefreshCapabilities fetches the live operation catalog, replacing the one
+// Capabilities and GetSupportedOperations return. On failure the previous
+// catalog stays in use.
+func (c *Dragdropdo) RefreshCapabilities(ctx context.Context) error {
+	_, err := c.getSupportedOperations(ctx, true)
+	return err
+}
+
+// Validate checks an operation offline: that action is supported for ext,
+// that its required parameters are set and that enumerated parameters have
+// an accepted value. It returns a *D3ValidationError listing every problem,
+// wrapping ErrUnsupportedOperation when the action is not supported.
+func (c *OperationCatalog) Validate(ext string, action Action, parameters map[string]interface{}) error {
+	var v validation
+	spec, ok := c.Lookup(ext, action)
+	if !ok {
+		v.addErr("action", ErrUnsupportedOperation, fmt.Sprintf("action %q is not supported for .%s", action, normalizeExt(ext)))
+		return v.err()
+	}
+
+	names := make([]string, 0, len(spec.Parameters))
+	for name := range spec.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		param := spec.Parameters[name]
+		value, set := parameters[name]
+		if !set || value == nil || value == "" {
+			if param.Required {
+				v.add("parameters."+name, fmt.Sprintf("%s is required for %s", name, action))
+			}
+			continue
+		}
+		if len(param.Values) == 0 {
+			continue
+		}
+		s, _ := value.(string)
+		if !containsFold(param.Values, s) {
+			v.add("parameters."+name, fmt.Sprintf("%s must be one of %s for .%s, got %v", name, strings.Join(param.Values, ", "), normalizeExt(ext), value))
+		}
+	}
+	return v.err()
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBundledCatalog(t *testing.T) {
+	catalog := BundledCatalog()
+	if catalog.UpdatedAt.IsZero() {
+		t.Error("Expected the snapshot date to be set")
+	}
+	if !catalog.Supports("pdf", "compress") || !catalog.Supports("DOCX", "convert") {
+		t.Error("Expected common operations in the bundled catalog")
+	}
+	if catalog.Supports("docx", "lock") {
+		t.Error("Expected lock to be PDF only")
+	}
+}
+
+func TestOperationCatalog_Validate(t *testing.T) {
+	catalog := BundledCatalog()
+
+	if err := catalog.Validate(".docx", "convert", map[string]interface{}{"convert_to": "PDF"}); err != nil {
+		t.Errorf("Expected docx to pdf to be valid, got %v", err)
+	}
+	if err := catalog.Validate("pdf", "merge", nil); err != nil {
+		t.Errorf("Expected merge without parameters to be valid, got %v", err)
+	}
+
+	err := catalog.Validate("docx", "lock", map[string]interface{}{"password": "x"})
+	if !errors.Is(err, ErrUnsupportedOperation) || !IsD3ValidationError(err) {
+		t.Errorf("Expected ErrUnsupportedOperation, got %v", err)
+	}
+
+	err = catalog.Validate("pdf", "reset_password", map[string]interface{}{"old_password": "a"})
+	if err == nil || !strings.Contains(err.Error(), "new_password is required") {
+		t.Errorf("Expected a missing parameter error, got %v", err)
+	}
+
+	err = catalog.Validate("docx", "convert", map[string]interface{}{"convert_to": "mp4"})
+	if err == nil || !strings.Contains(err.Error(), "convert_to must be one of") || errors.Is(err, ErrUnsupportedOperation) {
+		t.Errorf("Expected an invalid value error, got %v", err)
+	}
+}
+
+func TestClient_RefreshCapabilities(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"data": {"extensions": {"epub": {"convert": {}}}}}`))
+	}))
+	defer server.Close()
+	client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+
+	if client.Capabilities() != BundledCatalog() {
+		t.Error("Expected the bundled catalog before any refresh")
+	}
+	if err := client.RefreshCapabilities(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	live := client.Capabilities()
+	if !live.Supports("epub", "convert") || live.Supports("pdf", "compress") {
+		t.Errorf("Expected the live catalog, got %v", live.Extensions)
+	}
+
+	fail = true
+	if err := client.RefreshCapabilities(context.Background()); err == nil {
+		t.Error("Expected the refresh to fail")
+	}
+	if client.Capabilities() != live {
+		t.Error("Expected the previous catalog to stay in use after a failed refresh")
+	}
+}
+
+func TestClient_CapabilitiesDuringRefresh(t *testing.T) {
+	stalled := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(stalled)
+		<-release
+		w.Write([]byte(`{"data": {"extensions": {"epub": {"convert": {}}}}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+
+	go client.RefreshCapabilities(context.Background())
+	<-stalled
+
+	done := make(chan *OperationCatalog)
+	go func() { done <- client.Capabilities() }()
+	select {
+	case catalog := <-done:
+		if catalog != BundledCatalog() {
+			t.Error("Expected the bundled catalog while the refresh is stalled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Capabilities to return while a refresh is stalled")
+	}
+}
@@ -0,0 +1,126 @@
+package d3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// catalogCacheTTL is how long GetSupportedOperations reuses a catalog
+const catalogCacheTTL = time.Hour
+
+// OperationCatalog is the complete matrix of supported operations: for each
+// file extension, the actions it supports and their parameters
+type OperationCatalog struct {
+	// Extensions maps a lower-case extension without the dot, e.g. "pdf",
+	// to its actions by name
+	Extensions map[string]map[Action]ActionSpec `json:"extensions"`
+	// UpdatedAt is when the catalog was fetched, or generated for the
+	// bundled snapshot
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// ActionSpec describes an action supported for an extension
+type ActionSpec struct {
+	// Parameters are the parameters the action accepts, by name
+	Parameters map[string]ParameterSpec `json:"parameters,omitempty"`
+}
+
+// ParameterSpec describes a parameter of an action
+type ParameterSpec struct {
+	// Type is the JSON type of the value, e.g. "string" or "number"
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Values lists the accepted values when they are enumerated, e.g. the
+	// target formats of convert_to
+	Values []string `json:"values,omitempty"`
+}
+
+// normalizeExt lower-cases ext and strips its leading dot
+func normalizeExt(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(ext, "."))
+}
+
+// ExtensionNames returns the extensions in the catalog, sorted
+func (c *OperationCatalog) ExtensionNames() []string {
+	names := make([]string, 0, len(c.Extensions))
+	for ext := range c.Extensions {
+		names = append(names, ext)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Actions returns the actions supported for ext (with or without the dot,
+// in any case), sorted
+func (c *OperationCatalog) Actions(ext string) []Action {
+	actions := c.Extensions[normalizeExt(ext)]
+	names := make([]Action, 0, len(actions))
+	for action := range actions {
+		names = append(names, action)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// Lookup returns the spec of action for ext and whether it is supported
+func (c *OperationCatalog) Lookup(ext string, action Action) (ActionSpec, bool) {
+	spec, ok := c.Extensions[normalizeExt(ext)][action]
+	return spec, ok
+}
+
+// Supports reports whether action is supported for ext
+func (c *OperationCatalog) Supports(ext string, action Action) bool {
+	_, ok := c.Lookup(ext, action)
+	return ok
+}
+
+// catalogCache holds the catalog fetched by GetSupportedOperations
+type catalogCache struct {
+	mu      sync.Mutex
+	catalog *OperationCatalog
+	expires time.Time
+}
+
+// GetSupportedOperations gets the complete catalog of supported operations
+// in one call, e.g. to render a picker of the actions and parameters
+// available for a file. The catalog is cached for an hour and shared with
+// clones; it must not be modified.
+func (c *Dragdropdo) GetSupportedOperations() (*OperationCatalog, error) {
+	return c.getSupportedOperations(context.Background(), false)
+}
+
+// getSupportedOperations returns the cached catalog unless it expired or
+// refresh is set, and fetches it otherwise. The lock is not held during the
+// fetch, so Capabilities keeps answering from the previous catalog.
+func (c *Dragdropdo) getSupportedOperations(ctx context.Context, refresh bool) (*OperationCatalog, error) {
+	c.catalog.mu.Lock()
+	cached, expires := c.catalog.catalog, c.catalog.expires
+	c.catalog.mu.Unlock()
+	if !refresh && cached != nil && time.Now().Before(expires) {
+		return cached, nil
+	}
+
+	resp, err := fetch[OperationCatalog](c.newRequest().SetContext(ctx), http.MethodGet, "/v1/biz/supported-operations")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get supported operations: %w", err)
+	}
+
+	catalog := &OperationCatalog{
+		Extensions: make(map[string]map[Action]ActionSpec, len(resp.Extensions)),
+		UpdatedAt:  time.Now(),
+	}
+	for ext, actions := range resp.Extensions {
+		catalog.Extensions[normalizeExt(ext)] = actions
+	}
+	c.catalog.mu.Lock()
+	defer c.catalog.mu.Unlock()
+	c.catalog.catalog = catalog
+	c.catalog.expires = time.Now().Add(catalogCacheTTL)
+	return catalog, nil
+}
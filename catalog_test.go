@@ -0,0 +1,61 @@
+package d3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_GetSupportedOperations(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/biz/supported-operations" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"data": {"extensions": {
+			"PDF": {
+				"compress": {"parameters": {"compression_value": {"type": "string", "required": true, "values": ["low", "extreme"]}}},
+				"lock": {"parameters": {"password": {"type": "string", "required": true}}}
+			},
+			"docx": {"convert": {"parameters": {"convert_to": {"type": "string", "values": ["pdf", "txt"]}}}}
+		}}}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	catalog, err := client.GetSupportedOperations()
+	if err != nil {
+		t.Fatalf("Failed to get supported operations: %v", err)
+	}
+
+	if got := catalog.ExtensionNames(); !reflect.DeepEqual(got, []string{"docx", "pdf"}) {
+		t.Errorf("Expected normalized extensions [docx pdf], got %v", got)
+	}
+	if got := catalog.Actions(".PDF"); !reflect.DeepEqual(got, []Action{ActionCompress, ActionLock}) {
+		t.Errorf("Expected pdf actions [compress lock], got %v", got)
+	}
+	spec, ok := catalog.Lookup("docx", "convert")
+	if !ok || !reflect.DeepEqual(spec.Parameters["convert_to"].Values, []string{"pdf", "txt"}) {
+		t.Errorf("Expected convert_to values for docx, got %+v", spec)
+	}
+	if !catalog.Supports("pdf", "lock") || catalog.Supports("docx", "lock") || catalog.Supports("mp4", "convert") {
+		t.Error("Unexpected Supports answers")
+	}
+	if !catalog.Extensions["pdf"]["compress"].Parameters["compression_value"].Required {
+		t.Error("Expected compression_value to be required")
+	}
+
+	// Cached, and shared with clones
+	client.GetSupportedOperations()
+	clone, err := client.Clone()
+	if err != nil {
+		t.Fatalf("Failed to clone: %v", err)
+	}
+	clone.GetSupportedOperations()
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
@@ -0,0 +1,46 @@
+package d3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// compositeETagPattern matches the ETag S3-compatible storage gives a
+// multipart object: the hex MD5 of the concatenated part MD5s and the number
+// of parts
+var compositeETagPattern = regexp.MustCompile(`^([0-9a-f]{32})-(\d+)$`)
+
+// compositeETag returns the multipart ETag expected for parts with the given
+// MD5 digests
+func compositeETag(sums [][md5.Size]byte) string {
+	h := md5.New()
+	for _, sum := range sums {
+		h.Write(sum[:])
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(h.Sum(nil)), len(sums))
+}
+
+// verifyCompositeETag compares the final object's ETag with the one expected
+// from the uploaded parts. ETags that are not in multipart form are not
+// checked, since storage computes them differently (e.g. with SSE-KMS).
+func verifyCompositeETag(fileKey FileKey, etag string, sums [][md5.Size]byte) error {
+	etag = strings.ToLower(strings.Trim(etag, `"`))
+	if !compositeETagPattern.MatchString(etag) {
+		return nil
+	}
+
+	expected := compositeETag(sums)
+	if etag == expected {
+		return nil
+	}
+	e := newUploadError(ErrChecksumMismatch, fmt.Sprintf("uploaded object %s has ETag %s, expected %s", fileKey, etag, expected))
+	e.Details = map[string]interface{}{
+		"file_key": string(fileKey),
+		"etag":     etag,
+		"expected": expected,
+	}
+	return e
+}
@@ -0,0 +1,82 @@
+package d3
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompositeETag(t *testing.T) {
+	sums := [][md5.Size]byte{md5.Sum([]byte("hello")), md5.Sum([]byte("world"))}
+	etag := compositeETag(sums)
+	if !compositeETagPattern.MatchString(etag) || !strings.HasSuffix(etag, "-2") {
+		t.Errorf("Expected a two-part composite ETag, got %s", etag)
+	}
+	if err := verifyCompositeETag("file-key-123", `"`+strings.ToUpper(etag)+`"`, sums); err != nil {
+		t.Errorf("Expected quoted, upper-case ETag to match, got %v", err)
+	}
+	if err := verifyCompositeETag("file-key-123", "not-a-multipart-etag", sums); err != nil {
+		t.Errorf("Expected non-multipart ETag to be skipped, got %v", err)
+	}
+}
+
+func TestClient_UploadFile_VerifiesETag(t *testing.T) {
+	content := strings.Repeat("d", 2000)
+	tmpFile := filepath.Join(t.TempDir(), "verify.bin")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	good := compositeETag([][md5.Size]byte{md5.Sum([]byte(content[:1000])), md5.Sum([]byte(content[1000:]))})
+
+	finalETag := good
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1", server.URL + "/part/2"},
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"file_key": "file-key-123", "etag": `"` + finalETag + `"`},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, ChunkSize: 1000})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "verify.bin"}); err != nil {
+		t.Fatalf("Expected matching ETag to pass, got %v", err)
+	}
+
+	finalETag = "0123456789abcdef0123456789abcdef-2"
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "verify.bin"})
+	var uploadErr *D3UploadError
+	if !errors.As(err, &uploadErr) || !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+
+	unchecked, err := NewClient("test-key", WithConfig(Config{BaseURL: server.URL, ChunkSize: 1000}), WithoutETagVerification())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := unchecked.UploadFile(UploadFileOptions{File: tmpFile, FileName: "verify.bin"}); err != nil {
+		t.Errorf("Expected verification to be skipped, got %v", err)
+	}
+}
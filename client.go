@@ -1,44 +1,229 @@
 package d3
 
 import (
-	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"log/slog"
 	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/go-resty/resty/v2"
 )
 
-// Dragdropdo represents a D3 API client
+// Dragdropdo represents a D3 API client. It is safe for concurrent use,
+// including the Set* methods and hook registration while requests are in
+// flight.
 type Dragdropdo struct {
-	apiKey   string
-	baseURL  string
-	timeout  time.Duration
-	headers  map[string]string
-	httpClient *resty.Client
+	credentialsMu sync.RWMutex
+	credentials   CredentialsProvider
+	authScheme    AuthScheme
+	signer        RequestSigner
+
+	// settingsMu guards timeout and headers, which can be changed at runtime;
+	// headers is replaced rather than modified
+	settingsMu sync.RWMutex
+	timeout    time.Duration
+	headers    map[string]string
+
+	baseURL    string
+	endpoints  *endpointPool
+	userAgent  string
+	transport  Transport
+	apiClient  *http.Client
+	partClient *http.Client
+	retry      RetryPolicy
+	life       *lifecycle
+
+	chunkSize         int64
+	uploadConcurrency int
+	uploadAttempts    int
+	stateStore        StateStore
+	uploadCache       UploadCache
+	encryption        EncryptionProvider
+	sseHeaders        map[string]string
+	debugLogger       *log.Logger
+	logger            *slog.Logger
+	metrics           MetricsRecorder
+	preflight         *preflight
+	catalog           *catalogCache
+	verifyETags       bool
+	passwordPolicy    PasswordPolicy
+	fileSizeLimit     int64
+	planLimit         *sizeLimit
+	dryRun            bool
+	onSchemaDrift     func(SchemaDrift)
+
+	hooksMu     sync.RWMutex
+	beforeHooks []BeforeRequestHook
+	afterHooks  []AfterResponseHook
+	errorHooks  []ErrorHook
 }
 
 // Config represents client configuration
 type Config struct {
-	APIKey  string
+	APIKey string
+	// BaseURL is the base URL of the D3 API. It overrides Environment.
 	BaseURL string
+	// Environment selects the base URL when BaseURL is empty (default:
+	// EnvironmentProduction)
+	Environment Environment
+	// Timeout bounds each API request attempt from connect to the end of the
+	// response body (default: 30s). It does not apply to part uploads.
 	Timeout time.Duration
+	// DialTimeout bounds establishing a TCP connection, for API calls and
+	// part uploads alike (default: 30s)
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake (default: 10s)
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for response headers once the
+	// request has been sent (default: none)
+	ResponseHeaderTimeout time.Duration
+	// UploadPartTimeout bounds each presigned part upload, which can
+	// legitimately take much longer than an API call (default: none)
+	UploadPartTimeout time.Duration
+	// Headers are sent with every API request
 	Headers map[string]string
+	// Credentials supplies the API key per request instead of APIKey, e.g.
+	// from a secrets manager
+	Credentials CredentialsProvider
+	// AuthScheme selects how the API key is sent (default: AuthBearer)
+	AuthScheme AuthScheme
+	// Signer signs every API request attempt, e.g. with an HMACSigner
+	// (default: none)
+	Signer RequestSigner
+	// FallbackBaseURLs are tried in order when BaseURL fails with a network
+	// error or 502/503/504. The client sticks to whichever endpoint works.
+	FallbackBaseURLs []string
+	// FailoverCooldown is how long a failed endpoint is skipped (default: 30s)
+	FailoverCooldown time.Duration
+	// HTTPClient is used for API calls and presigned part uploads. It is
+	// copied before use, so later changes to it have no effect.
+	HTTPClient *http.Client
+	// Transport overrides the transport of HTTPClient (or of the default
+	// client), e.g. for proxies, instrumentation or test doubles
+	Transport http.RoundTripper
+	// CustomTransport carries API requests and part uploads instead of
+	// HTTP, e.g. through a relay or a sidecar; the HTTP settings then only
+	// apply to downloading outputs (default: HTTP)
+	CustomTransport Transport
+	// ProxyURL routes API calls and part uploads through an http, https or
+	// socks5 proxy. When empty, HTTP_PROXY/HTTPS_PROXY/NO_PROXY apply.
+	ProxyURL string
+	// TLSConfig sets root CAs, client certificates (mTLS), minimum version
+	// etc. for API calls and part uploads
+	TLSConfig *tls.Config
+	// APIConnections tunes the connections used for API calls
+	APIConnections ConnectionOptions
+	// UploadConnections tunes the connections used for presigned part
+	// uploads, separately from API calls
+	UploadConnections ConnectionOptions
+	// Retry configures retries of failed API calls. Retries are disabled
+	// unless Retry.MaxAttempts is greater than one.
+	Retry RetryPolicy
+	// UserAgentSuffix is appended to the default User-Agent to identify the
+	// application, e.g. "my-app/2.3"
+	UserAgentSuffix string
+	// ChunkSize is the target part size used to calculate the number of
+	// parts when UploadFileOptions.Parts is not set (default: 5MB)
+	ChunkSize int64
+	// UploadConcurrency is the number of parts uploaded in parallel
+	// (default: 1)
+	UploadConcurrency int
+	// UploadAttempts is the number of times UploadFile runs the whole
+	// upload, with a fresh initiate-upload, when it fails transiently
+	// before any part was uploaded (default: 1, no retries)
+	UploadAttempts int
+	// StateStore records the progress of uploads and submitted operations
+	// so they can be resumed after a crash or restart (default: none)
+	StateStore StateStore
+	// UploadCache lets UploadFile return the file key of identical content
+	// uploaded earlier instead of uploading it again (default: none)
+	UploadCache UploadCache
+	// Encryption encrypts files before they are uploaded and decrypts
+	// outputs as they are downloaded through Result (default: none)
+	Encryption EncryptionProvider
+	// SSECustomerKey is sent with part uploads and complete-upload for
+	// storage encrypting with customer-provided keys; it turns off ETag
+	// verification, as such ETags are not MD5-based (default: none)
+	SSECustomerKey *SSECustomerKey
+	// MaxFileSize rejects larger files in UploadFile before anything is
+	// sent, in bytes (default: no limit)
+	MaxFileSize int64
+	// CheckPlanFileSize looks up the plan's maximum upload size from the
+	// usage endpoint, once, when MaxFileSize is not set
+	CheckPlanFileSize bool
+	// DisableETagVerification skips comparing the ETag returned by
+	// complete-upload with the MD5-of-MD5s of the parts sent. Storage
+	// encrypting with customer keys may report ETags in the same form that
+	// are not MD5-based.
+	DisableETagVerification bool
+	// Debug logs every request's method, path, status, latency and truncated
+	// bodies, with credentials, URL signatures and passwords redacted
+	Debug bool
+	// DebugLogger receives debug output (default: stderr)
+	DebugLogger *log.Logger
+	// Logger receives structured events such as upload.part.completed,
+	// operation.submitted and poll.tick (default: no logging)
+	Logger *slog.Logger
+	// Metrics receives request, upload and polling metrics (default: none)
+	Metrics MetricsRecorder
+	// CurlWriter receives a sanitized curl command for each API request
+	// attempt, for reproducing failures outside the client (default: none)
+	CurlWriter io.Writer
+	// PasswordPolicy constrains passwords set by LockPdf and
+	// ResetPdfPassword (default: DefaultPasswordPolicy)
+	PasswordPolicy *PasswordPolicy
+	// PreflightValidation checks, before submitting an operation, that the
+	// extensions of files uploaded through the client support the action,
+	// using cached CheckSupportedOperation answers
+	PreflightValidation bool
+	// DryRun validates calls and builds their requests without sending
+	// them: the first API request of each call is returned in a
+	// *D3DryRunError instead, for rehearsing pipelines safely
+	DryRun bool
+	// OnSchemaDrift is called when a successful API response has fields the
+	// client does not decode or lacks fields it expects, to detect API
+	// changes before they break decoding silently (default: not checked)
+	OnSchemaDrift func(SchemaDrift)
 }
 
 // UploadFileOptions represents options for file upload
 type UploadFileOptions struct {
-	File      string
-	FileName  string
-	MimeType  string
-	Parts     int
+	File       string
+	FileName   string
+	MimeType   string
+	Parts      int
+	FolderID   string
 	OnProgress func(UploadProgress)
+	// Progress follows the upload alongside OnProgress
+	Progress ProgressReporter
+	// OnPartStart, OnPartComplete and OnPartRetry follow each part upload
+	// attempt, for per-part telemetry. With UploadConcurrency above 1 they
+	// are called concurrently from the upload's workers.
+	OnPartStart    func(UploadPartInfo)
+	OnPartComplete func(UploadPartInfo)
+	OnPartRetry    func(UploadPartInfo)
+	// OnComplete is called once the upload has been completed
+	OnComplete func(UploadCompleteInfo)
+	// DryRun returns the initiate-upload request in a *D3DryRunError
+	// instead of uploading, see Config.DryRun
+	DryRun bool
+	// Headers are sent with the upload's API calls, over the client defaults
+	Headers map[string]string
+	// RequestTimeout overrides the client timeout for the upload's API calls
+	RequestTimeout time.Duration
+	// SkipCache uploads the file even if Config.UploadCache knows its
+	// content
+	SkipCache bool
 }
 
 // UploadProgress represents upload progress information
@@ -50,72 +235,131 @@ type UploadProgress struct {
 	Percentage    int
 }
 
+// UploadPartInfo describes a part upload attempt to the part hooks of
+// UploadFileOptions
+type UploadPartInfo struct {
+	FileKey    FileKey
+	PartNumber int
+	TotalParts int
+	// Size is the part's size in bytes
+	Size int64
+	// Attempt is 1 for the first attempt, 2 for the retry after a presigned
+	// URL expired
+	Attempt int
+	// Duration is how long the failed attempt took for OnPartRetry, and how
+	// long the part took, across attempts, for OnPartComplete
+	Duration time.Duration
+	// ETag is storage's ETag for the part, set for OnPartComplete unless
+	// the part failed
+	ETag string
+	// Err is why the attempt failed, for OnPartRetry, or why the part
+	// failed, for OnPartComplete
+	Err error
+}
+
+// UploadCompleteInfo describes a completed upload to OnComplete
+type UploadCompleteInfo struct {
+	FileKey  FileKey
+	FileName string
+	Size     int64
+	Parts    int
+	// Duration is how long the successful attempt took, from initiating
+	// the upload to completing it
+	Duration time.Duration
+	// ETag is the completed object's ETag, if storage reports one
+	ETag string
+}
+
 // UploadResponse represents response from file upload
 type UploadResponse struct {
-	FileKey       string   `json:"file_key"`
-	UploadID      string   `json:"upload_id"`
+	FileKey       FileKey  `json:"file_key"`
+	UploadID      UploadID `json:"upload_id"`
 	PresignedURLs []string `json:"presigned_urls"`
 	ObjectName    string   `json:"object_name,omitempty"`
-	// CamelCase aliases for compatibility
-	FileKeyAlias       string   `json:"fileKey,omitempty"`
-	UploadIDAlias      string   `json:"uploadId,omitempty"`
-	PresignedURLsAlias []string `json:"presignedUrls,omitempty"`
-	ObjectNameAlias    string   `json:"objectName,omitempty"`
+
+	// Deprecated: use FileKey. The JSON encoding includes camelCase keys.
+	FileKeyAlias FileKey `json:"-"`
+	// Deprecated: use UploadID
+	UploadIDAlias UploadID `json:"-"`
+	// Deprecated: use PresignedURLs
+	PresignedURLsAlias []string `json:"-"`
+	// Deprecated: use ObjectName
+	ObjectNameAlias string `json:"-"`
+}
+
+// completeUploadResponse is the data of a complete upload response
+type completeUploadResponse struct {
+	Message string  `json:"message"`
+	FileKey FileKey `json:"file_key"`
+	ETag    string  `json:"etag"`
 }
 
 // SupportedOperationOptions represents options for checking supported operations
 type SupportedOperationOptions struct {
-	Ext        string
-	Action     string
-	Parameters map[string]interface{}
+	Ext            string
+	Action         Action
+	Parameters     map[string]interface{}
+	Headers        map[string]string
+	RequestTimeout time.Duration
 }
 
 // SupportedOperationResponse represents response from supported operation check
 type SupportedOperationResponse struct {
-	Supported       bool                   `json:"supported"`
-	Ext             string                 `json:"ext"`
-	Action          string                 `json:"action,omitempty"`
-	AvailableActions []string              `json:"available_actions,omitempty"`
-	Parameters      map[string]interface{} `json:"parameters,omitempty"`
+	Supported        bool                   `json:"supported"`
+	Ext              string                 `json:"ext"`
+	Action           Action                 `json:"action,omitempty"`
+	AvailableActions []Action               `json:"available_actions,omitempty"`
+	Parameters       map[string]interface{} `json:"parameters,omitempty"`
 }
 
 // OperationOptions represents options for creating an operation
 type OperationOptions struct {
-	Action     string
-	FileKeys   []string
-	Parameters map[string]interface{}
-	Notes      map[string]string
+	Action         Action
+	FileKeys       []FileKey
+	Parameters     map[string]interface{}
+	Notes          map[string]string
+	Headers        map[string]string
+	RequestTimeout time.Duration
+	// DryRun returns the operation request in a *D3DryRunError instead of
+	// submitting it, see Config.DryRun
+	DryRun bool
 }
 
 // OperationResponse represents response from operation creation
 type OperationResponse struct {
-	MainTaskID string `json:"main_task_id"`
-	// CamelCase alias
-	MainTaskIDAlias string `json:"mainTaskId,omitempty"`
+	MainTaskID MainTaskID `json:"main_task_id"`
+
+	// Deprecated: use MainTaskID. The JSON encoding includes camelCase keys.
+	MainTaskIDAlias MainTaskID `json:"-"`
 }
 
 // StatusOptions represents options for getting status
 type StatusOptions struct {
-	MainTaskID string
-	FileTaskID string
+	MainTaskID     MainTaskID
+	FileTaskID     FileTaskID
+	Headers        map[string]string
+	RequestTimeout time.Duration
 }
 
 // FileTaskStatus represents status of a file task
 type FileTaskStatus struct {
-	FileKey      string `json:"file_key"`
-	Status       string `json:"status"`
-	DownloadLink string `json:"download_link,omitempty"`
-	ErrorCode    string `json:"error_code,omitempty"`
-	ErrorMessage string `json:"error_message,omitempty"`
+	FileKey      FileKey `json:"file_key"`
+	Status       string  `json:"status"`
+	DownloadLink string  `json:"download_link,omitempty"`
+	ErrorCode    string  `json:"error_code,omitempty"`
+	ErrorMessage string  `json:"error_message,omitempty"`
 }
 
 // StatusResponse represents response from status check
 type StatusResponse struct {
 	OperationStatus string           `json:"operation_status"`
 	FilesData       []FileTaskStatus `json:"files_data"`
-	// CamelCase aliases
-	OperationStatusAlias string           `json:"operationStatus,omitempty"`
-	FilesDataAlias       []FileTaskStatus `json:"filesData,omitempty"`
+
+	// Deprecated: use OperationStatus. The JSON encoding includes camelCase
+	// keys.
+	OperationStatusAlias string `json:"-"`
+	// Deprecated: use FilesData
+	FilesDataAlias []FileTaskStatus `json:"-"`
 }
 
 // PollStatusOptions represents options for polling status
@@ -124,69 +368,292 @@ type PollStatusOptions struct {
 	Interval time.Duration
 	Timeout  time.Duration
 	OnUpdate func(StatusResponse)
+	// Progress follows the files of the operation as they finish
+	Progress ProgressReporter
 }
 
 // NewDragdropdo creates a new Dragdropdo Client instance
 func NewDragdropdo(config Config) (*Dragdropdo, error) {
-	if config.APIKey == "" {
-		return nil, errors.New("API key is required")
+	credentials := config.Credentials
+	if credentials == nil {
+		if config.APIKey == "" {
+			return nil, errors.New("API key is required")
+		}
+		credentials = StaticCredentials(config.APIKey)
+	}
+	if err := config.AuthScheme.validate(); err != nil {
+		return nil, err
+	}
+	if config.SSECustomerKey != nil {
+		if err := config.SSECustomerKey.validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	baseURL := config.BaseURL
 	if baseURL == "" {
-		baseURL = "https://api-dev.dragdropdo.com"
+		var err error
+		if baseURL, err = config.Environment.BaseURL(); err != nil {
+			return nil, err
+		}
 	}
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
-	timeout := config.Timeout
-	if timeout == 0 {
-		timeout = 30 * time.Second
+	baseURLs := []string{baseURL}
+	for _, fallback := range config.FallbackBaseURLs {
+		baseURLs = append(baseURLs, strings.TrimSuffix(fallback, "/"))
+	}
+
+	timeout := durationOr(config.Timeout, DefaultTimeout)
+
+	chunkSize := config.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
 	}
 
+	uploadConcurrency := config.UploadConcurrency
+	if uploadConcurrency < 1 {
+		uploadConcurrency = DefaultUploadConcurrency
+	}
+
+	ua := userAgent(config.UserAgentSuffix)
 	headers := map[string]string{
-		"Content-Type":  "application/json",
-		"Authorization": fmt.Sprintf("Bearer %s", config.APIKey),
+		"Content-Type": "application/json",
+		"User-Agent":   ua,
 	}
 	for k, v := range config.Headers {
 		headers[k] = v
 	}
 
-	httpClient := resty.New().
-		SetBaseURL(baseURL).
-		SetTimeout(timeout).
-		SetHeaders(headers)
+	transport, err := newHTTPTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	apiTransport, err := tuneTransport(transport, config.APIConnections, config.DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	partTransport, err := tuneTransport(transport, config.UploadConnections, config.DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	baseHTTPClient := &http.Client{}
+	if config.HTTPClient != nil {
+		copied := *config.HTTPClient
+		baseHTTPClient = &copied
+	}
+	baseHTTPClient.Transport = apiTransport
+	// Part uploads are not bound by the API timeout; large parts on slow
+	// links legitimately take longer
+	partClient := *baseHTTPClient
+	partClient.Transport = partTransport
+	if config.UploadPartTimeout > 0 {
+		partClient.Timeout = config.UploadPartTimeout
+	}
+	wire := config.CustomTransport
+	if wire == nil {
+		wire = &httpTransport{api: newAPIBackend(baseHTTPClient), parts: &partClient}
+	}
+
+	client := &Dragdropdo{
+		credentials: credentials,
+		authScheme:  config.AuthScheme,
+		signer:      config.Signer,
+		baseURL:     baseURL,
+		endpoints:   newEndpointPool(baseURLs, config.FailoverCooldown),
+		timeout:     timeout,
+		headers:     headers,
+		userAgent:   ua,
+		transport:   wire,
+		apiClient:   baseHTTPClient,
+		partClient:  &partClient,
+		retry:       config.Retry,
+		life:        newLifecycle(),
+
+		chunkSize:         chunkSize,
+		uploadConcurrency: uploadConcurrency,
+		uploadAttempts:    config.UploadAttempts,
+		stateStore:        config.StateStore,
+		uploadCache:       config.UploadCache,
+		encryption:        config.Encryption,
+		verifyETags:       !config.DisableETagVerification && config.SSECustomerKey == nil,
+		logger:            config.Logger,
+		metrics:           config.Metrics,
+		dryRun:            config.DryRun,
+		onSchemaDrift:     config.OnSchemaDrift,
+		catalog:           &catalogCache{},
+	}
+	if config.PreflightValidation {
+		client.preflight = newPreflight()
+	}
+	if config.SSECustomerKey != nil {
+		client.sseHeaders = config.SSECustomerKey.headers()
+	}
+	if config.MaxFileSize > 0 {
+		client.fileSizeLimit = config.MaxFileSize
+	} else if config.CheckPlanFileSize {
+		client.planLimit = &sizeLimit{}
+	}
+	client.passwordPolicy = DefaultPasswordPolicy
+	if config.PasswordPolicy != nil {
+		client.passwordPolicy = *config.PasswordPolicy
+	}
 
-	return &Dragdropdo{
-		apiKey:     config.APIKey,
-		baseURL:    baseURL,
-		timeout:    timeout,
-		headers:    headers,
-		httpClient: httpClient,
-	}, nil
+	if config.Debug {
+		client.debugLogger = newDebugLogger(config.DebugLogger)
+		client.OnAfterResponse(debugHook(client.debugLogger))
+	}
+	if config.Metrics != nil {
+		client.OnAfterResponse(metricsHook(config.Metrics))
+	}
+	if config.CurlWriter != nil {
+		client.OnAfterResponse(curlHook(config.CurlWriter))
+	}
+
+	return client, nil
 }
 
 // UploadFile uploads a file to D3 storage
 func (c *Dragdropdo) UploadFile(options UploadFileOptions) (*UploadResponse, error) {
+	return c.UploadFileContext(context.Background(), options)
+}
+
+// UploadFileContext uploads a file to D3 storage, stopping when ctx is done.
+// Cancelling stops the part uploads in flight, aborts the multipart upload
+// and returns a *D3UploadError wrapping ctx's error that tells how many parts
+// had completed.
+func (c *Dragdropdo) UploadFileContext(parent context.Context, options UploadFileOptions) (_ *UploadResponse, err error) {
+	var v validation
+	fileName := SanitizeFileName(options.FileName)
 	if options.FileName == "" {
-		return nil, errors.New("file_name is required")
+		v.add("file_name", "file_name is required")
+	} else if fileName == "" {
+		v.add("file_name", "file_name has no valid characters")
+	}
+	fileInfo, statErr := os.Stat(options.File)
+	if statErr != nil {
+		v.addErr("file", fmt.Errorf("%w: %w", ErrFileNotFound, statErr), fmt.Sprintf("file not found: %v", statErr))
+	}
+	if options.Parts < 0 || options.Parts > maxParts {
+		v.add("parts", fmt.Sprintf("parts must be between 1 and %d", maxParts))
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if fileName != options.FileName {
+		c.logDebug("upload.file_name_sanitized",
+			slog.String("file_name", options.FileName),
+			slog.String("sanitized", fileName))
+		options.FileName = fileName
+	}
+
+	progress := startProgress(options.Progress, Progress{Stage: ProgressUpload, Name: options.FileName, Total: fileInfo.Size()})
+	defer func() { progress.finish(err) }()
+	if progress != nil {
+		onProgress := options.OnProgress
+		options.OnProgress = func(p UploadProgress) {
+			progress.update(p.BytesUploaded, p.TotalBytes, "")
+			if onProgress != nil {
+				onProgress(p)
+			}
+		}
 	}
 
-	fileInfo, err := os.Stat(options.File)
+	if options.DryRun {
+		parent = withDryRun(parent)
+	}
+	ctx, done, err := c.life.beginOperation(parent)
 	if err != nil {
-		return nil, fmt.Errorf("file not found: %w", err)
+		return nil, err
+	}
+	defer done()
+
+	fileSize := fileInfo.Size()
+	if err := c.checkFileSize(ctx, options.FileName, fileSize); err != nil {
+		return nil, err
+	}
+
+	var cacheKey string
+	if c.uploadCache != nil && !options.SkipCache && !c.isDryRun(ctx) {
+		var cached *UploadResponse
+		if cached, cacheKey = c.cachedUpload(ctx, options); cached != nil {
+			return cached, nil
+		}
+	}
+
+	if c.encryption != nil && !c.isDryRun(ctx) {
+		encrypted, err := c.encryptFile(options.File)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(encrypted)
+		if fileInfo, err = os.Stat(encrypted); err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", options.File, err)
+		}
+		options.File = encrypted
+	}
+
+	for attempt := 1; ; attempt++ {
+		var state uploadAttempt
+		resp, err := c.uploadOnce(parent, ctx, options, fileInfo, &state)
+		if err == nil && cacheKey != "" {
+			if err := c.uploadCache.Put(ctx, cacheKey, resp.FileKey); err != nil {
+				c.uploadCacheFailed(err)
+			}
+		}
+		if err == nil || state.partsDone > 0 || attempt >= c.uploadAttempts || ctx.Err() != nil || !IsRetryable(err) {
+			return resp, err
+		}
+
+		// Nothing was stored; abandon the multipart upload and start over
+		if state.initiated != nil {
+			c.abortUpload(ctx, *state.initiated)
+			state.recorder.forget()
+		}
+		wait := c.retry.backoff(attempt)
+		c.logInfo("upload.retry",
+			slog.String("file_name", options.FileName),
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+			slog.String("error", err.Error()))
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, newUploadError(err, fmt.Sprintf("upload cancelled while waiting to retry: %v", err))
+		}
 	}
+}
+
+// uploadAttempt records how far one run of the upload flow got
+type uploadAttempt struct {
+	// initiated is the multipart upload started, nil if none was
+	initiated *AbortUploadOptions
+	// partsDone is the number of parts uploaded, including those stored by
+	// an earlier run that was resumed
+	partsDone int
+	// recorder keeps the progress in the StateStore, nil without one
+	recorder *uploadRecorder
+}
+
+// uploadOnce runs the upload flow once, from initiate-upload to
+// complete-upload, recording its progress in state. An upload recorded in
+// the StateStore is resumed instead of initiated.
+func (c *Dragdropdo) uploadOnce(parent, ctx context.Context, options UploadFileOptions, fileInfo os.FileInfo, state *uploadAttempt) (*UploadResponse, error) {
+	began := time.Now()
 	fileSize := fileInfo.Size()
 
 	// Calculate parts if not provided
-	chunkSize := int64(5 * 1024 * 1024) // 5MB per part
+	chunkSize := c.chunkSize
 	calculatedParts := options.Parts
 	if calculatedParts == 0 {
 		calculatedParts = int((fileSize + chunkSize - 1) / chunkSize)
 	}
-	if calculatedParts > 100 {
-		calculatedParts = 100
+	if calculatedParts > maxParts {
+		calculatedParts = maxParts
 	}
-	if calculatedParts < 1 {
+	// Empty files and files no larger than a chunk go as a single part;
+	// storage rejects parts other than the last below the minimum part size
+	if calculatedParts < 1 || fileSize <= chunkSize {
 		calculatedParts = 1
 	}
 
@@ -203,158 +670,395 @@ func (c *Dragdropdo) UploadFile(options UploadFileOptions) (*UploadResponse, err
 		}
 	}
 
-	// Step 1: Request presigned URLs
-	var uploadResp struct {
-		Data struct {
-			FileKey       string   `json:"file_key"`
-			UploadID      string   `json:"upload_id"`
-			PresignedURLs []string `json:"presigned_urls"`
-			ObjectName    string   `json:"object_name"`
-		} `json:"data"`
+	// Step 1: Request presigned URLs, or resume an upload cut short
+
+	var uploadResp *UploadResponse
+	var err error
+	saved := c.loadUpload(ctx, options, fileInfo)
+	if saved != nil {
+		if uploadResp, err = c.resumeUpload(ctx, options, saved); err != nil {
+			// The upload may have been aborted or expired meanwhile
+			c.logInfo("upload.resume_failed",
+				slog.String("file_key", string(saved.FileKey)),
+				slog.String("error", err.Error()))
+			c.stateFailed("delete_upload", c.stateStore.DeleteUpload(ctx, saved.File))
+			saved = nil
+		} else {
+			calculatedParts = saved.Parts
+			c.logInfo("upload.resumed",
+				slog.String("file_key", string(saved.FileKey)),
+				slog.Int("parts_stored", len(saved.Completed)),
+				slog.Int("parts", saved.Parts))
+		}
 	}
 
-	_, err = c.httpClient.R().
-		SetBody(map[string]interface{}{
+	if saved == nil {
+		initBody := map[string]interface{}{
 			"file_name": options.FileName,
 			"size":      fileSize,
 			"mime_type": detectedMimeType,
 			"parts":     calculatedParts,
-		}).
-		SetResult(&uploadResp).
-		Post("/v1/biz/initiate-upload")
+		}
+		if options.FolderID != "" {
+			initBody["folder_id"] = options.FolderID
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to request presigned URLs: %w", err)
+		req := c.newRequest().
+			SetContext(ctx).
+			SetHeaders(options.Headers).
+			SetTimeout(options.RequestTimeout).
+			SetBody(initBody)
+		uploadResp, err = fetch[UploadResponse](req, http.MethodPost, "/v1/biz/initiate-upload")
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to request presigned URLs: %w", err)
+		}
 	}
 
-	fileKey := uploadResp.Data.FileKey
-	uploadID := uploadResp.Data.UploadID
-	presignedURLs := uploadResp.Data.PresignedURLs
-	objectName := uploadResp.Data.ObjectName
+	fileKey := uploadResp.FileKey
+	uploadID := uploadResp.UploadID
+	presignedURLs := uploadResp.PresignedURLs
+	objectName := uploadResp.ObjectName
 
+	// The server may apply its own part policy; adopt its part count as
+	// long as every part gets at least one byte
 	if len(presignedURLs) != calculatedParts {
-		return nil, fmt.Errorf("mismatch: requested %d parts but received %d presigned URLs", calculatedParts, len(presignedURLs))
+		if !partsFit(fileSize, len(presignedURLs)) {
+			return nil, newUploadError(ErrPresignedURLMismatch, fmt.Sprintf("mismatch: requested %d parts but received %d presigned URLs, which cannot split %d bytes", calculatedParts, len(presignedURLs), fileSize))
+		}
+		c.logInfo("upload.parts_renegotiated",
+			slog.String("file_key", string(fileKey)),
+			slog.Int("requested_parts", calculatedParts),
+			slog.Int("parts", len(presignedURLs)))
+		calculatedParts = len(presignedURLs)
 	}
 
 	if uploadID == "" {
-		return nil, errors.New("upload ID not received from server")
+		return nil, newUploadError(ErrMissingUploadID, ErrMissingUploadID.Error())
 	}
 
+	// Until completed, the upload is aborted by Close if it is cut short
+	upload := AbortUploadOptions{FileKey: fileKey, UploadID: uploadID, ObjectName: objectName}
+	state.initiated = &upload
+	var completed []UploadedPart
+	if saved != nil {
+		completed = saved.Completed
+	}
+	state.recorder = c.recordUpload(ctx, options, fileInfo, upload, calculatedParts, completed)
+	c.life.trackUpload(upload)
+	defer func() {
+		if ctx.Err() == nil {
+			c.life.untrackUpload(uploadID)
+		}
+	}()
+
+	c.logInfo("upload.initiated",
+		slog.String("file_key", string(fileKey)),
+		slog.String("file_name", options.FileName),
+		slog.Int64("size", fileSize),
+		slog.Int("parts", calculatedParts))
+
 	// Step 2: Upload file parts and capture ETags
 	chunkSizePerPart := (fileSize + int64(calculatedParts) - 1) / int64(calculatedParts)
-	bytesUploaded := int64(0)
-	uploadParts := []map[string]interface{}{}
 
 	file, err := os.Open(options.File)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, wrapUploadError(err, "failed to open file")
 	}
 	defer file.Close()
 
-	for i := 0; i < calculatedParts; i++ {
-		start := int64(i) * chunkSizePerPart
-		end := start + chunkSizePerPart
-		if end > fileSize {
-			end = fileSize
-		}
-		partSize := end - start
+	workers := c.uploadConcurrency
+	if workers > calculatedParts {
+		workers = calculatedParts
+	}
 
-		// Read chunk
-		chunk := make([]byte, partSize)
-		_, err = file.ReadAt(chunk, start)
-		if err != nil && err != io.EOF {
-			return nil, fmt.Errorf("failed to read chunk: %w", err)
+	var (
+		mu            sync.Mutex
+		wg            sync.WaitGroup
+		uploadErr     error
+		bytesUploaded int64
+		partsDone     int
+	)
+	uploadParts := make([]map[string]interface{}, calculatedParts)
+	partSums := make([][md5.Size]byte, calculatedParts)
+	partIndexes := make(chan int)
+
+	// Parts stored by an earlier run are not uploaded again
+	for _, part := range completed {
+		i := part.PartNumber - 1
+		uploadParts[i] = map[string]interface{}{
+			"etag":        part.ETag,
+			"part_number": part.PartNumber,
 		}
+		sum, _ := hex.DecodeString(part.MD5)
+		copy(partSums[i][:], sum)
+		bytesUploaded += min(fileSize-int64(i)*chunkSizePerPart, chunkSizePerPart)
+		partsDone++
+	}
 
-		// Upload chunk
-		req, err := http.NewRequest("PUT", presignedURLs[i], bytes.NewReader(chunk))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+	// Slow uploads can outlive their presigned URLs; expired ones are
+	// renewed for all parts still to upload
+	urls := &partURLs{
+		urls: append([]string(nil), presignedURLs...),
+		pending: func() []int {
+			mu.Lock()
+			defer mu.Unlock()
+			var indexes []int
+			for i, part := range uploadParts {
+				if part == nil {
+					indexes = append(indexes, i)
+				}
+			}
+			return indexes
+		},
+		refresh: func(partNumbers []int) ([]string, error) {
+			refreshed, err := c.refreshPresignedURLs(ctx, upload, partNumbers, options.Headers, options.RequestTimeout)
+			if err == nil {
+				c.logInfo("upload.urls_refreshed",
+					slog.String("file_key", string(fileKey)),
+					slog.Int("parts", len(partNumbers)))
+			}
+			return refreshed, err
+		},
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range partIndexes {
+				start := int64(i) * chunkSizePerPart
+				end := start + chunkSizePerPart
+				if end > fileSize {
+					end = fileSize
+				}
+				partSize := end - start
+
+				info := UploadPartInfo{FileKey: fileKey, PartNumber: i + 1, TotalParts: calculatedParts, Size: partSize, Attempt: 1}
+				if options.OnPartStart != nil {
+					options.OnPartStart(info)
+				}
+				partStart := time.Now()
+				partURL := urls.get(i)
+				etag, sum, err := c.uploadPart(ctx, file, partURL, start, partSize, detectedMimeType, i+1)
+				attempt := 1
+				if isExpiredURLError(err) {
+					failed := info
+					failed.Duration, failed.Err = time.Since(partStart), err
+					var renewed string
+					if renewed, err = urls.renew(i, partURL); err == nil {
+						partURL = renewed
+						attempt++
+						if options.OnPartRetry != nil {
+							options.OnPartRetry(failed)
+						}
+						info.Attempt = attempt
+						if options.OnPartStart != nil {
+							options.OnPartStart(info)
+						}
+						etag, sum, err = c.uploadPart(ctx, file, partURL, start, partSize, detectedMimeType, i+1)
+					}
+				}
+				if options.OnPartComplete != nil {
+					info.Duration, info.ETag, info.Err = time.Since(partStart), etag, err
+					options.OnPartComplete(info)
+				}
+				if c.metrics != nil {
+					c.metrics.RecordUploadPart(partSize, time.Since(partStart), err)
+				}
+				if err != nil {
+					c.reportError(&ErrorInfo{
+						Method:  http.MethodPut,
+						Path:    stripQuery(partURL),
+						Attempt: attempt,
+						Err:     err,
+					})
+				}
+
+				// Progress callbacks are serialized under the lock
+				mu.Lock()
+				if err != nil {
+					if uploadErr == nil {
+						uploadErr = err
+					}
+					mu.Unlock()
+					continue
+				}
+				uploadParts[i] = map[string]interface{}{
+					"etag":        etag,
+					"part_number": i + 1,
+				}
+				partSums[i] = sum
+				bytesUploaded += partSize
+				partsDone++
+				state.recorder.partDone(UploadedPart{PartNumber: i + 1, ETag: etag, MD5: hex.EncodeToString(sum[:])})
+				c.logDebug("upload.part.completed",
+					slog.String("file_key", string(fileKey)),
+					slog.Int("part_number", i+1),
+					slog.Int("total_parts", calculatedParts),
+					slog.Int64("bytes", partSize),
+					slog.Duration("duration", time.Since(partStart)))
+				if options.OnProgress != nil {
+					options.OnProgress(UploadProgress{
+						CurrentPart:   partsDone,
+						TotalParts:    calculatedParts,
+						BytesUploaded: bytesUploaded,
+						TotalBytes:    fileSize,
+						Percentage:    percentage(bytesUploaded, fileSize),
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < calculatedParts; i++ {
+		mu.Lock()
+		failed := uploadErr != nil
+		stored := uploadParts[i] != nil
+		mu.Unlock()
+		if failed {
+			break
 		}
-		req.Header.Set("Content-Type", detectedMimeType)
+		if !stored {
+			partIndexes <- i
+		}
+	}
+	close(partIndexes)
+	wg.Wait()
+	state.partsDone = partsDone
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to upload chunk: %w", err)
+	if ctx.Err() != nil {
+		if parent.Err() != nil {
+			state.recorder.forget()
 		}
-		resp.Body.Close()
+		return nil, c.cancelUpload(parent, ctx, upload, partsDone, calculatedParts)
+	}
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, fmt.Errorf("failed to upload part %d: status %d", i+1, resp.StatusCode)
+	// Step 3: Complete the multipart upload
+	completeHeaders := options.Headers
+	if c.sseHeaders != nil {
+		completeHeaders = make(map[string]string, len(c.sseHeaders)+len(options.Headers))
+		for k, v := range c.sseHeaders {
+			completeHeaders[k] = v
+		}
+		for k, v := range options.Headers {
+			completeHeaders[k] = v
 		}
+	}
+	req := c.newRequest().
+		SetContext(ctx).
+		SetHeaders(completeHeaders).
+		SetTimeout(options.RequestTimeout).
+		SetBody(map[string]interface{}{
+			"file_key":    fileKey,
+			"upload_id":   uploadID,
+			"object_name": objectName,
+			"parts":       uploadParts,
+		})
+	completeResp, err := fetch[completeUploadResponse](req, http.MethodPost, "/v1/biz/complete-upload")
 
-		// Extract ETag from response
-		etag := resp.Header.Get("ETag")
-		if etag == "" {
-			etag = resp.Header.Get("etag")
+	if err != nil {
+		if ctx.Err() != nil {
+			if parent.Err() != nil {
+				state.recorder.forget()
+			}
+			return nil, c.cancelUpload(parent, ctx, upload, partsDone, calculatedParts)
 		}
-		if etag == "" {
-			return nil, fmt.Errorf("failed to get ETag for part %d", i+1)
+		return nil, fmt.Errorf("failed to complete upload: %w", err)
+	}
+	state.recorder.forget()
+	if c.verifyETags && completeResp.ETag != "" {
+		if err := verifyCompositeETag(fileKey, completeResp.ETag, partSums); err != nil {
+			return nil, err
 		}
-		etag = strings.Trim(etag, "\"")
+	}
 
-		uploadParts = append(uploadParts, map[string]interface{}{
-			"etag":        etag,
-			"part_number": i + 1,
+	c.logInfo("upload.completed",
+		slog.String("file_key", string(fileKey)),
+		slog.Int64("size", fileSize))
+	if options.OnComplete != nil {
+		options.OnComplete(UploadCompleteInfo{
+			FileKey:  fileKey,
+			FileName: options.FileName,
+			Size:     fileSize,
+			Parts:    calculatedParts,
+			Duration: time.Since(began),
+			ETag:     completeResp.ETag,
 		})
+	}
+	if c.preflight != nil {
+		c.preflight.rememberFile(fileKey, options.FileName)
+	}
 
-		bytesUploaded += partSize
+	return uploadResp, nil
+}
 
-		// Report progress
-		if options.OnProgress != nil {
-			options.OnProgress(UploadProgress{
-				CurrentPart:   i + 1,
-				TotalParts:    calculatedParts,
-				BytesUploaded: bytesUploaded,
-				TotalBytes:    fileSize,
-				Percentage:    int((bytesUploaded * 100) / fileSize),
-			})
-		}
+// partsFit reports whether size bytes split into parts parts of equal size
+// (the last one shorter) leaves no part empty
+func partsFit(size int64, parts int) bool {
+	if parts < 1 {
+		return false
 	}
-
-	// Step 3: Complete the multipart upload
-	var completeResp struct {
-		Data struct {
-			Message string `json:"message"`
-			FileKey string `json:"file_key"`
-		} `json:"data"`
+	if size == 0 {
+		return parts == 1
 	}
+	partSize := (size + int64(parts) - 1) / int64(parts)
+	return int64(parts-1)*partSize < size
+}
 
-	_, err = c.httpClient.R().
-		SetBody(map[string]interface{}{
-			"file_key":  fileKey,
-			"upload_id": uploadID,
-			"object_name": objectName,
-			"parts":     uploadParts,
-		}).
-		SetResult(&completeResp).
-		Post("/v1/biz/complete-upload")
+// uploadPart uploads one part of file to its presigned URL and returns the
+// part's ETag and the MD5 of the bytes sent
+func (c *Dragdropdo) uploadPart(ctx context.Context, file *os.File, presignedURL string, start, size int64, mimeType string, partNumber int) (string, [md5.Size]byte, error) {
+	var sum [md5.Size]byte
 
+	// Read chunk
+	chunk := make([]byte, size)
+	_, err := file.ReadAt(chunk, start)
+	if err != nil && err != io.EOF {
+		return "", sum, wrapUploadError(err, "failed to read chunk")
+	}
+	sum = md5.Sum(chunk)
+
+	// Upload chunk
+	header := http.Header{}
+	header.Set("Content-Type", mimeType)
+	header.Set("User-Agent", c.userAgent)
+	for k, v := range c.sseHeaders {
+		header.Set(k, v)
+	}
+
+	sent := time.Now()
+	resp, err := c.transport.PutPart(ctx, presignedURL, header, chunk)
 	if err != nil {
-		return nil, fmt.Errorf("failed to complete upload: %w", err)
+		c.debugPart(presignedURL, partNumber, 0, time.Since(sent), err)
+		return "", sum, wrapUploadError(err, "failed to upload chunk")
+	}
+	c.debugPart(presignedURL, partNumber, resp.StatusCode, time.Since(sent), nil)
+
+	if !resp.IsSuccess() {
+		return "", sum, newPartUploadError(partNumber, presignedURL, resp)
 	}
 
-	return &UploadResponse{
-		FileKey:       fileKey,
-		UploadID:      uploadID,
-		PresignedURLs: presignedURLs,
-		ObjectName:    objectName,
-		FileKeyAlias:  fileKey,
-		UploadIDAlias: uploadID,
-		PresignedURLsAlias: presignedURLs,
-		ObjectNameAlias:    objectName,
-	}, nil
+	// Extract ETag from response
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		etag = resp.Header.Get("etag")
+	}
+	if etag == "" {
+		return "", sum, newUploadError(ErrMissingETag, fmt.Sprintf("failed to get ETag for part %d", partNumber))
+	}
+
+	return strings.Trim(etag, "\""), sum, nil
 }
 
 // CheckSupportedOperation checks if an operation is supported for a file extension
 func (c *Dragdropdo) CheckSupportedOperation(options SupportedOperationOptions) (*SupportedOperationResponse, error) {
 	if options.Ext == "" {
-		return nil, errors.New("extension (ext) is required")
-	}
-
-	var resp struct {
-		Data SupportedOperationResponse `json:"data"`
+		return nil, NewD3ValidationError("extension (ext) is required", nil)
 	}
 
 	body := map[string]interface{}{
@@ -363,71 +1067,88 @@ func (c *Dragdropdo) CheckSupportedOperation(options SupportedOperationOptions)
 	if options.Action != "" {
 		body["action"] = options.Action
 	}
-	if options.Parameters != nil {
+	if len(options.Parameters) > 0 {
 		body["parameters"] = options.Parameters
 	}
 
-	_, err := c.httpClient.R().
-		SetBody(body).
-		SetResult(&resp).
-		Post("/v1/biz/supported-operation")
+	req := c.newRequest().
+		SetHeaders(options.Headers).
+		SetTimeout(options.RequestTimeout).
+		SetBody(body)
+	resp, err := fetch[SupportedOperationResponse](req, http.MethodPost, "/v1/biz/supported-operation")
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to check supported operation: %w", err)
 	}
 
-	return &resp.Data, nil
+	return resp, nil
 }
 
 // CreateOperation creates a file operation
 func (c *Dragdropdo) CreateOperation(options OperationOptions) (*OperationResponse, error) {
+	return c.createOperation(context.Background(), options)
+}
+
+// createOperation creates a file operation using ctx
+func (c *Dragdropdo) createOperation(ctx context.Context, options OperationOptions) (*OperationResponse, error) {
+	var v validation
 	if options.Action == "" {
-		return nil, errors.New("action is required")
+		v.add("action", "action is required")
 	}
 	if len(options.FileKeys) == 0 {
-		return nil, errors.New("at least one file key is required")
+		v.add("file_keys", "at least one file key is required")
 	}
-
-	var resp struct {
-		Data struct {
-			MainTaskID string `json:"main_task_id"`
-		} `json:"data"`
+	c.validateOperationPasswords(&v, options)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		ctx = withDryRun(ctx)
+	}
+	if err := c.preflightOperation(ctx, options); err != nil {
+		return nil, err
 	}
 
 	body := map[string]interface{}{
 		"action":    options.Action,
 		"file_keys": options.FileKeys,
 	}
-	if options.Parameters != nil {
+	if len(options.Parameters) > 0 {
 		body["parameters"] = options.Parameters
 	}
-	if options.Notes != nil {
+	if len(options.Notes) > 0 {
 		body["notes"] = options.Notes
 	}
 
-	_, err := c.httpClient.R().
-		SetBody(body).
-		SetResult(&resp).
-		Post("/v1/biz/do")
+	req := c.newRequest().
+		SetContext(ctx).
+		SetHeaders(options.Headers).
+		SetTimeout(options.RequestTimeout).
+		SetBody(body)
+	resp, err := fetch[OperationResponse](req, http.MethodPost, "/v1/biz/do")
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create operation: %w", err)
 	}
+	if resp.MainTaskID == "" {
+		return nil, errors.New("failed to create operation: main_task_id not received from server")
+	}
+
+	c.logInfo("operation.submitted",
+		slog.String("action", string(options.Action)),
+		slog.String("main_task_id", string(resp.MainTaskID)),
+		slog.Any("file_keys", options.FileKeys))
+	c.saveJob(ctx, options, resp.MainTaskID)
 
-	// Map snake_case to camelCase
-	mainTaskID := resp.Data.MainTaskID
-	return &OperationResponse{
-		MainTaskID:      mainTaskID,
-		MainTaskIDAlias: mainTaskID,
-	}, nil
+	return resp, nil
 }
 
 // Convenience methods
 
 // Convert converts files to a different format
-func (c *Dragdropdo) Convert(fileKeys []string, convertTo string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) Convert(fileKeys []FileKey, convertTo string, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "convert",
+		Action:   ActionConvert,
 		FileKeys: fileKeys,
 		Parameters: map[string]interface{}{
 			"convert_to": convertTo,
@@ -437,12 +1158,12 @@ func (c *Dragdropdo) Convert(fileKeys []string, convertTo string, notes map[stri
 }
 
 // Compress compresses files
-func (c *Dragdropdo) Compress(fileKeys []string, compressionValue string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) Compress(fileKeys []FileKey, compressionValue string, notes map[string]string) (*OperationResponse, error) {
 	if compressionValue == "" {
-		compressionValue = "recommended"
+		compressionValue = DefaultCompressionValue
 	}
 	return c.CreateOperation(OperationOptions{
-		Action:   "compress",
+		Action:   ActionCompress,
 		FileKeys: fileKeys,
 		Parameters: map[string]interface{}{
 			"compression_value": compressionValue,
@@ -452,36 +1173,36 @@ func (c *Dragdropdo) Compress(fileKeys []string, compressionValue string, notes
 }
 
 // Merge merges multiple files
-func (c *Dragdropdo) Merge(fileKeys []string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) Merge(fileKeys []FileKey, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "merge",
+		Action:   ActionMerge,
 		FileKeys: fileKeys,
 		Notes:    notes,
 	})
 }
 
 // Zip creates a ZIP archive from files
-func (c *Dragdropdo) Zip(fileKeys []string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) Zip(fileKeys []FileKey, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "zip",
+		Action:   ActionZip,
 		FileKeys: fileKeys,
 		Notes:    notes,
 	})
 }
 
 // Share shares files (generates shareable links)
-func (c *Dragdropdo) Share(fileKeys []string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) Share(fileKeys []FileKey, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "share",
+		Action:   ActionShare,
 		FileKeys: fileKeys,
 		Notes:    notes,
 	})
 }
 
 // LockPdf locks PDF with password
-func (c *Dragdropdo) LockPdf(fileKeys []string, password string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) LockPdf(fileKeys []FileKey, password string, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "lock",
+		Action:   ActionLock,
 		FileKeys: fileKeys,
 		Parameters: map[string]interface{}{
 			"password": password,
@@ -491,9 +1212,9 @@ func (c *Dragdropdo) LockPdf(fileKeys []string, password string, notes map[strin
 }
 
 // UnlockPdf unlocks PDF with password
-func (c *Dragdropdo) UnlockPdf(fileKeys []string, password string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) UnlockPdf(fileKeys []FileKey, password string, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "unlock",
+		Action:   ActionUnlock,
 		FileKeys: fileKeys,
 		Parameters: map[string]interface{}{
 			"password": password,
@@ -503,9 +1224,9 @@ func (c *Dragdropdo) UnlockPdf(fileKeys []string, password string, notes map[str
 }
 
 // ResetPdfPassword resets PDF password
-func (c *Dragdropdo) ResetPdfPassword(fileKeys []string, oldPassword, newPassword string, notes map[string]string) (*OperationResponse, error) {
+func (c *Dragdropdo) ResetPdfPassword(fileKeys []FileKey, oldPassword, newPassword string, notes map[string]string) (*OperationResponse, error) {
 	return c.CreateOperation(OperationOptions{
-		Action:   "reset_password",
+		Action:   ActionResetPassword,
 		FileKeys: fileKeys,
 		Parameters: map[string]interface{}{
 			"old_password": oldPassword,
@@ -517,93 +1238,106 @@ func (c *Dragdropdo) ResetPdfPassword(fileKeys []string, oldPassword, newPasswor
 
 // GetStatus gets operation status
 func (c *Dragdropdo) GetStatus(options StatusOptions) (*StatusResponse, error) {
+	return c.getStatus(context.Background(), options)
+}
+
+// getStatus gets operation status using ctx
+func (c *Dragdropdo) getStatus(ctx context.Context, options StatusOptions) (*StatusResponse, error) {
 	if options.MainTaskID == "" {
-		return nil, errors.New("main_task_id is required")
+		return nil, NewD3ValidationError("main_task_id is required", nil)
 	}
 
-	url := fmt.Sprintf("/v1/biz/status/%s", options.MainTaskID)
+	route := "/v1/biz/status/{main_task_id}"
 	if options.FileTaskID != "" {
-		url += fmt.Sprintf("/%s", options.FileTaskID)
+		route += "/{file_task_id}"
 	}
 
-	var resp struct {
-		Data struct {
-			OperationStatus string `json:"operation_status"`
-			FilesData        []struct {
-				FileKey      string `json:"file_key"`
-				Status       string `json:"status"`
-				DownloadLink string `json:"download_link,omitempty"`
-				ErrorCode    string `json:"error_code,omitempty"`
-				ErrorMessage string `json:"error_message,omitempty"`
-			} `json:"files_data"`
-		} `json:"data"`
-	}
-
-	_, err := c.httpClient.R().
-		SetResult(&resp).
-		Get(url)
+	req := c.newRequest().
+		SetContext(ctx).
+		SetHeaders(options.Headers).
+		SetTimeout(options.RequestTimeout).
+		SetPathParam("main_task_id", string(options.MainTaskID)).
+		SetPathParam("file_task_id", string(options.FileTaskID))
+	resp, err := fetch[StatusResponse](req, http.MethodGet, route)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get status: %w", err)
 	}
-
-	// Map snake_case to camelCase
-	filesData := make([]FileTaskStatus, len(resp.Data.FilesData))
-	for i, file := range resp.Data.FilesData {
-		filesData[i] = FileTaskStatus{
-			FileKey:      file.FileKey,
-			Status:       file.Status,
-			DownloadLink: file.DownloadLink,
-			ErrorCode:    file.ErrorCode,
-			ErrorMessage: file.ErrorMessage,
-		}
+	if resp.OperationStatus == "" {
+		return nil, errors.New("failed to get status: operation_status not received from server")
 	}
 
-	return &StatusResponse{
-		OperationStatus:     resp.Data.OperationStatus,
-		FilesData:           filesData,
-		OperationStatusAlias: resp.Data.OperationStatus,
-		FilesDataAlias:      filesData,
-	}, nil
+	return resp, nil
 }
 
-// PollStatus polls operation status until completion or failure
+// PollStatus polls operation status until the operation reaches a terminal
+// status: completed, failed, partially_completed or cancelled
 func (c *Dragdropdo) PollStatus(options PollStatusOptions) (*StatusResponse, error) {
-	interval := options.Interval
-	if interval == 0 {
-		interval = 2 * time.Second
-	}
-	timeout := options.Timeout
-	if timeout == 0 {
-		timeout = 5 * time.Minute
+	return c.pollStatus(context.Background(), options)
+}
+
+// pollStatus is PollStatus bounded by ctx
+func (c *Dragdropdo) pollStatus(ctx context.Context, options PollStatusOptions) (_ *StatusResponse, err error) {
+	interval := durationOr(options.Interval, DefaultPollInterval)
+	timeout := durationOr(options.Timeout, DefaultPollTimeout)
+
+	progress := startProgress(options.Progress, Progress{Stage: ProgressPoll, Name: string(options.MainTaskID)})
+	defer func() { progress.finish(err) }()
+
+	ctx, done, err := c.life.beginOperation(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer done()
 
 	startTime := time.Now()
+	var last *StatusResponse
 
 	for {
 		// Check timeout
 		if time.Since(startTime) > timeout {
-			return nil, fmt.Errorf("polling timed out after %v", timeout)
+			return nil, newPollTimeoutError(options.MainTaskID, timeout, last)
 		}
 
 		// Get status
-		status, err := c.GetStatus(options.StatusOptions)
+		status, err := c.getStatus(ctx, options.StatusOptions)
 		if err != nil {
+			// An attempt timeout, not Close, cut the request short
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				return nil, newRequestTimeoutError(options.MainTaskID, err, last)
+			}
 			return nil, err
 		}
+		last = status
+
+		c.logDebug("poll.tick",
+			slog.String("main_task_id", string(options.MainTaskID)),
+			slog.String("operation_status", status.OperationStatus),
+			slog.Duration("elapsed", time.Since(startTime)))
 
 		// Call update callback
 		if options.OnUpdate != nil {
 			options.OnUpdate(*status)
 		}
-
-		// Check if completed or failed
-		if status.OperationStatus == "completed" || status.OperationStatus == "failed" {
+		progress.update(int64(finishedFiles(status)), int64(len(status.FilesData)), status.OperationStatus)
+
+		// Check if finished, including with mixed results or cancelled
+		if status.Done() {
+			c.logInfo("poll.finished",
+				slog.String("main_task_id", string(options.MainTaskID)),
+				slog.String("operation_status", status.OperationStatus),
+				slog.Duration("elapsed", time.Since(startTime)))
+			if c.metrics != nil {
+				c.metrics.RecordPoll(status.OperationStatus, time.Since(startTime))
+			}
+			c.deleteJob(ctx, options.MainTaskID)
 			return status, nil
 		}
 
 		// Wait before next poll
-		time.Sleep(interval)
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
 	}
 }
 
@@ -628,4 +1362,3 @@ func (c *Dragdropdo) getMimeType(ext string) string {
 
 	return mimeTypes[strings.ToLower(ext)]
 }
-
@@ -64,7 +64,7 @@ func TestClient_LiveAPI_UploadConvertPollDownload(t *testing.T) {
 	t.Logf("[live-test] Upload result: file_key=%s, upload_id=%s", upload.FileKey, upload.UploadID)
 
 	t.Log("[live-test] Starting convert...")
-	operation, err := client.Convert([]string{upload.FileKey}, "png", nil)
+	operation, err := client.Convert([]FileKey{upload.FileKey}, "png", nil)
 	if err != nil {
 		t.Fatalf("Convert failed: %v", err)
 	}
@@ -1,7 +1,10 @@
 package d3
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -44,7 +47,7 @@ func TestClient_UploadFile_MultipartFlow(t *testing.T) {
 
 	// Mock server for API requests
 	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/v1/external/upload" {
+		if r.URL.Path == "/v1/biz/initiate-upload" {
 			// Presigned URL request
 			var body map[string]interface{}
 			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -61,7 +64,7 @@ func TestClient_UploadFile_MultipartFlow(t *testing.T) {
 			}
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(response)
-		} else if r.URL.Path == "/v1/external/complete-upload" {
+		} else if r.URL.Path == "/v1/biz/complete-upload" {
 			// Complete upload request
 			var body map[string]interface{}
 			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -121,7 +124,7 @@ func TestClient_CreateOperation_AndPollStatus(t *testing.T) {
 	callCount := 0
 	// Mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.HasPrefix(r.URL.Path, "/v1/external/do") {
+		if strings.HasPrefix(r.URL.Path, "/v1/biz/do") {
 			// Create operation
 			if r.Method != "POST" {
 				t.Errorf("Expected POST, got %s", r.Method)
@@ -145,7 +148,7 @@ func TestClient_CreateOperation_AndPollStatus(t *testing.T) {
 			}
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(response)
-		} else if strings.HasPrefix(r.URL.Path, "/v1/external/status/") {
+		} else if strings.HasPrefix(r.URL.Path, "/v1/biz/status/") {
 			// Get status
 			if r.Method != "GET" {
 				t.Errorf("Expected GET, got %s", r.Method)
@@ -174,8 +177,8 @@ func TestClient_CreateOperation_AndPollStatus(t *testing.T) {
 						"operation_status": "completed",
 						"files_data": []map[string]interface{}{
 							{
-								"file_key":     "file-key-123",
-								"status":       "completed",
+								"file_key":      "file-key-123",
+								"status":        "completed",
 								"download_link": "https://files.d3.com/output.png",
 							},
 						},
@@ -198,7 +201,7 @@ func TestClient_CreateOperation_AndPollStatus(t *testing.T) {
 	}
 
 	// Create operation
-	operation, err := client.Convert([]string{"file-key-123"}, "png", nil)
+	operation, err := client.Convert([]FileKey{"file-key-123"}, "png", nil)
 	if err != nil {
 		t.Fatalf("Failed to create operation: %v", err)
 	}
@@ -253,7 +256,7 @@ func TestClient_NewDragdropdo_Validation(t *testing.T) {
 
 func TestClient_CheckSupportedOperation(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "POST" || r.URL.Path != "/v1/external/supported-operation" {
+		if r.Method != "POST" || r.URL.Path != "/v1/biz/supported-operation" {
 			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
 			return
 		}
@@ -304,3 +307,405 @@ func TestClient_CheckSupportedOperation(t *testing.T) {
 	}
 }
 
+func TestClient_PerRequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace-Id") != "trace-1" {
+			t.Errorf("Expected X-Trace-Id header, got '%s'", r.Header.Get("X-Trace-Id"))
+		}
+		if r.Header.Get("X-Tenant") != "override" {
+			t.Errorf("Expected X-Tenant to be overridden, got '%s'", r.Header.Get("X-Tenant"))
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected default Authorization header, got '%s'", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+		Headers: map[string]string{"X-Tenant": "default"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateOperation(OperationOptions{
+		Action:   "convert",
+		FileKeys: []FileKey{"file-key-123"},
+		Headers: map[string]string{
+			"X-Trace-Id": "trace-1",
+			"X-Tenant":   "override",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create operation: %v", err)
+	}
+}
+
+func TestClient_PerRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+		Timeout: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"}); err == nil {
+		t.Error("Expected client timeout to apply")
+	}
+
+	status, err := client.GetStatus(StatusOptions{
+		MainTaskID:     "task-123",
+		RequestTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Expected per-request timeout to override client timeout: %v", err)
+	}
+	if status.OperationStatus != "completed" {
+		t.Errorf("Expected completed status, got '%s'", status.OperationStatus)
+	}
+}
+
+func TestClient_UploadFile_Concurrent(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "concurrent.bin")
+	if err := os.WriteFile(tmpFile, []byte(strings.Repeat("b", 4000)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["parts"] != float64(4) {
+				t.Errorf("Expected 4 parts from chunk size, got %v", body["parts"])
+			}
+			urls := []string{}
+			for i := 1; i <= 4; i++ {
+				urls = append(urls, server.URL+"/part/"+string(rune('0'+i)))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": urls,
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			w.Header().Set("ETag", `"etag-`+strings.TrimPrefix(r.URL.Path, "/part/")+`"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			var body struct {
+				Parts []struct {
+					ETag       string `json:"etag"`
+					PartNumber int    `json:"part_number"`
+				} `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			for i, part := range body.Parts {
+				if part.PartNumber != i+1 || part.ETag != "etag-"+string(rune('1'+i)) {
+					t.Errorf("Unexpected part %d: %+v", i, part)
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:            "test-key",
+		BaseURL:           server.URL,
+		ChunkSize:         1000,
+		UploadConcurrency: 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var last UploadProgress
+	_, err = client.UploadFile(UploadFileOptions{
+		File:     tmpFile,
+		FileName: "concurrent.bin",
+		OnProgress: func(progress UploadProgress) {
+			last = progress
+		},
+	})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if last.CurrentPart != 4 || last.Percentage != 100 {
+		t.Errorf("Expected final progress of 4 parts / 100%%, got %+v", last)
+	}
+}
+
+func TestClient_UploadFileContext_Cancel(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "cancel.bin")
+	if err := os.WriteFile(tmpFile, []byte(strings.Repeat("c", 3000)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	aborted := make(chan string, 1)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1", server.URL + "/part/2", server.URL + "/part/3"},
+				},
+			})
+		case "/part/1":
+			w.Header().Set("ETag", `"etag-1"`)
+		case "/part/2":
+			// Cancel while the second part is in flight
+			io.ReadAll(r.Body)
+			cancel()
+			<-r.Context().Done()
+		case "/v1/biz/abort-upload":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			aborted <- body["upload_id"].(string)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, ChunkSize: 1000})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.UploadFileContext(ctx, UploadFileOptions{File: tmpFile, FileName: "cancel.bin"})
+	var uploadErr *D3UploadError
+	if !errors.As(err, &uploadErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected D3UploadError wrapping context.Canceled, got %T: %v", err, err)
+	}
+	if uploadErr.PartsCompleted != 1 || uploadErr.TotalParts != 3 {
+		t.Errorf("Expected 1 of 3 parts completed, got %d of %d", uploadErr.PartsCompleted, uploadErr.TotalParts)
+	}
+
+	select {
+	case uploadID := <-aborted:
+		if uploadID != "upload-id-456" {
+			t.Errorf("Expected upload-id-456 to be aborted, got %s", uploadID)
+		}
+	default:
+		t.Error("Expected the cancelled upload to be aborted")
+	}
+}
+
+func TestClient_UploadFile_RenegotiatesParts(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "renegotiate.bin")
+	if err := os.WriteFile(tmpFile, []byte(strings.Repeat("f", 4000)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	partSizes := map[string]int{}
+	var completedParts int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			urls := []string{}
+			for i := 1; i <= 2; i++ {
+				urls = append(urls, server.URL+"/part/"+string(rune('0'+i)))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": urls,
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			body, _ := io.ReadAll(r.Body)
+			partSizes[r.URL.Path] = len(body)
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			var body struct {
+				Parts []interface{} `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			completedParts = len(body.Parts)
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, ChunkSize: 1000})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "renegotiate.bin"}); err != nil {
+		t.Fatalf("Expected upload to adopt the server's part count, got %v", err)
+	}
+	if completedParts != 2 || partSizes["/part/1"] != 2000 || partSizes["/part/2"] != 2000 {
+		t.Errorf("Expected 2 parts of 2000 bytes, got %d parts sized %v", completedParts, partSizes)
+	}
+
+	if !partsFit(10, 4) || partsFit(10, 6) || partsFit(3, 5) || !partsFit(0, 1) {
+		t.Error("Unexpected partsFit result")
+	}
+}
+
+func TestClient_UploadFile_TinyFileSinglePart(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "tiny.txt")
+	if err := os.WriteFile(tmpFile, []byte("abc"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var requestedParts float64
+	var puts int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			requestedParts, _ = body["parts"].(float64)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			puts++
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var last UploadProgress
+	_, err = client.UploadFile(UploadFileOptions{
+		File:       tmpFile,
+		FileName:   "tiny.txt",
+		Parts:      3,
+		OnProgress: func(p UploadProgress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if requestedParts != 1 || puts != 1 {
+		t.Errorf("Expected a single part, requested %v and uploaded %d", requestedParts, puts)
+	}
+	if last.Percentage != 100 || last.BytesUploaded != 3 || last.TotalParts != 1 {
+		t.Errorf("Expected 100%% of 3 bytes in 1 part, got %+v", last)
+	}
+}
+
+func TestClient_UploadFile_RetriesWholeUpload(t *testing.T) {
+	dir := t.TempDir()
+	smallFile := filepath.Join(dir, "small.bin")
+	largeFile := filepath.Join(dir, "large.bin")
+	if err := os.WriteFile(smallFile, []byte(strings.Repeat("r", 500)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(largeFile, []byte(strings.Repeat("r", 2000)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var initiates, aborts int
+	// failing maps a part path to the number of times it still fails
+	var failing map[string]int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			initiates++
+			var body struct {
+				Parts int `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			urls := []string{}
+			for i := 1; i <= body.Parts; i++ {
+				urls = append(urls, fmt.Sprintf("%s/part/%d", server.URL, i))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       fmt.Sprintf("file-key-%d", initiates),
+					"upload_id":      fmt.Sprintf("upload-id-%d", initiates),
+					"presigned_urls": urls,
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			if failing[r.URL.Path] > 0 {
+				failing[r.URL.Path]--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/abort-upload":
+			aborts++
+			w.Write([]byte(`{"data":{}}`))
+		case r.URL.Path == "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:         "test-key",
+		BaseURL:        server.URL,
+		ChunkSize:      1000,
+		UploadAttempts: 3,
+		Retry:          RetryPolicy{Backoff: func(int) time.Duration { return time.Millisecond }},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// The only part fails twice before anything is stored
+	failing = map[string]int{"/part/1": 2}
+	resp, err := client.UploadFile(UploadFileOptions{File: smallFile, FileName: "small.bin"})
+	if err != nil {
+		t.Fatalf("Expected the upload to succeed on its third attempt, got %v", err)
+	}
+	if initiates != 3 || aborts != 2 || resp.FileKey != "file-key-3" {
+		t.Errorf("Expected 3 initiates, 2 aborts and file-key-3, got %d, %d and %s", initiates, aborts, resp.FileKey)
+	}
+
+	// A part already stored rules out starting over; parts go in order
+	initiates, aborts = 0, 0
+	failing = map[string]int{"/part/2": 1}
+	if _, err := client.UploadFile(UploadFileOptions{File: largeFile, FileName: "large.bin"}); err == nil {
+		t.Fatal("Expected the upload to fail")
+	}
+	if initiates != 1 {
+		t.Errorf("Expected no retry after a stored part, got %d initiates", initiates)
+	}
+}
@@ -0,0 +1,207 @@
+package d3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clone returns a client that shares this client's connection pools but with
+// opts applied on top of its settings. The API key or credentials, auth
+// scheme, signer, custom transport, base URL, timeout, headers (merged over
+// the existing ones), retry policy, user agent, upload tuning and size limit,
+// password policy, logger and OnSchemaDrift can be overridden,
+// PreflightValidation and DryRun enabled, and ETag verification disabled.
+// Transport settings would need new connection pools and Debug, Metrics and
+// CurlWriter are installed as hooks, so setting any of them returns an
+// error.
+//
+// The clone starts with this client's hooks; hooks registered afterwards on
+// either client do not affect the other. Closing either client does not stop
+// the other from making calls.
+func (c *Dragdropdo) Clone(opts ...Option) (*Dragdropdo, error) {
+	var config Config
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if name := unclonableSetting(config); name != "" {
+		return nil, fmt.Errorf("%s cannot be changed by Clone", name)
+	}
+
+	beforeHooks, afterHooks := c.hooks()
+	headers, timeout := c.settings()
+	clone := &Dragdropdo{
+		credentials: c.getCredentials(),
+		authScheme:  c.authScheme,
+		signer:      c.signer,
+		baseURL:     c.baseURL,
+		endpoints:   c.endpoints,
+		timeout:     timeout,
+		headers:     make(map[string]string, len(headers)),
+		userAgent:   c.userAgent,
+		transport:   c.transport,
+		apiClient:   c.apiClient,
+		partClient:  c.partClient,
+		retry:       c.retry,
+		life:        newLifecycle(),
+
+		chunkSize:         c.chunkSize,
+		uploadConcurrency: c.uploadConcurrency,
+		uploadAttempts:    c.uploadAttempts,
+		stateStore:        c.stateStore,
+		uploadCache:       c.uploadCache,
+		encryption:        c.encryption,
+		sseHeaders:        c.sseHeaders,
+		verifyETags:       c.verifyETags,
+		passwordPolicy:    c.passwordPolicy,
+		fileSizeLimit:     c.fileSizeLimit,
+		planLimit:         c.planLimit,
+		debugLogger:       c.debugLogger,
+		logger:            c.logger,
+		metrics:           c.metrics,
+		preflight:         c.preflight,
+		catalog:           c.catalog,
+		dryRun:            c.dryRun || config.DryRun,
+		onSchemaDrift:     c.onSchemaDrift,
+
+		beforeHooks: append([]BeforeRequestHook(nil), beforeHooks...),
+		afterHooks:  append([]AfterResponseHook(nil), afterHooks...),
+		errorHooks:  append([]ErrorHook(nil), c.getErrorHooks()...),
+	}
+	for k, v := range headers {
+		clone.headers[k] = v
+	}
+
+	switch {
+	case config.Credentials != nil:
+		clone.credentials = config.Credentials
+	case config.APIKey != "":
+		clone.credentials = StaticCredentials(config.APIKey)
+	}
+
+	if config.AuthScheme != "" {
+		if err := config.AuthScheme.validate(); err != nil {
+			return nil, err
+		}
+		clone.authScheme = config.AuthScheme
+	}
+
+	if config.Signer != nil {
+		clone.signer = config.Signer
+	}
+	if config.CustomTransport != nil {
+		clone.transport = config.CustomTransport
+	}
+
+	if config.BaseURL != "" || config.Environment != "" || len(config.FallbackBaseURLs) > 0 {
+		baseURL := config.BaseURL
+		if baseURL == "" {
+			var err error
+			if baseURL, err = config.Environment.BaseURL(); err != nil {
+				return nil, err
+			}
+		}
+		clone.baseURL = strings.TrimSuffix(baseURL, "/")
+
+		baseURLs := []string{clone.baseURL}
+		for _, fallback := range config.FallbackBaseURLs {
+			baseURLs = append(baseURLs, strings.TrimSuffix(fallback, "/"))
+		}
+		clone.endpoints = newEndpointPool(baseURLs, config.FailoverCooldown)
+	}
+
+	if config.Timeout > 0 {
+		clone.timeout = config.Timeout
+	}
+	if config.UserAgentSuffix != "" {
+		clone.userAgent = userAgent(config.UserAgentSuffix)
+		clone.headers["User-Agent"] = clone.userAgent
+	}
+	for k, v := range config.Headers {
+		clone.headers[k] = v
+	}
+	if config.Retry.MaxAttempts != 0 {
+		clone.retry = config.Retry
+	}
+	if config.ChunkSize > 0 {
+		clone.chunkSize = config.ChunkSize
+	}
+	if config.UploadConcurrency > 0 {
+		clone.uploadConcurrency = config.UploadConcurrency
+	}
+	if config.UploadAttempts > 0 {
+		clone.uploadAttempts = config.UploadAttempts
+	}
+	if config.StateStore != nil {
+		clone.stateStore = config.StateStore
+	}
+	if config.UploadCache != nil {
+		clone.uploadCache = config.UploadCache
+	}
+	if config.Encryption != nil {
+		clone.encryption = config.Encryption
+	}
+	if config.SSECustomerKey != nil {
+		if err := config.SSECustomerKey.validate(); err != nil {
+			return nil, err
+		}
+		clone.sseHeaders = config.SSECustomerKey.headers()
+		clone.verifyETags = false
+	}
+	if config.DisableETagVerification {
+		clone.verifyETags = false
+	}
+	if config.Logger != nil {
+		clone.logger = config.Logger
+	}
+	if config.MaxFileSize > 0 {
+		clone.fileSizeLimit = config.MaxFileSize
+	} else if config.CheckPlanFileSize && clone.planLimit == nil {
+		clone.planLimit = &sizeLimit{}
+	}
+	if config.PasswordPolicy != nil {
+		clone.passwordPolicy = *config.PasswordPolicy
+	}
+	if config.OnSchemaDrift != nil {
+		clone.onSchemaDrift = config.OnSchemaDrift
+	}
+	if config.PreflightValidation && clone.preflight == nil {
+		clone.preflight = newPreflight()
+	}
+
+	return clone, nil
+}
+
+// unclonableSetting returns the name of the first setting in config that
+// Clone cannot apply, or "" if there is none
+func unclonableSetting(config Config) string {
+	switch {
+	case config.HTTPClient != nil:
+		return "HTTPClient"
+	case config.Transport != nil:
+		return "Transport"
+	case config.ProxyURL != "":
+		return "ProxyURL"
+	case config.TLSConfig != nil:
+		return "TLSConfig"
+	case config.DialTimeout != 0:
+		return "DialTimeout"
+	case config.TLSHandshakeTimeout != 0:
+		return "TLSHandshakeTimeout"
+	case config.ResponseHeaderTimeout != 0:
+		return "ResponseHeaderTimeout"
+	case config.UploadPartTimeout != 0:
+		return "UploadPartTimeout"
+	case config.APIConnections != (ConnectionOptions{}):
+		return "APIConnections"
+	case config.UploadConnections != (ConnectionOptions{}):
+		return "UploadConnections"
+	case config.Debug || config.DebugLogger != nil:
+		return "Debug"
+	case config.Metrics != nil:
+		return "Metrics"
+	case config.CurlWriter != nil:
+		return "CurlWriter"
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package d3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Clone(t *testing.T) {
+	var gotAuth, gotTenant, gotTrace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotTenant = r.Header.Get("X-Tenant")
+		gotTrace = r.Header.Get("X-Trace")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	hooked := 0
+	client, err := NewClient("parent-key", WithBaseURL(server.URL), WithHeader("X-Tenant", "a"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.OnAfterResponse(func(*ResponseInfo) { hooked++ })
+
+	clone, err := client.Clone(WithTimeout(time.Second), WithHeader("X-Trace", "t-1"), WithCredentials(StaticCredentials("clone-key")))
+	if err != nil {
+		t.Fatalf("Failed to clone client: %v", err)
+	}
+	if clone.transport != client.transport || clone.partClient != client.partClient {
+		t.Error("Expected clone to share the parent's HTTP clients")
+	}
+	if clone.timeout != time.Second {
+		t.Errorf("Expected clone timeout 1s, got %v", clone.timeout)
+	}
+
+	if _, err := clone.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if gotAuth != "Bearer clone-key" || gotTenant != "a" || gotTrace != "t-1" {
+		t.Errorf("Unexpected clone headers: auth=%q tenant=%q trace=%q", gotAuth, gotTenant, gotTrace)
+	}
+	if hooked != 1 {
+		t.Errorf("Expected clone to inherit the parent's hooks, ran %d", hooked)
+	}
+
+	// The parent is unchanged
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if gotAuth != "Bearer parent-key" || gotTrace != "" || client.timeout != 30*time.Second {
+		t.Errorf("Expected parent to be unchanged: auth=%q trace=%q timeout=%v", gotAuth, gotTrace, client.timeout)
+	}
+
+	unverified, err := client.Clone(WithoutETagVerification())
+	if err != nil {
+		t.Fatalf("Failed to clone client: %v", err)
+	}
+	if !client.verifyETags || unverified.verifyETags {
+		t.Errorf("Expected only the clone to skip ETag verification, got parent %v, clone %v", client.verifyETags, unverified.verifyETags)
+	}
+
+	if _, err := client.Clone(WithProxy("http://proxy.internal:3128")); err == nil {
+		t.Error("Expected error changing transport settings")
+	}
+}
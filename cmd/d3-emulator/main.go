@@ -0,0 +1,41 @@
+// Command d3-emulator serves the D3 API's upload, operation and status
+// endpoints locally, so the client can be developed against without network
+// access, e.g. on a plane or in air-gapped CI:
+//
+//	d3-emulator -addr :8787 -processing-time 2s
+//	D3_BASE_URL=http://localhost:8787 D3_API_KEY=any d3 upload report.docx
+//
+// Uploads are kept in memory. Operations run for -processing-time, then
+// complete with fake results: conversions copy the input bytes under the
+// new extension, merges concatenate the inputs and zips build a real zip
+// archive. Files whose names match -fail fail, so partially completed and
+// failed operations can be exercised. Any API key is accepted.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/dragdropdo/dragdropdo-sdk-go/d3test"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8787", "address to listen on")
+	processingTime := flag.Duration("processing-time", 2*time.Second, "how long operations run before completing")
+	failPattern := flag.String("fail", "", "file names matching this pattern, e.g. '*.bad', fail to process")
+	flag.Parse()
+
+	if _, err := path.Match(*failPattern, ""); err != nil {
+		log.Fatalf("invalid -fail pattern: %v", err)
+	}
+
+	emulator := d3test.NewEmulator(d3test.EmulatorOptions{
+		ProcessingTime: *processingTime,
+		FailPattern:    *failPattern,
+	})
+	log.Printf("D3 API emulator listening on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, emulator))
+}
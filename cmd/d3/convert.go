@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+func newConvertCmd(flags *globalFlags) *cobra.Command {
+	var to string
+	cmd := &cobra.Command{
+		Use:   "convert --to FORMAT FILE_KEY...",
+		Short: "Start converting uploaded files and print the task ID",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close(cmd.Context())
+
+			op, err := client.Convert(d3.FileKeys(args...), to, nil)
+			if err != nil {
+				return err
+			}
+			if flags.json {
+				return writeJSON(cmd.OutOrStdout(), operationRecord{MainTaskID: op.MainTaskID})
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), op.MainTaskID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&to, "to", "", "target format, e.g. pdf or png")
+	cmd.MarkFlagRequired("to")
+	return cmd
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd(flags *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the API key, connectivity, clock and storage access",
+		Long: `Diagnose the most common setup failures: an unreachable base URL, a local
+clock too far from the API's, a rejected API key and unreachable presigned
+storage. Each failed check prints a hint. The storage check starts a one-byte
+upload and aborts it. The exit status is 1 when a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close(cmd.Context())
+
+			report, err := client.HealthCheck(cmd.Context())
+			if flags.json {
+				if err := writeJSON(cmd.OutOrStdout(), newDoctorRecord(report)); err != nil {
+					return err
+				}
+			} else {
+				printReport(cmd.OutOrStdout(), report)
+			}
+			return err
+		},
+	}
+}
+
+// checkStatus is "ok", "fail" or "skip"
+func checkStatus(check d3.HealthCheckResult) string {
+	switch {
+	case check.OK:
+		return "ok"
+	case check.Skipped:
+		return "skip"
+	}
+	return "fail"
+}
+
+// printReport writes the base URL, then one aligned line per check, followed by its hint if
+// it failed
+func printReport(w io.Writer, report *d3.HealthReport) {
+	fmt.Fprintf(w, "base URL: %s\n", report.BaseURL)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, check := range report.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", checkStatus(check), check.Name, check.Detail)
+		if check.Hint != "" {
+			fmt.Fprintf(tw, "\t\thint: %s\n", check.Hint)
+		}
+	}
+	tw.Flush()
+}
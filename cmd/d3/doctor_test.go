@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newDoctorServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/" || r.URL.Path == "/part/1":
+			w.Header().Set("ETag", `"etag"`)
+		case r.Header.Get("Authorization") != "Bearer test-key":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			w.Write([]byte(`{"data": {"file_key": "probe", "upload_id": "up-1", "presigned_urls": ["` + server.URL + `/part/1"]}}`))
+		default:
+			w.Write([]byte(`{"data": {}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCLI_Doctor(t *testing.T) {
+	server := newDoctorServer(t)
+	t.Setenv("D3_BASE_URL", server.URL)
+
+	out, err := run(t, "doctor", "--api-key", "test-key")
+	if err != nil {
+		t.Fatalf("Expected all checks to pass, got %v\n%s", err, out)
+	}
+	for _, want := range []string{"ok  api", "ok  clock", "ok  auth", "ok  storage"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got\n%s", want, out)
+		}
+	}
+
+	out, err = run(t, "doctor", "--api-key", "wrong-key")
+	if err == nil {
+		t.Fatal("Expected a rejected key to fail")
+	}
+	if !strings.Contains(out, "fail  auth     API key rejected") || !strings.Contains(out, "hint: check the API key") {
+		t.Errorf("Expected an actionable auth failure, got\n%s", out)
+	}
+}
+
+func TestCLI_DoctorJSON(t *testing.T) {
+	server := newDoctorServer(t)
+	t.Setenv("D3_BASE_URL", server.URL)
+
+	out, err := run(t, "doctor", "--api-key", "wrong-key", "--json")
+	if err == nil {
+		t.Fatal("Expected a rejected key to fail")
+	}
+	var record doctorRecord
+	if err := json.Unmarshal([]byte(out), &record); err != nil {
+		t.Fatalf("Expected a doctor record, got %q: %v", out, err)
+	}
+	statuses := make([]string, len(record.Checks))
+	for i, check := range record.Checks {
+		statuses[i] = check.Name + "=" + check.Status
+	}
+	if record.OK || strings.Join(statuses, ",") != "api=ok,clock=ok,auth=fail,storage=skip" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+func newDownloadCmd(flags *globalFlags) *cobra.Command {
+	var outDir string
+	cmd := &cobra.Command{
+		Use:   "download TASK_ID",
+		Short: "Download the results of a finished operation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close(cmd.Context())
+
+			taskID := d3.MainTaskID(args[0])
+			status, err := client.GetStatus(d3.StatusOptions{MainTaskID: taskID})
+			if err != nil {
+				return err
+			}
+			if !status.Done() {
+				return fmt.Errorf("operation %s is still %s", taskID, status.OperationStatus)
+			}
+			if len(status.CompletedFiles()) == 0 {
+				return fmt.Errorf("operation %s has no completed files", taskID)
+			}
+			results, err := client.Results(cmd.Context(), status)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(outDir, 0755); err != nil {
+				return err
+			}
+			for _, result := range results {
+				if result.Status != d3.StatusCompleted {
+					continue
+				}
+				dest := filepath.Join(outDir, result.Name)
+				bar := flags.newProgressBar(cmd.ErrOrStderr(), result.Name)
+				err := downloadResult(cmd.Context(), &result, dest, bar)
+				bar.Finish()
+				if err != nil {
+					return fmt.Errorf("%s: %w", result.FileKey, err)
+				}
+				if flags.json {
+					if err := writeJSON(cmd.OutOrStdout(), downloadRecord{FileKey: result.FileKey, Path: dest}); err != nil {
+						return err
+					}
+					continue
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), dest)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outDir, "output", "o", ".", "directory to write the files to")
+	return cmd
+}
+
+// downloadResult writes the output of result to dest, decrypted with the
+// client's Config.Encryption if set, removing dest on failure. Progress is
+// drawn on bar.
+func downloadResult(ctx context.Context, result *d3.Result, dest string, bar *d3.ProgressBar) error {
+	body, err := result.OpenContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	progress := &d3.ProgressWriter{TotalBytes: result.Size, OnProgress: bar.Download}
+	if _, err := io.Copy(io.MultiWriter(out, progress), body); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses, so scripts can tell failures apart without parsing output
+const (
+	exitOK = 0
+	// exitGeneric is any error while running a command, e.g. an API error
+	exitGeneric = 1
+	// exitUsage is an unknown command, flag or wrong number of arguments
+	exitUsage = 2
+	// exitOperationFailed is an operation that finished with status failed
+	exitOperationFailed = 3
+	// exitPartial is an operation in which only some files succeeded
+	exitPartial = 4
+	// exitCancelled is an operation that was cancelled
+	exitCancelled = 5
+	// exitTimeout is a watch that gave up while the operation was running
+	exitTimeout = 6
+	// exitInterrupted follows the shell convention for SIGINT
+	exitInterrupted = 130
+)
+
+// exitError carries the exit status for err
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the exit status for an error returned by Execute. ctx is
+// the context the command ran with.
+func exitCode(ctx context.Context, err error) int {
+	var exitErr *exitError
+	switch {
+	case err == nil:
+		return exitOK
+	case ctx.Err() != nil:
+		return exitInterrupted
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	default:
+		// Only errors cobra raises before a command runs are left
+		// unwrapped, see markRuntimeErrors
+		return exitUsage
+	}
+}
+
+// markRuntimeErrors wraps the errors returned by the RunE of cmd and its
+// subcommands in an exitError, so that exitCode can tell them apart from
+// usage errors
+func markRuntimeErrors(cmd *cobra.Command) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			err := run(cmd, args)
+			var exitErr *exitError
+			if err != nil && !errors.As(err, &exitErr) {
+				err = &exitError{code: exitGeneric, err: err}
+			}
+			return err
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markRuntimeErrors(sub)
+	}
+}
+
+// statusExitError returns the error for a finished operation that did not
+// fully succeed, or nil
+func statusExitError(taskID d3.MainTaskID, status *d3.StatusResponse) error {
+	switch status.OperationStatus {
+	case d3.StatusFailed:
+		return &exitError{code: exitOperationFailed, err: fmt.Errorf("operation %s failed", taskID)}
+	case d3.StatusPartiallyCompleted:
+		return &exitError{code: exitPartial, err: fmt.Errorf("operation %s partially completed", taskID)}
+	case d3.StatusCancelled, "canceled":
+		return &exitError{code: exitCancelled, err: fmt.Errorf("operation %s was cancelled", taskID)}
+	}
+	return nil
+}
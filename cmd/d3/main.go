@@ -0,0 +1,41 @@
+// Command d3 uploads files to the D3 API, starts operations on them and
+// downloads the results, so the service can be used from a shell.
+//
+//	d3 upload report.docx
+//	d3 convert --to pdf <file-key>
+//	d3 status <task-id>
+//	d3 watch <task-id>
+//	d3 download <task-id> -o out/
+//	d3 doctor
+//
+// The API key is read from --api-key, else from D3_API_KEY, else from the
+// configuration file named by --config. Progress bars are drawn on standard
+// error when it is a terminal. The exit status is 0 on success, 1 on errors,
+// 2 on usage errors, 3, 4 or 5 when an operation failed, partially completed
+// or was cancelled, 6 when watch timed out and 130 when interrupted. With
+// --json, results are printed as JSON Lines, see output.go.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cmd := newRootCmd()
+	err := cmd.ExecuteContext(ctx)
+	code := exitCode(ctx, err)
+	if err != nil {
+		if asJSON, _ := cmd.PersistentFlags().GetBool("json"); asJSON {
+			writeJSON(os.Stderr, errorRecord{Error: err.Error(), ExitCode: code})
+		} else {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+	}
+	os.Exit(code)
+}
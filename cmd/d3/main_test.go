@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the developer's own profiles out of the tests
+	dir, err := os.MkdirTemp("", "d3-cli-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Unsetenv("D3_PROFILE")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newTestServer fakes the API endpoints used by the CLI. Operations finish
+// immediately with one converted file served from /files/report.pdf.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" && r.URL.Path != "/files/report.pdf" && !strings.HasPrefix(r.URL.Path, "/part/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/do":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["action"] != "convert" || body["parameters"].(map[string]interface{})["convert_to"] != "pdf" {
+				t.Errorf("Unexpected operation request: %v", body)
+			}
+			w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+		case r.URL.Path == "/v1/biz/status/task-123":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"operation_status": "completed",
+					"files_data": []map[string]interface{}{{
+						"file_key":      "file-key-123",
+						"status":        "completed",
+						"download_link": server.URL + "/files/report.pdf",
+					}},
+				},
+			})
+		case r.URL.Path == "/files/report.pdf":
+			w.Write([]byte("%PDF-1.7"))
+		default:
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// run executes the CLI with args and returns its standard output
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestCLI_UploadConvertStatusDownload(t *testing.T) {
+	server := newTestServer(t)
+	t.Setenv("D3_API_KEY", "test-key")
+	t.Setenv("D3_BASE_URL", server.URL)
+
+	file := filepath.Join(t.TempDir(), "report.docx")
+	if err := os.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	out, err := run(t, "upload", file)
+	if err != nil || out != "file-key-123\n" {
+		t.Fatalf("Expected upload to print the file key, got %q, %v", out, err)
+	}
+
+	out, err = run(t, "convert", "--to", "pdf", "file-key-123")
+	if err != nil || out != "task-123\n" {
+		t.Fatalf("Expected convert to print the task ID, got %q, %v", out, err)
+	}
+
+	out, err = run(t, "status", "task-123")
+	want := "completed\nfile-key-123\tcompleted\t" + server.URL + "/files/report.pdf\n"
+	if err != nil || out != want {
+		t.Fatalf("Expected status output %q, got %q, %v", want, out, err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	out, err = run(t, "download", "task-123", "-o", dir)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	dest := filepath.Join(dir, "report.pdf")
+	if out != dest+"\n" {
+		t.Errorf("Expected download to print %q, got %q", dest, out)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "%PDF-1.7" {
+		t.Errorf("Expected the downloaded content, got %q", data)
+	}
+}
+
+func TestCLI_DownloadUsesContentDisposition(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/biz/status/task-123":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"operation_status": "completed",
+					"files_data": []map[string]interface{}{{
+						"file_key":      "file-key-123",
+						"status":        "completed",
+						"download_link": server.URL + "/files/abc123",
+					}},
+				},
+			})
+		case "/files/abc123":
+			w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+			w.Write([]byte("%PDF-1.7"))
+		}
+	}))
+	defer server.Close()
+	t.Setenv("D3_API_KEY", "test-key")
+	t.Setenv("D3_BASE_URL", server.URL)
+
+	dir := t.TempDir()
+	out, err := run(t, "download", "task-123", "-o", dir)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	dest := filepath.Join(dir, "report.pdf")
+	if out != dest+"\n" {
+		t.Errorf("Expected the Content-Disposition name %q, got %q", dest, out)
+	}
+}
+
+func TestCLI_APIKeyFlagOverridesEnvironment(t *testing.T) {
+	server := newTestServer(t)
+	t.Setenv("D3_API_KEY", "wrong-key")
+
+	if _, err := run(t, "status", "task-123", "--base-url", server.URL); err == nil {
+		t.Error("Expected the environment key to be rejected")
+	}
+	if _, err := run(t, "status", "task-123", "--base-url", server.URL, "--api-key", "test-key"); err != nil {
+		t.Errorf("Expected --api-key to take precedence, got %v", err)
+	}
+}
+
+func TestCLI_Errors(t *testing.T) {
+	t.Setenv("D3_API_KEY", "")
+	t.Setenv("D3_BASE_URL", "")
+
+	if _, err := run(t, "status", "task-123"); err == nil || !strings.Contains(err.Error(), "no API key") {
+		t.Errorf("Expected a missing API key error, got %v", err)
+	}
+	if _, err := run(t, "convert", "--api-key", "k", "file-key"); err == nil || !strings.Contains(err.Error(), "to") {
+		t.Errorf("Expected --to to be required, got %v", err)
+	}
+	if _, err := run(t, "upload"); err == nil {
+		t.Error("Expected upload without files to fail")
+	}
+}
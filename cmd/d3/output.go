@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// With --json, commands print one JSON object per line (JSON Lines) on
+// standard output, and errors as an errorRecord on standard error. The
+// records below are the CLI's machine-readable interface: fields may be
+// added, but are never renamed or removed.
+
+// uploadRecord is printed by upload for each file
+type uploadRecord struct {
+	File    string     `json:"file"`
+	FileKey d3.FileKey `json:"file_key"`
+}
+
+// operationRecord is printed by convert
+type operationRecord struct {
+	MainTaskID d3.MainTaskID `json:"main_task_id"`
+}
+
+// statusRecord is printed by status, and by watch whenever the status of
+// the operation or one of its files changes
+type statusRecord struct {
+	MainTaskID      d3.MainTaskID `json:"main_task_id"`
+	OperationStatus string        `json:"operation_status"`
+	Files           []fileRecord  `json:"files"`
+}
+
+// fileRecord is a file of a statusRecord
+type fileRecord struct {
+	FileKey      d3.FileKey `json:"file_key"`
+	Status       string     `json:"status"`
+	DownloadLink string     `json:"download_link,omitempty"`
+	ErrorCode    string     `json:"error_code,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+}
+
+// downloadRecord is printed by download for each file written
+type downloadRecord struct {
+	FileKey d3.FileKey `json:"file_key"`
+	Path    string     `json:"path"`
+}
+
+// runRecord is printed by run when the workflow completes
+type runRecord struct {
+	Outputs []string        `json:"outputs"`
+	TaskIDs []d3.MainTaskID `json:"task_ids"`
+	Resumed int             `json:"resumed"`
+}
+
+// doctorRecord is printed by doctor
+type doctorRecord struct {
+	BaseURL string `json:"base_url"`
+	OK      bool   `json:"ok"`
+	// ClockSkewSeconds is the API's clock minus the local clock
+	ClockSkewSeconds float64       `json:"clock_skew_seconds"`
+	Checks           []checkRecord `json:"checks"`
+}
+
+// checkRecord is a check of a doctorRecord
+type checkRecord struct {
+	Name string `json:"name"`
+	// Status is "ok", "fail" or "skip"
+	Status    string `json:"status"`
+	Detail    string `json:"detail"`
+	Hint      string `json:"hint,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// errorRecord is printed on standard error when a command fails
+type errorRecord struct {
+	Error    string `json:"error"`
+	ExitCode int    `json:"exit_code"`
+}
+
+func newStatusRecord(taskID d3.MainTaskID, status *d3.StatusResponse) statusRecord {
+	record := statusRecord{
+		MainTaskID:      taskID,
+		OperationStatus: status.OperationStatus,
+		Files:           make([]fileRecord, len(status.FilesData)),
+	}
+	for i, file := range status.FilesData {
+		record.Files[i] = fileRecord{
+			FileKey:      file.FileKey,
+			Status:       file.Status,
+			DownloadLink: file.DownloadLink,
+			ErrorCode:    file.ErrorCode,
+			ErrorMessage: file.ErrorMessage,
+		}
+	}
+	return record
+}
+
+func newDoctorRecord(report *d3.HealthReport) doctorRecord {
+	record := doctorRecord{
+		BaseURL:          report.BaseURL,
+		OK:               report.OK(),
+		ClockSkewSeconds: report.ClockSkew.Seconds(),
+		Checks:           make([]checkRecord, len(report.Checks)),
+	}
+	for i, check := range report.Checks {
+		record.Checks[i] = checkRecord{
+			Name:      check.Name,
+			Status:    checkStatus(check),
+			Detail:    check.Detail,
+			Hint:      check.Hint,
+			LatencyMS: check.Latency.Milliseconds(),
+		}
+	}
+	return record
+}
+
+// writeJSON writes record as a single line of JSON
+func writeJSON(w io.Writer, record interface{}) error {
+	return json.NewEncoder(w).Encode(record)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+func TestCLI_JSONOutput(t *testing.T) {
+	server := newTestServer(t)
+	t.Setenv("D3_API_KEY", "test-key")
+	t.Setenv("D3_BASE_URL", server.URL)
+
+	file := filepath.Join(t.TempDir(), "report.docx")
+	if err := os.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), "out")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"upload", file}, `{"file":"` + file + `","file_key":"file-key-123"}`},
+		{[]string{"convert", "--to", "pdf", "file-key-123"}, `{"main_task_id":"task-123"}`},
+		{[]string{"status", "task-123"}, `{"main_task_id":"task-123","operation_status":"completed","files":[{"file_key":"file-key-123","status":"completed","download_link":"` + server.URL + `/files/report.pdf"}]}`},
+		{[]string{"download", "task-123", "-o", dir}, `{"file_key":"file-key-123","path":"` + filepath.Join(dir, "report.pdf") + `"}`},
+	}
+	for _, tt := range tests {
+		out, err := run(t, append(tt.args, "--json")...)
+		if err != nil {
+			t.Fatalf("%s failed: %v", tt.args[0], err)
+		}
+		if strings.TrimSuffix(out, "\n") != tt.want {
+			t.Errorf("Expected %s to print %s, got %s", tt.args[0], tt.want, out)
+		}
+		if !json.Valid([]byte(out)) {
+			t.Errorf("Expected valid JSON from %s, got %s", tt.args[0], out)
+		}
+	}
+}
+
+func TestStatusWatcher_JSON(t *testing.T) {
+	var out bytes.Buffer
+	watcher := &statusWatcher{w: &out, taskID: "task-123", json: true, files: map[d3.FileKey]string{}}
+	running := d3.StatusResponse{OperationStatus: "running", FilesData: []d3.FileTaskStatus{{FileKey: "a", Status: "running"}}}
+	watcher.update(running)
+	watcher.update(running)
+	watcher.update(d3.StatusResponse{OperationStatus: "completed", FilesData: []d3.FileTaskStatus{{FileKey: "a", Status: "completed"}}})
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one line per change, got %q", out.String())
+	}
+	var record statusRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("Expected a status record, got %v", err)
+	}
+	if record.MainTaskID != "task-123" || record.OperationStatus != "completed" || record.Files[0].Status != "completed" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+}
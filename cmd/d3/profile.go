@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// profilesFile is the format of the CLI configuration file:
+//
+//	default_profile: dev
+//	profiles:
+//	  dev:
+//	    api_key: ${D3_DEV_KEY}
+//	    environment: development
+//	  prod:
+//	    api_key: sk-live-...
+//	    config: ~/.config/d3/prod.yaml
+//	    defaults:
+//	      to: pdf
+//	      output: ~/Downloads
+type profilesFile struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]profile `yaml:"profiles"`
+}
+
+// profile is a named set of settings selected with --profile
+type profile struct {
+	APIKey      string `yaml:"api_key"`
+	BaseURL     string `yaml:"base_url"`
+	Environment string `yaml:"environment"`
+	// Config is a client configuration file for further settings, see
+	// d3.LoadConfig
+	Config string `yaml:"config"`
+	// Defaults are flag values used when a flag is not given, by flag name
+	Defaults map[string]string `yaml:"defaults"`
+}
+
+// defaultProfilesPath returns $XDG_CONFIG_HOME/d3/config, or
+// ~/.config/d3/config
+func defaultProfilesPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "d3", "config")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "d3", "config")
+}
+
+// loadProfile returns the profile named name from the file at path, or the
+// file's default profile, or the profile named "default", when name is
+// empty. A missing file or default profile yields nil; a missing named
+// profile is an error.
+func loadProfile(path, name string) (*profile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	data = []byte(os.Expand(string(data), func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return os.Getenv(name)
+	}))
+
+	var file profilesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse profiles %s: %w", path, err)
+	}
+
+	explicit := name != ""
+	if name == "" {
+		name = file.DefaultProfile
+	}
+	if name == "" {
+		name = "default"
+	}
+	p, ok := file.Profiles[name]
+	if !ok {
+		if !explicit && file.DefaultProfile == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("profile %q not found in %s (have: %s)", name, path, profileNames(file.Profiles))
+	}
+	if p.Config != "" {
+		p.Config = expandHome(p.Config)
+		if !filepath.IsAbs(p.Config) {
+			p.Config = filepath.Join(filepath.Dir(path), p.Config)
+		}
+	}
+	return &p, nil
+}
+
+// applyDefaults sets the flags of cmd that were not given to the profile's
+// defaults. Defaults for flags cmd does not have are ignored, so one
+// profile can hold defaults for several commands.
+func (p *profile) applyDefaults(cmd *cobra.Command) error {
+	if p == nil {
+		return nil
+	}
+	for name, value := range p.Defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if flag.Value.Type() == "string" {
+			value = expandHome(value)
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("invalid default for --%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// profileNames lists the profile names in order, for error messages
+func profileNames(profiles map[string]profile) string {
+	if len(profiles) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProfiles(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write profiles: %v", err)
+	}
+	return path
+}
+
+func TestCLI_Profiles(t *testing.T) {
+	server := newTestServer(t)
+	t.Setenv("D3_API_KEY", "")
+	t.Setenv("D3_BASE_URL", "")
+	t.Setenv("D3_TEST_KEY", "test-key")
+	path := writeProfiles(t, `
+default_profile: dev
+profiles:
+  dev:
+    api_key: wrong-key
+    base_url: `+server.URL+`
+  prod:
+    api_key: ${D3_TEST_KEY}
+    base_url: `+server.URL+`
+    defaults:
+      to: pdf
+      interval: 1ms
+`)
+
+	if _, err := run(t, "status", "task-123", "--profiles", path); err == nil {
+		t.Error("Expected the default profile's key to be used and rejected")
+	}
+	if _, err := run(t, "status", "task-123", "--profiles", path, "--profile", "prod"); err != nil {
+		t.Errorf("Expected the prod profile to be used, got %v", err)
+	}
+
+	// defaults.to satisfies the required --to flag
+	out, err := run(t, "convert", "file-key-123", "--profiles", path, "--profile", "prod")
+	if err != nil || out != "task-123\n" {
+		t.Errorf("Expected convert to use the profile's --to, got %q, %v", out, err)
+	}
+
+	t.Setenv("D3_PROFILE", "prod")
+	if _, err := run(t, "status", "task-123", "--profiles", path); err != nil {
+		t.Errorf("Expected D3_PROFILE to select the profile, got %v", err)
+	}
+	if _, err := run(t, "status", "task-123", "--profiles", path, "--api-key", "wrong-key"); err == nil {
+		t.Error("Expected --api-key to override the profile")
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "config")
+	if p, err := loadProfile(missing, ""); p != nil || err != nil {
+		t.Errorf("Expected no profile for a missing file, got %+v, %v", p, err)
+	}
+	if _, err := loadProfile(missing, "prod"); err == nil {
+		t.Error("Expected an error for a named profile in a missing file")
+	}
+
+	path := writeProfiles(t, "profiles:\n  default:\n    api_key: k\n    config: prod.yaml\n  dev:\n    api_key: d\n")
+	p, err := loadProfile(path, "")
+	if err != nil || p == nil || p.APIKey != "k" {
+		t.Fatalf("Expected the profile named default, got %+v, %v", p, err)
+	}
+	if want := filepath.Join(filepath.Dir(path), "prod.yaml"); p.Config != want {
+		t.Errorf("Expected config %q, got %q", want, p.Config)
+	}
+
+	_, err = loadProfile(path, "staging")
+	if err == nil || !strings.Contains(err.Error(), "default, dev") {
+		t.Errorf("Expected an unknown profile error listing the profiles, got %v", err)
+	}
+
+	path = writeProfiles(t, "profiles:\n  default:\n    apikey: k\n")
+	if _, err := loadProfile(path, ""); err == nil {
+		t.Error("Expected unknown settings to be rejected")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+// globalFlags are the persistent flags shared by every subcommand
+type globalFlags struct {
+	apiKey     string
+	baseURL    string
+	configPath string
+	noProgress bool
+	json       bool
+
+	profileName  string
+	profilesPath string
+	// profile is loaded before any subcommand runs; nil when none applies
+	profile *profile
+}
+
+func newRootCmd() *cobra.Command {
+	flags := &globalFlags{}
+	cmd := &cobra.Command{
+		Use:           "d3",
+		Short:         "Upload, convert and download files with the D3 API",
+		Version:       d3.Version,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			p, err := loadProfile(flags.profilesPath, flags.profileName)
+			if err != nil {
+				return &exitError{code: exitGeneric, err: err}
+			}
+			flags.profile = p
+			return p.applyDefaults(cmd)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&flags.apiKey, "api-key", "", "API key (default: $D3_API_KEY)")
+	cmd.PersistentFlags().StringVar(&flags.baseURL, "base-url", "", "API base URL (default: $D3_BASE_URL)")
+	cmd.PersistentFlags().StringVar(&flags.configPath, "config", "", "client configuration file (JSON or YAML)")
+	cmd.PersistentFlags().StringVar(&flags.profileName, "profile", os.Getenv("D3_PROFILE"), "profile to use from the profiles file (default: $D3_PROFILE)")
+	cmd.PersistentFlags().StringVar(&flags.profilesPath, "profiles", defaultProfilesPath(), "profiles file")
+	cmd.PersistentFlags().BoolVar(&flags.noProgress, "no-progress", false, "do not draw progress bars")
+	cmd.PersistentFlags().BoolVar(&flags.json, "json", false, "print results as JSON, one object per line")
+
+	cmd.AddCommand(
+		newUploadCmd(flags),
+		newConvertCmd(flags),
+		newStatusCmd(flags),
+		newDownloadCmd(flags),
+		newWatchCmd(flags),
+		newRunCmd(flags),
+		newDoctorCmd(flags),
+	)
+	markRuntimeErrors(cmd)
+	return cmd
+}
+
+// newProgressBar returns a progress bar drawn on w, or nil (drawing
+// nothing) when w is not a terminal or --no-progress is set
+func (f *globalFlags) newProgressBar(w io.Writer, label string) *d3.ProgressBar {
+	if f.noProgress || !d3.IsTerminal(w) {
+		return nil
+	}
+	return d3.NewProgressBar(w, label)
+}
+
+// newClient builds a client from the configuration file, then the
+// profile, then the environment, then the flags, each overriding the
+// previous
+func (f *globalFlags) newClient() (*d3.Dragdropdo, error) {
+	configPath := f.configPath
+	if configPath == "" && f.profile != nil {
+		configPath = f.profile.Config
+	}
+	var config d3.Config
+	if configPath != "" {
+		var err error
+		if config, err = d3.LoadConfig(configPath); err != nil {
+			return nil, err
+		}
+	}
+	if p := f.profile; p != nil {
+		if p.APIKey != "" {
+			config.APIKey = p.APIKey
+		}
+		if p.Environment != "" {
+			config.Environment = d3.Environment(p.Environment)
+			config.BaseURL = ""
+		}
+		if p.BaseURL != "" {
+			config.BaseURL = p.BaseURL
+		}
+	}
+	if key := os.Getenv("D3_API_KEY"); key != "" {
+		config.APIKey = key
+	}
+	if baseURL := os.Getenv("D3_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	if f.apiKey != "" {
+		config.APIKey = f.apiKey
+	}
+	if f.baseURL != "" {
+		config.BaseURL = f.baseURL
+	}
+	if config.UserAgentSuffix == "" {
+		config.UserAgentSuffix = "d3-cli"
+	}
+	if config.APIKey == "" && config.Credentials == nil {
+		return nil, errors.New("no API key: set --api-key, D3_API_KEY or a profile")
+	}
+	return d3.NewDragdropdo(config)
+}
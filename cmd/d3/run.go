@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/dragdropdo/dragdropdo-sdk-go/d3workflow"
+	"github.com/spf13/cobra"
+)
+
+func newRunCmd(flags *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "run WORKFLOW",
+		Short: "Run a workflow file, resuming an interrupted run, and print its outputs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflow, err := d3workflow.Load(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close(cmd.Context())
+
+			result, err := d3workflow.NewRunner(client).Run(cmd.Context(), workflow)
+			if err != nil {
+				return err
+			}
+			if flags.json {
+				return writeJSON(cmd.OutOrStdout(), runRecord{Outputs: result.Outputs, TaskIDs: result.TaskIDs, Resumed: result.Resumed})
+			}
+			for _, output := range result.Outputs {
+				fmt.Fprintln(cmd.OutOrStdout(), output)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCLI_RunWorkflow(t *testing.T) {
+	server := newTestServer(t)
+	t.Setenv("D3_API_KEY", "test-key")
+	t.Setenv("D3_BASE_URL", server.URL)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.docx"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	path := filepath.Join(dir, "workflow.yaml")
+	workflow := "inputs: [report.docx]\nsteps:\n  - action: convert\n    parameters: {convert_to: pdf}\noutput: out\n"
+	if err := os.WriteFile(path, []byte(workflow), 0644); err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+
+	out, err := run(t, "run", path)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	dest := filepath.Join(dir, "out", "report.pdf")
+	if out != dest+"\n" {
+		t.Errorf("Expected run to print %q, got %q", dest, out)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "%PDF-1.7" {
+		t.Errorf("Expected the converted content, got %q", data)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd(flags *globalFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status TASK_ID",
+		Short: "Print the status of an operation and of each of its files",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close(cmd.Context())
+
+			taskID := d3.MainTaskID(args[0])
+			status, err := client.GetStatus(d3.StatusOptions{MainTaskID: taskID})
+			if err != nil {
+				return err
+			}
+			if flags.json {
+				return writeJSON(cmd.OutOrStdout(), newStatusRecord(taskID, status))
+			}
+			printStatus(cmd.OutOrStdout(), status)
+			return nil
+		},
+	}
+}
+
+// printStatus writes the operation status, then one tab-separated line per
+// file: file key, status, and the download link or error message
+func printStatus(w io.Writer, status *d3.StatusResponse) {
+	fmt.Fprintln(w, status.OperationStatus)
+	for _, file := range status.FilesData {
+		printFileStatus(w, file)
+	}
+}
+
+// printFileStatus writes a file's line of printStatus
+func printFileStatus(w io.Writer, file d3.FileTaskStatus) {
+	detail := file.DownloadLink
+	if file.ErrorMessage != "" {
+		detail = file.ErrorMessage
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\n", file.FileKey, file.Status, detail)
+}
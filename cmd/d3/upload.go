@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+func newUploadCmd(flags *globalFlags) *cobra.Command {
+	var (
+		name     string
+		folderID string
+	)
+	cmd := &cobra.Command{
+		Use:   "upload FILE...",
+		Short: "Upload files and print their file keys, one per line",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name != "" && len(args) > 1 {
+				return fmt.Errorf("--name requires a single file")
+			}
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			defer client.Close(cmd.Context())
+
+			for _, file := range args {
+				fileName := name
+				if fileName == "" {
+					fileName = filepath.Base(file)
+				}
+				bar := flags.newProgressBar(cmd.ErrOrStderr(), fileName)
+				upload, err := client.UploadFileContext(cmd.Context(), d3.UploadFileOptions{
+					File:       file,
+					FileName:   fileName,
+					FolderID:   folderID,
+					OnProgress: bar.Upload,
+				})
+				bar.Finish()
+				if err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+				if flags.json {
+					if err := writeJSON(cmd.OutOrStdout(), uploadRecord{File: file, FileKey: upload.FileKey}); err != nil {
+						return err
+					}
+					continue
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), upload.FileKey)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "file name to store (default: the file's base name)")
+	cmd.Flags().StringVar(&folderID, "folder", "", "folder ID to upload into")
+	return cmd
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/spf13/cobra"
+)
+
+func newWatchCmd(flags *globalFlags) *cobra.Command {
+	var interval, timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "watch TASK_ID",
+		Short: "Print status changes of an operation until it finishes",
+		Long: `Poll an operation and print its status, and each file's line as printed
+by status, whenever they change. The exit status is 0 when the operation
+completed, 3 when it failed, 4 when it partially completed, 5 when it was
+cancelled and 6 when --timeout passed first.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := flags.newClient()
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			defer client.Close(ctx)
+			// Closing the client with a done context cancels the poll
+			stop := context.AfterFunc(ctx, func() { client.Close(ctx) })
+			defer stop()
+
+			taskID := d3.MainTaskID(args[0])
+			watcher := &statusWatcher{w: cmd.OutOrStdout(), taskID: taskID, json: flags.json, files: map[d3.FileKey]string{}}
+			status, err := client.PollStatus(d3.PollStatusOptions{
+				StatusOptions: d3.StatusOptions{MainTaskID: taskID},
+				Interval:      interval,
+				Timeout:       timeout,
+				OnUpdate:      watcher.update,
+			})
+			if err != nil {
+				if errors.Is(err, d3.ErrPollTimeout) {
+					return &exitError{code: exitTimeout, err: err}
+				}
+				return err
+			}
+			return statusExitError(taskID, status)
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", d3.DefaultPollInterval, "time between status checks")
+	cmd.Flags().DurationVar(&timeout, "timeout", d3.DefaultPollTimeout, "give up after this long")
+	return cmd
+}
+
+// statusWatcher prints the parts of successive statuses that changed, or
+// with json the whole status whenever a part changed
+type statusWatcher struct {
+	w         io.Writer
+	taskID    d3.MainTaskID
+	json      bool
+	operation string
+	files     map[d3.FileKey]string
+}
+
+func (s *statusWatcher) update(status d3.StatusResponse) {
+	changed := status.OperationStatus != s.operation
+	if changed {
+		s.operation = status.OperationStatus
+		if !s.json {
+			io.WriteString(s.w, status.OperationStatus+"\n")
+		}
+	}
+	for _, file := range status.FilesData {
+		if s.files[file.FileKey] != file.Status {
+			s.files[file.FileKey] = file.Status
+			changed = true
+			if !s.json {
+				printFileStatus(s.w, file)
+			}
+		}
+	}
+	if changed && s.json {
+		writeJSON(s.w, newStatusRecord(s.taskID, &status))
+	}
+}
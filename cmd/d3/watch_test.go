@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// newStatusServer serves the given statuses of task-123 in turn, repeating
+// the last one
+func newStatusServer(t *testing.T, statuses ...map[string]interface{}) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": status})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func fileStatus(key, status string) map[string]interface{} {
+	return map[string]interface{}{"file_key": key, "status": status}
+}
+
+func TestCLI_WatchPrintsTransitions(t *testing.T) {
+	server := newStatusServer(t,
+		map[string]interface{}{"operation_status": "queued", "files_data": []interface{}{fileStatus("a", "queued"), fileStatus("b", "queued")}},
+		map[string]interface{}{"operation_status": "running", "files_data": []interface{}{fileStatus("a", "completed"), fileStatus("b", "queued")}},
+		map[string]interface{}{"operation_status": "running", "files_data": []interface{}{fileStatus("a", "completed"), fileStatus("b", "queued")}},
+		map[string]interface{}{"operation_status": "completed", "files_data": []interface{}{fileStatus("a", "completed"), fileStatus("b", "completed")}},
+	)
+
+	out, err := run(t, "watch", "task-123", "--api-key", "k", "--base-url", server.URL, "--interval", "1ms")
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	want := "queued\na\tqueued\t\nb\tqueued\t\nrunning\na\tcompleted\t\ncompleted\nb\tcompleted\t\n"
+	if out != want {
+		t.Errorf("Expected output %q, got %q", want, out)
+	}
+}
+
+func TestCLI_WatchExitCodes(t *testing.T) {
+	tests := []struct {
+		status string
+		want   int
+	}{
+		{"completed", exitOK},
+		{"failed", exitOperationFailed},
+		{"partially_completed", exitPartial},
+		{"cancelled", exitCancelled},
+		{"running", exitTimeout},
+	}
+	for _, tt := range tests {
+		server := newStatusServer(t, map[string]interface{}{"operation_status": tt.status})
+		_, err := run(t, "watch", "task-123", "--api-key", "k", "--base-url", server.URL, "--interval", "1ms", "--timeout", "5ms")
+		if got := exitCode(context.Background(), err); got != tt.want {
+			t.Errorf("Expected exit status %d for %s, got %d (%v)", tt.want, tt.status, got, err)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := exitCode(context.Background(), nil); got != exitOK {
+		t.Errorf("Expected %d for no error, got %d", exitOK, got)
+	}
+
+	_, err := run(t, "status")
+	if got := exitCode(context.Background(), err); got != exitUsage {
+		t.Errorf("Expected %d for missing arguments, got %d", exitUsage, got)
+	}
+	_, err = run(t, "status", "task-123", "--bogus")
+	if got := exitCode(context.Background(), err); got != exitUsage {
+		t.Errorf("Expected %d for an unknown flag, got %d", exitUsage, got)
+	}
+
+	t.Setenv("D3_API_KEY", "")
+	_, err = run(t, "status", "task-123")
+	if got := exitCode(context.Background(), err); got != exitGeneric {
+		t.Errorf("Expected %d for a runtime error, got %d", exitGeneric, got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := exitCode(ctx, errors.New("context canceled")); got != exitInterrupted {
+		t.Errorf("Expected %d when interrupted, got %d", exitInterrupted, got)
+	}
+}
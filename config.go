@@ -0,0 +1,183 @@
+package d3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileConfig is the configuration file format read by LoadConfig
+type fileConfig struct {
+	APIKey                string            `json:"api_key" yaml:"api_key"`
+	APIKeyEnv             string            `json:"api_key_env" yaml:"api_key_env"`
+	APIKeyFile            string            `json:"api_key_file" yaml:"api_key_file"`
+	AuthScheme            string            `json:"auth_scheme" yaml:"auth_scheme"`
+	BaseURL               string            `json:"base_url" yaml:"base_url"`
+	Environment           string            `json:"environment" yaml:"environment"`
+	Timeout               string            `json:"timeout" yaml:"timeout"`
+	DialTimeout           string            `json:"dial_timeout" yaml:"dial_timeout"`
+	TLSHandshakeTimeout   string            `json:"tls_handshake_timeout" yaml:"tls_handshake_timeout"`
+	ResponseHeaderTimeout string            `json:"response_header_timeout" yaml:"response_header_timeout"`
+	UploadPartTimeout     string            `json:"upload_part_timeout" yaml:"upload_part_timeout"`
+	Headers               map[string]string `json:"headers" yaml:"headers"`
+	ProxyURL              string            `json:"proxy_url" yaml:"proxy_url"`
+	UserAgentSuffix       string            `json:"user_agent_suffix" yaml:"user_agent_suffix"`
+	ChunkSize             int64             `json:"chunk_size" yaml:"chunk_size"`
+	UploadConcurrency     int               `json:"upload_concurrency" yaml:"upload_concurrency"`
+	UploadAttempts        int               `json:"upload_attempts" yaml:"upload_attempts"`
+	StateFile             string            `json:"state_file" yaml:"state_file"`
+	UploadCacheTTL        string            `json:"upload_cache_ttl" yaml:"upload_cache_ttl"`
+	MaxFileSize           int64             `json:"max_file_size" yaml:"max_file_size"`
+	CheckPlanFileSize     bool              `json:"check_plan_file_size" yaml:"check_plan_file_size"`
+	PreflightValidation   bool              `json:"preflight_validation" yaml:"preflight_validation"`
+	Retry                 struct {
+		MaxAttempts   int    `json:"max_attempts" yaml:"max_attempts"`
+		BaseBackoff   string `json:"base_backoff" yaml:"base_backoff"`
+		MaxBackoff    string `json:"max_backoff" yaml:"max_backoff"`
+		MaxRetryAfter string `json:"max_retry_after" yaml:"max_retry_after"`
+	} `json:"retry" yaml:"retry"`
+}
+
+// LoadConfig reads a client configuration from a JSON (.json) or YAML
+// (.yaml, .yml) file. ${VAR} and $VAR references are expanded from the
+// environment before parsing ($$ yields a literal $). The API key is taken
+// from api_key, else from the environment variable named by api_key_env,
+// else from the file named by api_key_file (relative to the config file).
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	data = []byte(os.Expand(string(data), func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return os.Getenv(name)
+	}))
+
+	var fc fileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&fc)
+	case ".yaml", ".yml":
+		err = unmarshalYAML(data, &fc)
+	default:
+		return Config{}, fmt.Errorf("unsupported config format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return fc.toConfig(filepath.Dir(path))
+}
+
+// toConfig converts the file format into a Config, resolving relative paths
+// against dir
+func (fc *fileConfig) toConfig(dir string) (Config, error) {
+	config := Config{
+		APIKey:              fc.APIKey,
+		AuthScheme:          AuthScheme(fc.AuthScheme),
+		BaseURL:             fc.BaseURL,
+		Environment:         Environment(fc.Environment),
+		Headers:             fc.Headers,
+		ProxyURL:            fc.ProxyURL,
+		UserAgentSuffix:     fc.UserAgentSuffix,
+		ChunkSize:           fc.ChunkSize,
+		UploadConcurrency:   fc.UploadConcurrency,
+		UploadAttempts:      fc.UploadAttempts,
+		MaxFileSize:         fc.MaxFileSize,
+		CheckPlanFileSize:   fc.CheckPlanFileSize,
+		PreflightValidation: fc.PreflightValidation,
+	}
+
+	if config.APIKey == "" && fc.APIKeyEnv != "" {
+		config.APIKey = os.Getenv(fc.APIKeyEnv)
+	}
+	if config.APIKey == "" && fc.APIKeyFile != "" {
+		keyPath := fc.APIKeyFile
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(dir, keyPath)
+		}
+		key, err := os.ReadFile(keyPath)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read API key file: %w", err)
+		}
+		config.APIKey = strings.TrimSpace(string(key))
+	}
+
+	if fc.StateFile != "" {
+		statePath := fc.StateFile
+		if !filepath.IsAbs(statePath) {
+			statePath = filepath.Join(dir, statePath)
+		}
+		config.StateStore = NewFileStateStore(statePath)
+	}
+
+	var err error
+	if config.Timeout, err = parseConfigDuration("timeout", fc.Timeout); err != nil {
+		return Config{}, err
+	}
+	if config.DialTimeout, err = parseConfigDuration("dial_timeout", fc.DialTimeout); err != nil {
+		return Config{}, err
+	}
+	if config.TLSHandshakeTimeout, err = parseConfigDuration("tls_handshake_timeout", fc.TLSHandshakeTimeout); err != nil {
+		return Config{}, err
+	}
+	if config.ResponseHeaderTimeout, err = parseConfigDuration("response_header_timeout", fc.ResponseHeaderTimeout); err != nil {
+		return Config{}, err
+	}
+	if config.UploadPartTimeout, err = parseConfigDuration("upload_part_timeout", fc.UploadPartTimeout); err != nil {
+		return Config{}, err
+	}
+
+	if fc.UploadCacheTTL != "" {
+		ttl, err := parseConfigDuration("upload_cache_ttl", fc.UploadCacheTTL)
+		if err != nil {
+			return Config{}, err
+		}
+		config.UploadCache = NewMemoryUploadCache(ttl)
+	}
+
+	config.Retry.MaxAttempts = fc.Retry.MaxAttempts
+	if config.Retry.MaxRetryAfter, err = parseConfigDuration("retry.max_retry_after", fc.Retry.MaxRetryAfter); err != nil {
+		return Config{}, err
+	}
+	if fc.Retry.BaseBackoff != "" || fc.Retry.MaxBackoff != "" {
+		base, err := parseConfigDuration("retry.base_backoff", fc.Retry.BaseBackoff)
+		if err != nil {
+			return Config{}, err
+		}
+		max, err := parseConfigDuration("retry.max_backoff", fc.Retry.MaxBackoff)
+		if err != nil {
+			return Config{}, err
+		}
+		if base == 0 {
+			base = 500 * time.Millisecond
+		}
+		if max == 0 {
+			max = 30 * time.Second
+		}
+		config.Retry.Backoff = ExponentialBackoff(base, max)
+	}
+
+	return config, nil
+}
+
+// parseConfigDuration parses an optional duration setting such as "30s"
+func parseConfigDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return d, nil
+}
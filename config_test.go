@@ -0,0 +1,41 @@
+package d3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_JSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "key.txt"), []byte("file-key\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	path := filepath.Join(dir, "d3.json")
+	if err := os.WriteFile(path, []byte(`{"api_key_file": "key.txt", "base_url": "https://api.example.test", "dial_timeout": "5s", "upload_part_timeout": "10m", "state_file": "state.json"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.APIKey != "file-key" {
+		t.Errorf("Expected API key from file, got '%s'", config.APIKey)
+	}
+	if config.DialTimeout != 5*time.Second || config.UploadPartTimeout != 10*time.Minute {
+		t.Errorf("Expected dial and part timeouts, got %v and %v", config.DialTimeout, config.UploadPartTimeout)
+	}
+	if store, ok := config.StateStore.(*FileStateStore); !ok || store.path != filepath.Join(dir, "state.json") {
+		t.Errorf("Expected a state store in the config's directory, got %+v", config.StateStore)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"base_ulr": "typo"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !d3_stdlib
+
+package d3
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unmarshalYAML decodes YAML, rejecting unknown fields
+func unmarshalYAML(data []byte, v interface{}) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(v)
+}
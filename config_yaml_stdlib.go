@@ -0,0 +1,10 @@
+//go:build d3_stdlib
+
+package d3
+
+import "errors"
+
+// unmarshalYAML is unavailable in the zero-dependency build
+func unmarshalYAML(data []byte, v interface{}) error {
+	return errors.New("YAML configuration is not supported when built with d3_stdlib; use JSON")
+}
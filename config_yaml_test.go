@@ -0,0 +1,61 @@
+//go:build !d3_stdlib
+
+package d3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_YAML(t *testing.T) {
+	t.Setenv("D3_TEST_BASE_URL", "https://api.example.test")
+	t.Setenv("D3_TEST_KEY", "env-key")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "d3.yaml")
+	content := `
+api_key_env: D3_TEST_KEY
+base_url: ${D3_TEST_BASE_URL}
+timeout: 45s
+headers:
+  X-Tenant: tenant-$$1
+chunk_size: 8388608
+upload_concurrency: 4
+retry:
+  max_attempts: 3
+  max_retry_after: 10s
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if config.APIKey != "env-key" {
+		t.Errorf("Expected API key from environment, got '%s'", config.APIKey)
+	}
+	if config.BaseURL != "https://api.example.test" {
+		t.Errorf("Expected expanded base URL, got '%s'", config.BaseURL)
+	}
+	if config.Timeout != 45*time.Second {
+		t.Errorf("Expected 45s timeout, got %v", config.Timeout)
+	}
+	if config.Headers["X-Tenant"] != "tenant-$1" {
+		t.Errorf("Expected escaped dollar in header, got '%s'", config.Headers["X-Tenant"])
+	}
+	if config.ChunkSize != 8*1024*1024 || config.UploadConcurrency != 4 {
+		t.Errorf("Unexpected upload settings: %d, %d", config.ChunkSize, config.UploadConcurrency)
+	}
+	if config.Retry.MaxAttempts != 3 || config.Retry.MaxRetryAfter != 10*time.Second {
+		t.Errorf("Unexpected retry settings: %+v", config.Retry)
+	}
+
+	if _, err := NewDragdropdo(config); err != nil {
+		t.Errorf("Loaded config should create a client: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CredentialsProvider supplies the API key. It is evaluated for every API
+// request, so keys fetched from a secrets manager can change without
+// recreating the client.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// SetAPIKey atomically replaces the client's credentials with a static API
+// key, so long-running services can rotate keys without recreating the
+// client and losing its connection pools. Requests already in flight keep
+// the key they started with.
+func (c *Dragdropdo) SetAPIKey(apiKey string) error {
+	if apiKey == "" {
+		return errors.New("API key is required")
+	}
+	c.credentialsMu.Lock()
+	defer c.credentialsMu.Unlock()
+	c.credentials = StaticCredentials(apiKey)
+	return nil
+}
+
+// getCredentials returns the current credentials provider
+func (c *Dragdropdo) getCredentials() CredentialsProvider {
+	c.credentialsMu.RLock()
+	defer c.credentialsMu.RUnlock()
+	return c.credentials
+}
+
+// StaticCredentials always returns the same API key
+type StaticCredentials string
+
+// APIKey returns the static key
+func (s StaticCredentials) APIKey(ctx context.Context) (string, error) {
+	if s == "" {
+		return "", errors.New("static API key is empty")
+	}
+	return string(s), nil
+}
+
+// EnvCredentials reads the API key from the named environment variable
+type EnvCredentials string
+
+// APIKey returns the value of the environment variable
+func (e EnvCredentials) APIKey(ctx context.Context) (string, error) {
+	key := os.Getenv(string(e))
+	if key == "" {
+		return "", fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	return key, nil
+}
+
+// FileCredentials reads the API key from a file, e.g. a mounted secret. The
+// file is re-read on every request so rotated keys are picked up.
+type FileCredentials string
+
+// APIKey returns the trimmed contents of the file
+func (f FileCredentials) APIKey(ctx context.Context) (string, error) {
+	data, err := os.ReadFile(string(f))
+	if err != nil {
+		return "", fmt.Errorf("failed to read API key file: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("API key file %s is empty", string(f))
+	}
+	return key, nil
+}
+
+// CredentialsFunc adapts a function, e.g. a call to Vault or SSM, to a
+// CredentialsProvider
+type CredentialsFunc func(ctx context.Context) (string, error)
+
+// APIKey calls f
+func (f CredentialsFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// ChainCredentials returns a provider that tries providers in order and uses
+// the first key obtained without error
+func ChainCredentials(providers ...CredentialsProvider) CredentialsProvider {
+	return chainCredentials(providers)
+}
+
+type chainCredentials []CredentialsProvider
+
+func (c chainCredentials) APIKey(ctx context.Context) (string, error) {
+	errs := []string{}
+	for _, provider := range c {
+		key, err := provider.APIKey(ctx)
+		if err == nil && key != "" {
+			return key, nil
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) == 0 {
+		return "", errors.New("no credentials provider returned an API key")
+	}
+	return "", fmt.Errorf("no credentials provider returned an API key: %s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,102 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_CredentialsProvider(t *testing.T) {
+	expected := "Bearer key-1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != expected {
+			t.Errorf("Expected Authorization '%s', got '%s'", expected, r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	key := "key-1"
+	client, err := NewClient("",
+		WithBaseURL(server.URL),
+		WithCredentials(CredentialsFunc(func(ctx context.Context) (string, error) {
+			return key, nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	// The provider is evaluated per request
+	key = "key-2"
+	expected = "Bearer key-2"
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+}
+
+func TestChainCredentials(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("file-key\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	chain := ChainCredentials(
+		EnvCredentials("D3_TEST_UNSET_KEY"),
+		FileCredentials(keyFile),
+		StaticCredentials("static-key"),
+	)
+	key, err := chain.APIKey(context.Background())
+	if err != nil || key != "file-key" {
+		t.Errorf("Expected key from file, got '%s', %v", key, err)
+	}
+
+	failing := ChainCredentials(CredentialsFunc(func(ctx context.Context) (string, error) {
+		return "", errors.New("vault unavailable")
+	}))
+	if _, err := failing.APIKey(context.Background()); err == nil {
+		t.Error("Expected error when no provider returns a key")
+	}
+}
+
+func TestClient_SetAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("old-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var seen []string
+	client.OnBeforeRequest(func(req *RequestInfo) error {
+		seen = append(seen, req.Header.Get("Authorization"))
+		return nil
+	})
+
+	client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"})
+	if err := client.SetAPIKey("new-key"); err != nil {
+		t.Fatalf("Failed to set API key: %v", err)
+	}
+	client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"})
+
+	if len(seen) != 2 || seen[0] != "Bearer old-key" || seen[1] != "Bearer new-key" {
+		t.Errorf("Expected rotated key on second request, got %v", seen)
+	}
+
+	if err := client.SetAPIKey(""); err == nil {
+		t.Error("Expected error for empty API key")
+	}
+}
@@ -0,0 +1,64 @@
+package d3
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CurlCommand returns a curl command reproducing an API request. The API key
+// is replaced by $D3_API_KEY and URL signatures and passwords are redacted,
+// so the command is safe to paste into a support ticket.
+func CurlCommand(req *RequestInfo) string {
+	var b strings.Builder
+	b.WriteString("curl -X ")
+	b.WriteString(req.Method)
+	b.WriteString(" ")
+	b.WriteString(shellQuote(redactURL(req.URL)))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := redactHeader(req.Header)
+	for _, name := range names {
+		switch {
+		case name == "Authorization" && strings.HasPrefix(req.Header.Get(name), "Bearer "):
+			b.WriteString(` -H "Authorization: Bearer $D3_API_KEY"`)
+			continue
+		case name == "X-Api-Key":
+			b.WriteString(` -H "X-API-Key: $D3_API_KEY"`)
+			continue
+		}
+		for _, value := range header.Values(name) {
+			b.WriteString(" -H ")
+			b.WriteString(shellQuote(name + ": " + value))
+		}
+	}
+
+	if len(req.Body) > 0 {
+		b.WriteString(" --data-raw ")
+		b.WriteString(shellQuote(redactBody(req.Body, 0)))
+	}
+
+	return b.String()
+}
+
+// shellQuote single-quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// curlHook writes a curl command for each API request attempt to w
+func curlHook(w io.Writer) AfterResponseHook {
+	var mu sync.Mutex
+	return func(resp *ResponseInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintln(w, CurlCommand(resp.Request))
+	}
+}
@@ -0,0 +1,56 @@
+package d3
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_CurlWriter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client, err := NewClient("secret-api-key", WithBaseURL(server.URL), WithCurlWriter(&buf))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.LockPdf([]FileKey{"file-key-123"}, "it's-secret", nil); err != nil {
+		t.Fatalf("Failed to create operation: %v", err)
+	}
+
+	output := buf.String()
+	for _, expected := range []string{
+		"curl -X POST '" + server.URL + "/v1/biz/do'",
+		`-H "Authorization: Bearer $D3_API_KEY"`,
+		"-H 'Content-Type: application/json'",
+		`--data-raw '{"action":"lock"`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected curl command to contain %q, got: %s", expected, output)
+		}
+	}
+	for _, secret := range []string{"secret-api-key", "s-secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Curl command leaked %q: %s", secret, output)
+		}
+	}
+}
+
+func TestCurlCommand_Quoting(t *testing.T) {
+	cmd := CurlCommand(&RequestInfo{
+		Method: "GET",
+		URL:    "https://api.example.com/v1/biz/files/search?name_contains=o'brien",
+		Header: http.Header{},
+	})
+	expected := `curl -X GET 'https://api.example.com/v1/biz/files/search?name_contains=o'\''brien'`
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
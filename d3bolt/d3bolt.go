@@ -0,0 +1,135 @@
+// Package d3bolt stores D3 client state in a BoltDB database, so uploads
+// and operations survive crashes and restarts of a single process.
+//
+//	db, err := bolt.Open("d3-state.db", 0o600, nil)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	store, err := d3bolt.New(db)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := d3.NewDragdropdo(d3.Config{APIKey: apiKey, StateStore: store})
+package d3bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	bolt "go.etcd.io/bbolt"
+)
+
+var _ d3.StateStore = (*Store)(nil)
+
+var (
+	uploadsBucket = []byte("d3_uploads")
+	jobsBucket    = []byte("d3_jobs")
+)
+
+// Store is a d3.StateStore keeping its state in two buckets of a BoltDB
+// database, d3_uploads and d3_jobs
+type Store struct {
+	db *bolt.DB
+}
+
+// New creates the buckets in db if needed and returns a Store using them.
+// The caller keeps ownership of db and closes it.
+func New(db *bolt.DB) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{uploadsBucket, jobsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("d3bolt: failed to create buckets: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// SaveUpload implements d3.StateStore
+func (s *Store) SaveUpload(ctx context.Context, state d3.UploadState) error {
+	return s.put(uploadsBucket, state.File, state)
+}
+
+// LoadUpload implements d3.StateStore
+func (s *Store) LoadUpload(ctx context.Context, file string) (*d3.UploadState, error) {
+	var state *d3.UploadState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(uploadsBucket).Get([]byte(file))
+		if data == nil {
+			return nil
+		}
+		state = &d3.UploadState{}
+		return json.Unmarshal(data, state)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("d3bolt: failed to load upload: %w", err)
+	}
+	return state, nil
+}
+
+// DeleteUpload implements d3.StateStore
+func (s *Store) DeleteUpload(ctx context.Context, file string) error {
+	return s.delete(uploadsBucket, file)
+}
+
+// SaveJob implements d3.StateStore
+func (s *Store) SaveJob(ctx context.Context, state d3.JobState) error {
+	return s.put(jobsBucket, string(state.MainTaskID), state)
+}
+
+// LoadJobs implements d3.StateStore
+func (s *Store) LoadJobs(ctx context.Context) ([]d3.JobState, error) {
+	var jobs []d3.JobState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			var job d3.JobState
+			if err := json.Unmarshal(data, &job); err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("d3bolt: failed to load jobs: %w", err)
+	}
+	// Keys are in ID order; ties in submission time keep it
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt) })
+	return jobs, nil
+}
+
+// DeleteJob implements d3.StateStore
+func (s *Store) DeleteJob(ctx context.Context, mainTaskID d3.MainTaskID) error {
+	return s.delete(jobsBucket, string(mainTaskID))
+}
+
+func (s *Store) put(bucket []byte, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("d3bolt: failed to encode %s: %w", bucket, err)
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("d3bolt: failed to save to %s: %w", bucket, err)
+	}
+	return nil
+}
+
+func (s *Store) delete(bucket []byte, key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("d3bolt: failed to delete from %s: %w", bucket, err)
+	}
+	return nil
+}
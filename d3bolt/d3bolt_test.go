@@ -0,0 +1,23 @@
+package d3bolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dragdropdo/dragdropdo-sdk-go/d3test"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "state.db"), 0o600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	store, err := New(db)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	d3test.RunStateStoreTests(t, store)
+}
@@ -0,0 +1,919 @@
+// Package d3mock provides an in-memory d3.D3API for unit tests of code that
+// uses the D3 client, without a server.
+//
+//	mock := d3mock.New()
+//	svc := NewService(mock) // accepts d3.D3API
+//	svc.ConvertReport("report.docx")
+//	if mock.CallCount("Convert") != 1 {
+//		t.Error("expected one conversion")
+//	}
+//
+// Uploaded files are kept in memory and operations complete immediately.
+// Use Fail to make a method return an error and SetStatus to control what
+// an operation reports.
+package d3mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+var _ d3.D3API = (*Client)(nil)
+
+// Call records one method call on the mock
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Client is an in-memory d3.D3API. The zero value is not usable; create one
+// with New. It is safe for concurrent use.
+type Client struct {
+	mu      sync.Mutex
+	files   map[d3.FileKey]d3.FileInfo
+	order   []d3.FileKey
+	folders map[string]d3.Folder
+	tasks   map[d3.MainTaskID]d3.StatusResponse
+	taskIDs []d3.MainTaskID
+	errs    map[string]error
+	calls   []Call
+	next    int
+	closed  bool
+	usage   d3.Usage
+	maxSize int64
+	catalog *d3.OperationCatalog
+}
+
+// New returns an empty mock
+func New() *Client {
+	return &Client{
+		files:   map[d3.FileKey]d3.FileInfo{},
+		folders: map[string]d3.Folder{},
+		tasks:   map[d3.MainTaskID]d3.StatusResponse{},
+		errs:    map[string]error{},
+	}
+}
+
+// Fail makes every later call of method (e.g. "UploadFile") return err. A
+// nil err makes the method succeed again.
+func (m *Client) Fail(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+	m.errs[method] = err
+}
+
+// Calls returns the calls made so far, in order
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns how often method was called
+func (m *Client) CallCount(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, call := range m.calls {
+		if call.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// AddFile stores file as if it had been uploaded, e.g. to seed searches.
+// A missing FileKey is generated. It returns the stored file.
+func (m *Client) AddFile(file d3.FileInfo) d3.FileInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addFile(file)
+}
+
+// Files returns the stored files in upload order
+func (m *Client) Files() []d3.FileInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	files := make([]d3.FileInfo, 0, len(m.order))
+	for _, key := range m.order {
+		files = append(files, m.files[key])
+	}
+	return files
+}
+
+// SetStatus sets the status GetStatus and PollStatus report for taskID
+func (m *Client) SetStatus(taskID d3.MainTaskID, status d3.StatusResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setTask(taskID, status)
+}
+
+// SetUsage sets what GetUsage returns
+func (m *Client) SetUsage(usage d3.Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = usage
+}
+
+// SetCatalog sets what GetSupportedOperations and Capabilities return
+func (m *Client) SetCatalog(catalog d3.OperationCatalog) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.catalog = &catalog
+}
+
+// SetMaxFileSize sets the largest file UploadFile accepts (0: no limit)
+func (m *Client) SetMaxFileSize(size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxSize = size
+}
+
+// record logs a call and returns the error it should fail with, if any.
+// It must be called with m.mu held.
+func (m *Client) record(method string, args ...interface{}) error {
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	if m.closed {
+		return d3.ErrClientClosed
+	}
+	return m.errs[method]
+}
+
+func (m *Client) id(prefix string) string {
+	m.next++
+	return fmt.Sprintf("%s-%d", prefix, m.next)
+}
+
+func (m *Client) addFile(file d3.FileInfo) d3.FileInfo {
+	if file.FileKey == "" {
+		file.FileKey = d3.FileKey(m.id("file"))
+	}
+	if file.CreatedAt.IsZero() {
+		file.CreatedAt = time.Now()
+	}
+	if _, ok := m.files[file.FileKey]; !ok {
+		m.order = append(m.order, file.FileKey)
+	}
+	m.files[file.FileKey] = file
+	return file
+}
+
+// setTask stores the status of taskID, remembering the order tasks were
+// first stored in
+func (m *Client) setTask(taskID d3.MainTaskID, status d3.StatusResponse) {
+	if _, ok := m.tasks[taskID]; !ok {
+		m.taskIDs = append(m.taskIDs, taskID)
+	}
+	m.tasks[taskID] = status
+}
+
+func (m *Client) file(fileKey d3.FileKey) (d3.FileInfo, error) {
+	file, ok := m.files[fileKey]
+	if !ok {
+		return file, notFound("file " + string(fileKey))
+	}
+	return file, nil
+}
+
+// notFound returns the API error for a missing resource
+func notFound(what string) error {
+	code := int(d3.ErrorCodeInvalidFileKey)
+	return d3.NewD3APIError(what+" not found", http.StatusNotFound, &code, nil)
+}
+
+// Uploads
+
+func (m *Client) UploadFile(options d3.UploadFileOptions) (*d3.UploadResponse, error) {
+	return m.upload(context.Background(), "UploadFile", options)
+}
+
+func (m *Client) UploadFileContext(ctx context.Context, options d3.UploadFileOptions) (*d3.UploadResponse, error) {
+	return m.upload(ctx, "UploadFileContext", options)
+}
+
+// upload stores the file at options.File, which must exist
+func (m *Client) upload(ctx context.Context, method string, options d3.UploadFileOptions) (*d3.UploadResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record(method, options); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if options.FileName == "" {
+		return nil, d3.NewD3ValidationError("file_name is required", nil)
+	}
+	info, err := os.Stat(options.File)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", d3.ErrFileNotFound, err)
+	}
+	if m.maxSize > 0 && info.Size() > m.maxSize {
+		return nil, d3.ErrFileTooLarge
+	}
+
+	file := m.addFile(d3.FileInfo{
+		FileName: d3.SanitizeFileName(options.FileName),
+		MimeType: options.MimeType,
+		Size:     info.Size(),
+		FolderID: options.FolderID,
+	})
+	if options.OnProgress != nil {
+		options.OnProgress(d3.UploadProgress{
+			CurrentPart:   1,
+			TotalParts:    1,
+			BytesUploaded: file.Size,
+			TotalBytes:    file.Size,
+			Percentage:    100,
+		})
+	}
+	if options.Progress != nil {
+		progress := d3.Progress{Stage: d3.ProgressUpload, Name: file.FileName, Done: file.Size, Total: file.Size, Percentage: 100}
+		options.Progress.Start(progress)
+		options.Progress.Update(progress)
+		options.Progress.Done(progress)
+	}
+	part := d3.UploadPartInfo{FileKey: file.FileKey, PartNumber: 1, TotalParts: 1, Size: file.Size, Attempt: 1}
+	if options.OnPartStart != nil {
+		options.OnPartStart(part)
+	}
+	if options.OnPartComplete != nil {
+		options.OnPartComplete(part)
+	}
+	if options.OnComplete != nil {
+		options.OnComplete(d3.UploadCompleteInfo{FileKey: file.FileKey, FileName: file.FileName, Size: file.Size, Parts: 1})
+	}
+	return &d3.UploadResponse{FileKey: file.FileKey, UploadID: d3.UploadID(m.id("upload"))}, nil
+}
+
+func (m *Client) ListPendingUploads() ([]d3.PendingUpload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("ListPendingUploads"); err != nil {
+		return nil, err
+	}
+	return []d3.PendingUpload{}, nil
+}
+
+func (m *Client) AbortUpload(options d3.AbortUploadOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("AbortUpload", options)
+}
+
+func (m *Client) AbortStaleUploads(olderThan time.Duration) ([]d3.PendingUpload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("AbortStaleUploads", olderThan); err != nil {
+		return nil, err
+	}
+	return []d3.PendingUpload{}, nil
+}
+
+func (m *Client) MaxFileSize() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("MaxFileSize"); err != nil {
+		return 0, err
+	}
+	return m.maxSize, nil
+}
+
+// Operations
+
+func (m *Client) CheckSupportedOperation(options d3.SupportedOperationOptions) (*d3.SupportedOperationResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("CheckSupportedOperation", options); err != nil {
+		return nil, err
+	}
+	return &d3.SupportedOperationResponse{Supported: true, Ext: options.Ext, Action: options.Action}, nil
+}
+
+func (m *Client) GetSupportedOperations() (*d3.OperationCatalog, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetSupportedOperations"); err != nil {
+		return nil, err
+	}
+	var catalog d3.OperationCatalog
+	if m.catalog != nil {
+		catalog = *m.catalog
+	}
+	return &catalog, nil
+}
+
+// Capabilities returns the catalog set with SetCatalog, else
+// d3.BundledCatalog
+func (m *Client) Capabilities() *d3.OperationCatalog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: "Capabilities"})
+	if m.catalog != nil {
+		return m.catalog
+	}
+	return d3.BundledCatalog()
+}
+
+func (m *Client) RefreshCapabilities(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("RefreshCapabilities"); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// CreateOperation completes the operation immediately, with a download link
+// per file
+func (m *Client) CreateOperation(options d3.OperationOptions) (*d3.OperationResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("CreateOperation", options); err != nil {
+		return nil, err
+	}
+	return m.createOperation(options)
+}
+
+func (m *Client) createOperation(options d3.OperationOptions) (*d3.OperationResponse, error) {
+	if options.Action == "" || len(options.FileKeys) == 0 {
+		return nil, d3.NewD3ValidationError("action and at least one file key are required", nil)
+	}
+
+	taskID := d3.MainTaskID(m.id("task"))
+	status := d3.StatusResponse{OperationStatus: d3.StatusCompleted}
+	for _, fileKey := range options.FileKeys {
+		if _, err := m.file(fileKey); err != nil {
+			return nil, err
+		}
+		status.FilesData = append(status.FilesData, d3.FileTaskStatus{
+			FileKey:      fileKey,
+			Status:       d3.StatusCompleted,
+			DownloadLink: fmt.Sprintf("https://d3.mock/download/%s/%s", taskID, fileKey),
+		})
+	}
+	m.setTask(taskID, status)
+	return &d3.OperationResponse{MainTaskID: taskID}, nil
+}
+
+// operation records a convenience method call and creates its operation
+func (m *Client) operation(method string, options d3.OperationOptions) (*d3.OperationResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record(method, options.FileKeys, options.Parameters, options.Notes); err != nil {
+		return nil, err
+	}
+	return m.createOperation(options)
+}
+
+func (m *Client) Convert(fileKeys []d3.FileKey, convertTo string, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("Convert", d3.OperationOptions{Action: d3.ActionConvert, FileKeys: fileKeys, Parameters: map[string]interface{}{"convert_to": convertTo}, Notes: notes})
+}
+
+func (m *Client) Compress(fileKeys []d3.FileKey, compressionValue string, notes map[string]string) (*d3.OperationResponse, error) {
+	if compressionValue == "" {
+		compressionValue = d3.DefaultCompressionValue
+	}
+	return m.operation("Compress", d3.OperationOptions{Action: d3.ActionCompress, FileKeys: fileKeys, Parameters: map[string]interface{}{"compression_value": compressionValue}, Notes: notes})
+}
+
+func (m *Client) Merge(fileKeys []d3.FileKey, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("Merge", d3.OperationOptions{Action: d3.ActionMerge, FileKeys: fileKeys, Notes: notes})
+}
+
+func (m *Client) Zip(fileKeys []d3.FileKey, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("Zip", d3.OperationOptions{Action: d3.ActionZip, FileKeys: fileKeys, Notes: notes})
+}
+
+func (m *Client) Share(fileKeys []d3.FileKey, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("Share", d3.OperationOptions{Action: d3.ActionShare, FileKeys: fileKeys, Notes: notes})
+}
+
+func (m *Client) LockPdf(fileKeys []d3.FileKey, password string, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("LockPdf", d3.OperationOptions{Action: d3.ActionLock, FileKeys: fileKeys, Parameters: map[string]interface{}{"password": password}, Notes: notes})
+}
+
+func (m *Client) UnlockPdf(fileKeys []d3.FileKey, password string, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("UnlockPdf", d3.OperationOptions{Action: d3.ActionUnlock, FileKeys: fileKeys, Parameters: map[string]interface{}{"password": password}, Notes: notes})
+}
+
+func (m *Client) ResetPdfPassword(fileKeys []d3.FileKey, oldPassword, newPassword string, notes map[string]string) (*d3.OperationResponse, error) {
+	return m.operation("ResetPdfPassword", d3.OperationOptions{Action: d3.ActionResetPassword, FileKeys: fileKeys, Parameters: map[string]interface{}{"old_password": oldPassword, "new_password": newPassword}, Notes: notes})
+}
+
+func (m *Client) GetStatus(options d3.StatusOptions) (*d3.StatusResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetStatus", options); err != nil {
+		return nil, err
+	}
+	return m.status(options.MainTaskID)
+}
+
+func (m *Client) status(taskID d3.MainTaskID) (*d3.StatusResponse, error) {
+	status, ok := m.tasks[taskID]
+	if !ok {
+		return nil, notFound("task " + string(taskID))
+	}
+	return &status, nil
+}
+
+// PollStatus returns the task's status at once, or a poll timeout if it is
+// not terminal
+func (m *Client) PollStatus(options d3.PollStatusOptions) (*d3.StatusResponse, error) {
+	return m.poll(options, "PollStatus", options)
+}
+
+// poll records a call of method with args and reports the task's status to
+// options' callbacks
+func (m *Client) poll(options d3.PollStatusOptions, method string, args ...interface{}) (*d3.StatusResponse, error) {
+	m.mu.Lock()
+	if err := m.record(method, args...); err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	status, err := m.status(options.MainTaskID)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.OnUpdate != nil {
+		options.OnUpdate(*status)
+	}
+	progress := d3.Progress{Stage: d3.ProgressPoll, Name: string(options.MainTaskID), Total: int64(len(status.FilesData)), Status: status.OperationStatus}
+	for _, file := range status.FilesData {
+		if d3.IsTerminalStatus(file.Status) {
+			progress.Done++
+		}
+	}
+	if progress.Total > 0 {
+		progress.Percentage = int(progress.Done * 100 / progress.Total)
+	}
+	if options.Progress != nil {
+		options.Progress.Start(progress)
+		options.Progress.Update(progress)
+	}
+	if !status.Done() {
+		timeoutErr := d3.NewD3TimeoutError(fmt.Sprintf("%v: operation %s is still %s", d3.ErrPollTimeout, options.MainTaskID, status.OperationStatus))
+		timeoutErr.Err = d3.ErrPollTimeout
+		timeoutErr.TaskID = options.MainTaskID
+		timeoutErr.LastStatus = status
+		if options.Progress != nil {
+			options.Progress.Error(progress, timeoutErr)
+		}
+		return nil, timeoutErr
+	}
+	if options.Progress != nil {
+		options.Progress.Done(progress)
+	}
+	return status, nil
+}
+
+// WaitForOperation returns the task's results at once, with a
+// *d3.PartialFailure if any file did not complete, or a poll timeout if the
+// task is not terminal
+func (m *Client) WaitForOperation(ctx context.Context, mainTaskID d3.MainTaskID, opts d3.WaitOptions) ([]d3.Result, error) {
+	status, err := m.poll(d3.PollStatusOptions{
+		StatusOptions: d3.StatusOptions{MainTaskID: mainTaskID, Headers: opts.Headers, RequestTimeout: opts.RequestTimeout},
+		Interval:      opts.Interval,
+		Timeout:       opts.Timeout,
+		OnUpdate:      opts.OnUpdate,
+		Progress:      opts.Progress,
+	}, "WaitForOperation", mainTaskID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := results(status)
+	failure := &d3.PartialFailure{MainTaskID: mainTaskID, OperationStatus: status.OperationStatus}
+	for _, result := range results {
+		if result.Status == d3.StatusCompleted {
+			failure.Completed = append(failure.Completed, result)
+		} else {
+			failure.Failed = append(failure.Failed, result)
+		}
+	}
+	if len(failure.Failed) > 0 || status.OperationStatus != d3.StatusCompleted {
+		return results, failure
+	}
+	return results, nil
+}
+
+// Results returns the Result of each file of status without probing the
+// outputs, see WaitForOperation
+func (m *Client) Results(ctx context.Context, status *d3.StatusResponse) ([]d3.Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Results", status); err != nil {
+		return nil, err
+	}
+	return results(status), nil
+}
+
+// Jobs
+
+// Submit creates an operation, see CreateOperation, and returns its Job
+func (m *Client) Submit(ctx context.Context, options d3.OperationOptions) (*d3.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Submit", options); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	operation, err := m.createOperation(options)
+	if err != nil {
+		return nil, err
+	}
+	return m.job(operation.MainTaskID), nil
+}
+
+// Job returns the Job of a task, which calls GetStatus, WaitForOperation
+// and, to cancel it, marks the task cancelled unless it has finished
+func (m *Client) Job(mainTaskID d3.MainTaskID) *d3.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: "Job", Args: []interface{}{mainTaskID}})
+	return m.job(mainTaskID)
+}
+
+func (m *Client) job(mainTaskID d3.MainTaskID) *d3.Job {
+	return d3.NewJob(mainTaskID, d3.JobFuncs{
+		Status: func(ctx context.Context, mainTaskID d3.MainTaskID) (*d3.StatusResponse, error) {
+			return m.GetStatus(d3.StatusOptions{MainTaskID: mainTaskID})
+		},
+		Wait:   m.WaitForOperation,
+		Cancel: m.cancel,
+	})
+}
+
+// PendingJobs returns the Jobs of the tasks that have not finished, oldest
+// first
+func (m *Client) PendingJobs(ctx context.Context) ([]*d3.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("PendingJobs"); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var jobs []*d3.Job
+	for _, taskID := range m.taskIDs {
+		if status := m.tasks[taskID]; !status.Done() {
+			jobs = append(jobs, m.job(taskID))
+		}
+	}
+	return jobs, nil
+}
+
+// cancel marks the task cancelled unless it has finished
+func (m *Client) cancel(ctx context.Context, mainTaskID d3.MainTaskID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Cancel", mainTaskID); err != nil {
+		return err
+	}
+	status, err := m.status(mainTaskID)
+	if err != nil {
+		return err
+	}
+	if !status.Done() {
+		status.OperationStatus = d3.StatusCancelled
+		m.setTask(mainTaskID, *status)
+	}
+	return nil
+}
+
+// results returns the Result of each file of status without probing the
+// outputs: Size is -1 and Name is taken from the download link
+func results(status *d3.StatusResponse) []d3.Result {
+	results := make([]d3.Result, len(status.FilesData))
+	for i, file := range status.FilesData {
+		results[i] = d3.Result{FileTaskStatus: file, Size: -1}
+		if file.DownloadLink != "" {
+			results[i].Name = path.Base(file.DownloadLink)
+		}
+	}
+	return results
+}
+
+// Files
+
+func (m *Client) TagFile(fileKey d3.FileKey, tags []string) (*d3.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("TagFile", fileKey, tags); err != nil {
+		return nil, err
+	}
+	file, err := m.file(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	file.Tags = append([]string(nil), tags...)
+	m.files[fileKey] = file
+	return &file, nil
+}
+
+func (m *Client) SetFileExpiry(fileKey d3.FileKey, ttl time.Duration) (*d3.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("SetFileExpiry", fileKey, ttl); err != nil {
+		return nil, err
+	}
+	file, err := m.file(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	file.ExpiresAt = time.Now().Add(ttl)
+	m.files[fileKey] = file
+	return &file, nil
+}
+
+func (m *Client) DeleteFile(fileKey d3.FileKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("DeleteFile", fileKey); err != nil {
+		return err
+	}
+	return m.deleteFile(fileKey)
+}
+
+func (m *Client) deleteFile(fileKey d3.FileKey) error {
+	if _, err := m.file(fileKey); err != nil {
+		return err
+	}
+	delete(m.files, fileKey)
+	for i, key := range m.order {
+		if key == fileKey {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *Client) DeleteFiles(fileKeys []d3.FileKey) d3.DeleteResults {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := m.record("DeleteFiles", fileKeys)
+	results := make(d3.DeleteResults, len(fileKeys))
+	for i, fileKey := range fileKeys {
+		results[i] = d3.DeleteResult{FileKey: fileKey, Err: err}
+		if err == nil {
+			results[i].Err = m.deleteFile(fileKey)
+		}
+	}
+	return results
+}
+
+// SearchFiles filters the stored files like the API does and pages through
+// them in upload order
+func (m *Client) SearchFiles(options d3.SearchOptions) (*d3.SearchFilesResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("SearchFiles", options); err != nil {
+		return nil, err
+	}
+
+	var matches []d3.FileInfo
+	for _, key := range m.order {
+		if file := m.files[key]; matchesSearch(file, options) {
+			matches = append(matches, file)
+		}
+	}
+
+	page, perPage := options.Page, options.PerPage
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 100
+	}
+	start := (page - 1) * perPage
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + perPage
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return &d3.SearchFilesResponse{
+		Files:   append([]d3.FileInfo{}, matches[start:end]...),
+		Total:   len(matches),
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// matchesSearch reports whether file passes all of options' filters
+func matchesSearch(file d3.FileInfo, options d3.SearchOptions) bool {
+	for _, tag := range options.Tags {
+		found := false
+		for _, fileTag := range file.Tags {
+			found = found || fileTag == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(file.FileName)), ".")
+	switch {
+	case options.NameContains != "" && !strings.Contains(file.FileName, options.NameContains),
+		options.MimeType != "" && file.MimeType != options.MimeType,
+		options.Ext != "" && ext != strings.TrimPrefix(strings.ToLower(options.Ext), "."),
+		options.FolderID != "" && file.FolderID != options.FolderID,
+		!options.UploadedAfter.IsZero() && !file.CreatedAt.After(options.UploadedAfter),
+		!options.UploadedBefore.IsZero() && !file.CreatedAt.Before(options.UploadedBefore):
+		return false
+	}
+	return true
+}
+
+func (m *Client) IterateFiles(options d3.SearchOptions) *d3.FileIterator {
+	return d3.NewFileIterator(m.SearchFiles, options)
+}
+
+// StorageStats sums the stored files by MIME type. ByAge is left empty.
+func (m *Client) StorageStats() (*d3.StorageStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("StorageStats"); err != nil {
+		return nil, err
+	}
+	stats := &d3.StorageStats{ByType: map[string]d3.StorageBucket{}}
+	for _, file := range m.files {
+		stats.Total.Files++
+		stats.Total.Bytes += file.Size
+		bucket := stats.ByType[file.MimeType]
+		bucket.Files++
+		bucket.Bytes += file.Size
+		stats.ByType[file.MimeType] = bucket
+	}
+	return stats, nil
+}
+
+func (m *Client) CreateShareLink(fileKey d3.FileKey, options d3.ShareOptions) (*d3.ShareLink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("CreateShareLink", fileKey, options); err != nil {
+		return nil, err
+	}
+	if _, err := m.file(fileKey); err != nil {
+		return nil, err
+	}
+	link := &d3.ShareLink{
+		URL:               "https://d3.mock/share/" + m.id("link"),
+		FileKey:           fileKey,
+		PasswordProtected: options.Password != "",
+		MaxDownloads:      options.MaxDownloads,
+	}
+	if options.ExpiresIn > 0 {
+		link.ExpiresAt = time.Now().Add(options.ExpiresIn)
+	}
+	return link, nil
+}
+
+// Folders
+
+func (m *Client) CreateFolder(options d3.CreateFolderOptions) (*d3.Folder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("CreateFolder", options); err != nil {
+		return nil, err
+	}
+	if options.Name == "" {
+		return nil, d3.NewD3ValidationError("folder name is required", nil)
+	}
+	path := "/" + options.Name
+	if options.ParentID != "" {
+		parent, ok := m.folders[options.ParentID]
+		if !ok {
+			return nil, notFound("folder " + options.ParentID)
+		}
+		path = parent.Path + path
+	}
+	folder := d3.Folder{
+		FolderID:  m.id("folder"),
+		Name:      options.Name,
+		ParentID:  options.ParentID,
+		Path:      path,
+		CreatedAt: time.Now(),
+	}
+	m.folders[folder.FolderID] = folder
+	return &folder, nil
+}
+
+func (m *Client) MoveFile(options d3.MoveFileOptions) (*d3.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("MoveFile", options); err != nil {
+		return nil, err
+	}
+	file, err := m.file(options.FileKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := m.folders[options.FolderID]; options.FolderID != "" && !ok {
+		return nil, notFound("folder " + options.FolderID)
+	}
+	file.FolderID = options.FolderID
+	m.files[file.FileKey] = file
+	return &file, nil
+}
+
+// ListFolder lists a folder's subfolders by name and files in upload order,
+// without paging
+func (m *Client) ListFolder(options d3.ListFolderOptions) (*d3.ListFolderResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("ListFolder", options); err != nil {
+		return nil, err
+	}
+	folder, ok := m.folders[options.FolderID]
+	if options.FolderID != "" && !ok {
+		return nil, notFound("folder " + options.FolderID)
+	}
+
+	resp := &d3.ListFolderResponse{Folder: folder, Folders: []d3.Folder{}, Files: []d3.FileInfo{}, Page: 1}
+	for _, sub := range m.folders {
+		if sub.ParentID == options.FolderID {
+			resp.Folders = append(resp.Folders, sub)
+		}
+	}
+	sort.Slice(resp.Folders, func(i, j int) bool { return resp.Folders[i].Name < resp.Folders[j].Name })
+	for _, key := range m.order {
+		if file := m.files[key]; file.FolderID == options.FolderID {
+			resp.Files = append(resp.Files, file)
+		}
+	}
+	resp.Total = len(resp.Files)
+	resp.PerPage = len(resp.Files)
+	return resp, nil
+}
+
+// Account
+
+func (m *Client) GetUsage() (*d3.Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetUsage"); err != nil {
+		return nil, err
+	}
+	usage := m.usage
+	return &usage, nil
+}
+
+func (m *Client) Ping(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("Ping"); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// HealthCheck reports every check as passed
+func (m *Client) HealthCheck(ctx context.Context) (*d3.HealthReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("HealthCheck"); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	report := &d3.HealthReport{BaseURL: "https://d3.mock"}
+	for _, name := range []string{d3.HealthCheckAPI, d3.HealthCheckClock, d3.HealthCheckAuth, d3.HealthCheckStorage} {
+		report.Checks = append(report.Checks, d3.HealthCheckResult{Name: name, OK: true})
+	}
+	return report, nil
+}
+
+// Close makes later calls fail with d3.ErrClientClosed
+func (m *Client) Close(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: "Close"})
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,156 @@
+package d3mock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// convertAll is the kind of downstream code the mock is for
+func convertAll(api d3.D3API, path string) ([]string, error) {
+	upload, err := api.UploadFile(d3.UploadFileOptions{File: path, FileName: filepath.Base(path)})
+	if err != nil {
+		return nil, err
+	}
+	op, err := api.Convert([]d3.FileKey{upload.FileKey}, "pdf", nil)
+	if err != nil {
+		return nil, err
+	}
+	status, err := api.PollStatus(d3.PollStatusOptions{StatusOptions: d3.StatusOptions{MainTaskID: op.MainTaskID}})
+	if err != nil {
+		return nil, err
+	}
+	var links []string
+	for _, file := range status.CompletedFiles() {
+		links = append(links, file.DownloadLink)
+	}
+	return links, nil
+}
+
+func TestClient_Workflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.docx")
+	if err := os.WriteFile(path, []byte("report"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	mock := New()
+	links, err := convertAll(mock, path)
+	if err != nil {
+		t.Fatalf("Workflow failed: %v", err)
+	}
+	if len(links) != 1 {
+		t.Errorf("Expected 1 download link, got %v", links)
+	}
+	if mock.CallCount("UploadFile") != 1 || mock.CallCount("Convert") != 1 {
+		t.Errorf("Unexpected calls: %+v", mock.Calls())
+	}
+	if files := mock.Files(); len(files) != 1 || files[0].FileName != "report.docx" || files[0].Size != 6 {
+		t.Errorf("Expected the uploaded file to be stored, got %+v", files)
+	}
+
+	outage := errors.New("service unavailable")
+	mock.Fail("Convert", outage)
+	if _, err := convertAll(mock, path); !errors.Is(err, outage) {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+}
+
+func TestClient_StatusAndSearch(t *testing.T) {
+	mock := New()
+	file := mock.AddFile(d3.FileInfo{FileName: "a.pdf", MimeType: "application/pdf", Size: 10})
+	mock.AddFile(d3.FileInfo{FileName: "b.png", MimeType: "image/png", Size: 5})
+
+	if _, err := mock.TagFile(file.FileKey, []string{"invoice"}); err != nil {
+		t.Fatalf("TagFile failed: %v", err)
+	}
+	it := mock.IterateFiles(d3.SearchOptions{Tags: []string{"invoice"}, PerPage: 1})
+	var found []string
+	for it.Next() {
+		found = append(found, it.File().FileName)
+	}
+	if it.Err() != nil || len(found) != 1 || found[0] != "a.pdf" {
+		t.Errorf("Expected to find a.pdf, got %v, %v", found, it.Err())
+	}
+
+	mock.SetStatus("task-running", d3.StatusResponse{OperationStatus: d3.StatusRunning})
+	_, err := mock.PollStatus(d3.PollStatusOptions{StatusOptions: d3.StatusOptions{MainTaskID: "task-running"}})
+	var timeoutErr *d3.D3TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.TaskID != "task-running" || !errors.Is(err, d3.ErrPollTimeout) {
+		t.Errorf("Expected a poll timeout for a running task, got %v", err)
+	}
+
+	if _, err := mock.GetStatus(d3.StatusOptions{MainTaskID: "missing"}); !d3.IsD3APIError(err) {
+		t.Errorf("Expected an API error for an unknown task, got %v", err)
+	}
+
+	if catalog := mock.Capabilities(); catalog != d3.BundledCatalog() {
+		t.Errorf("Expected the bundled catalog before SetCatalog, got %+v", catalog)
+	}
+	mock.Fail("RefreshCapabilities", errors.New("offline"))
+	if err := mock.RefreshCapabilities(context.Background()); err == nil {
+		t.Error("Expected the injected refresh error")
+	}
+
+	if report, err := mock.HealthCheck(context.Background()); err != nil || !report.OK() || len(report.Checks) != 4 {
+		t.Errorf("Expected a passing health report, got %+v, %v", report, err)
+	}
+
+	mock.Close(context.Background())
+	if _, err := mock.GetUsage(); !errors.Is(err, d3.ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+}
+
+func TestClient_WaitForOperation(t *testing.T) {
+	mock := New()
+	file := mock.AddFile(d3.FileInfo{FileName: "a.docx"})
+	op, err := mock.Convert([]d3.FileKey{file.FileKey}, "pdf", nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	results, err := mock.WaitForOperation(context.Background(), op.MainTaskID, d3.WaitOptions{})
+	if err != nil || len(results) != 1 || results[0].FileKey != file.FileKey || results[0].Name != string(file.FileKey) {
+		t.Errorf("Expected the completed result, got %+v, %v", results, err)
+	}
+
+	mock.SetStatus("task-failed", d3.StatusResponse{
+		OperationStatus: d3.StatusPartiallyCompleted,
+		FilesData:       []d3.FileTaskStatus{{FileKey: "a", Status: d3.StatusCompleted}, {FileKey: "b", Status: d3.StatusFailed}},
+	})
+	results, err = mock.WaitForOperation(context.Background(), "task-failed", d3.WaitOptions{})
+	var failure *d3.PartialFailure
+	if !errors.As(err, &failure) || len(failure.Completed) != 1 || len(failure.Failed) != 1 || len(results) != 2 {
+		t.Errorf("Expected a PartialFailure with both results, got %+v, %v", results, err)
+	}
+}
+
+func TestClient_Jobs(t *testing.T) {
+	mock := New()
+	file := mock.AddFile(d3.FileInfo{FileName: "a.docx"})
+	job, err := mock.Submit(context.Background(), d3.OperationOptions{Action: d3.ActionConvert, FileKeys: []d3.FileKey{file.FileKey}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if results, err := job.Wait(context.Background()); err != nil || len(results) != 1 {
+		t.Errorf("Expected one result, got %+v, %v", results, err)
+	}
+	if results, err := job.Results(); err != nil || len(results) != 1 {
+		t.Errorf("Expected Results to repeat Wait, got %+v, %v", results, err)
+	}
+
+	mock.SetStatus("task-running", d3.StatusResponse{OperationStatus: d3.StatusRunning})
+	if jobs, err := mock.PendingJobs(context.Background()); err != nil || len(jobs) != 1 || jobs[0].ID() != "task-running" {
+		t.Errorf("Expected the running task pending, got %v, %v", jobs, err)
+	}
+	running := mock.Job("task-running")
+	if err := running.Cancel(context.Background()); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if status, err := running.Refresh(context.Background()); err != nil || status.OperationStatus != d3.StatusCancelled {
+		t.Errorf("Expected the task cancelled, got %+v, %v", status, err)
+	}
+}
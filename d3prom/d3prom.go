@@ -0,0 +1,112 @@
+// Package d3prom records D3 client metrics with Prometheus.
+//
+//	recorder, err := d3prom.NewRecorder(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := d3.NewClient(apiKey, d3.WithMetrics(recorder))
+package d3prom
+
+import (
+	"strconv"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ d3.MetricsRecorder = (*Recorder)(nil)
+
+// Recorder is a d3.MetricsRecorder backed by Prometheus collectors
+type Recorder struct {
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	uploadParts      *prometheus.CounterVec
+	uploadBytes      prometheus.Counter
+	uploadThroughput prometheus.Histogram
+	pollDuration     *prometheus.HistogramVec
+}
+
+// NewRecorder creates a Recorder and registers its collectors with reg. A nil
+// reg uses prometheus.DefaultRegisterer.
+func NewRecorder(reg prometheus.Registerer) (*Recorder, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	r := &Recorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "d3",
+			Name:      "requests_total",
+			Help:      "API request attempts by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "d3",
+			Name:      "request_duration_seconds",
+			Help:      "API request attempt latency by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		uploadParts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "d3",
+			Name:      "upload_parts_total",
+			Help:      "Presigned part uploads by result.",
+		}, []string{"result"}),
+		uploadBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "d3",
+			Name:      "upload_bytes_total",
+			Help:      "Bytes uploaded in successful parts.",
+		}),
+		uploadThroughput: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "d3",
+			Name:      "upload_part_throughput_bytes_per_second",
+			Help:      "Throughput of successful part uploads.",
+			Buckets:   prometheus.ExponentialBuckets(64*1024, 2, 12),
+		}),
+		pollDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "d3",
+			Name:      "poll_duration_seconds",
+			Help:      "Time from the first poll until the operation finished, by final status.",
+			Buckets:   []float64{1, 2, 5, 10, 30, 60, 120, 300, 600},
+		}, []string{"status"}),
+	}
+
+	collectors := []prometheus.Collector{
+		r.requests, r.requestDuration, r.uploadParts, r.uploadBytes, r.uploadThroughput, r.pollDuration,
+	}
+	for _, collector := range collectors {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// RecordRequest implements d3.MetricsRecorder. Transport errors are counted
+// with status "error".
+func (r *Recorder) RecordRequest(method, route string, statusCode int, latency time.Duration, err error) {
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(statusCode)
+	}
+	r.requests.WithLabelValues(method, route, status).Inc()
+	r.requestDuration.WithLabelValues(method, route).Observe(latency.Seconds())
+}
+
+// RecordUploadPart implements d3.MetricsRecorder
+func (r *Recorder) RecordUploadPart(bytes int64, latency time.Duration, err error) {
+	if err != nil {
+		r.uploadParts.WithLabelValues("error").Inc()
+		return
+	}
+	r.uploadParts.WithLabelValues("success").Inc()
+	r.uploadBytes.Add(float64(bytes))
+	if latency > 0 {
+		r.uploadThroughput.Observe(float64(bytes) / latency.Seconds())
+	}
+}
+
+// RecordPoll implements d3.MetricsRecorder
+func (r *Recorder) RecordPoll(status string, duration time.Duration) {
+	r.pollDuration.WithLabelValues(status).Observe(duration.Seconds())
+}
@@ -0,0 +1,44 @@
+package d3prom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	recorder, err := NewRecorder(reg)
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	recorder.RecordRequest("GET", "/v1/biz/status/{main_task_id}", 200, 10*time.Millisecond, nil)
+	recorder.RecordRequest("GET", "/v1/biz/status/{main_task_id}", 0, time.Second, errors.New("connection refused"))
+	recorder.RecordUploadPart(1024, time.Second, nil)
+	recorder.RecordUploadPart(1024, time.Second, errors.New("reset"))
+	recorder.RecordPoll("completed", 3*time.Second)
+
+	if got := testutil.ToFloat64(recorder.requests.WithLabelValues("GET", "/v1/biz/status/{main_task_id}", "200")); got != 1 {
+		t.Errorf("Expected 1 successful request, got %v", got)
+	}
+	if got := testutil.ToFloat64(recorder.requests.WithLabelValues("GET", "/v1/biz/status/{main_task_id}", "error")); got != 1 {
+		t.Errorf("Expected 1 failed request, got %v", got)
+	}
+	if got := testutil.ToFloat64(recorder.uploadBytes); got != 1024 {
+		t.Errorf("Expected 1024 uploaded bytes, got %v", got)
+	}
+	if got := testutil.ToFloat64(recorder.uploadParts.WithLabelValues("error")); got != 1 {
+		t.Errorf("Expected 1 failed part, got %v", got)
+	}
+	if got := testutil.CollectAndCount(recorder.pollDuration); got != 1 {
+		t.Errorf("Expected 1 poll duration series, got %d", got)
+	}
+
+	if _, err := NewRecorder(reg); err == nil {
+		t.Error("Expected error registering collectors twice")
+	}
+}
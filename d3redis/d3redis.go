@@ -0,0 +1,120 @@
+// Package d3redis stores D3 client state in Redis, so several instances of
+// a service share it: a restarted instance resumes the operations another
+// one submitted, e.g. with Manager.Resume.
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	client, err := d3.NewDragdropdo(d3.Config{
+//		APIKey:     apiKey,
+//		StateStore: d3redis.New(rdb, "myapp:d3:"),
+//	})
+package d3redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ d3.StateStore = (*Store)(nil)
+
+// DefaultPrefix prefixes the keys of a Store created without a prefix
+const DefaultPrefix = "d3:"
+
+// Store is a d3.StateStore keeping its state in two Redis hashes,
+// <prefix>uploads and <prefix>jobs, whose fields are JSON records
+type Store struct {
+	rdb     redis.UniversalClient
+	uploads string
+	jobs    string
+}
+
+// New returns a Store using rdb with keys starting with prefix, or
+// DefaultPrefix if empty. Instances sharing state use the same prefix.
+func New(rdb redis.UniversalClient, prefix string) *Store {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return &Store{rdb: rdb, uploads: prefix + "uploads", jobs: prefix + "jobs"}
+}
+
+// SaveUpload implements d3.StateStore
+func (s *Store) SaveUpload(ctx context.Context, state d3.UploadState) error {
+	return s.put(ctx, s.uploads, state.File, state)
+}
+
+// LoadUpload implements d3.StateStore
+func (s *Store) LoadUpload(ctx context.Context, file string) (*d3.UploadState, error) {
+	data, err := s.rdb.HGet(ctx, s.uploads, file).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("d3redis: failed to load upload: %w", err)
+	}
+	var state d3.UploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("d3redis: failed to decode upload: %w", err)
+	}
+	return &state, nil
+}
+
+// DeleteUpload implements d3.StateStore
+func (s *Store) DeleteUpload(ctx context.Context, file string) error {
+	return s.delete(ctx, s.uploads, file)
+}
+
+// SaveJob implements d3.StateStore
+func (s *Store) SaveJob(ctx context.Context, state d3.JobState) error {
+	return s.put(ctx, s.jobs, string(state.MainTaskID), state)
+}
+
+// LoadJobs implements d3.StateStore
+func (s *Store) LoadJobs(ctx context.Context) ([]d3.JobState, error) {
+	records, err := s.rdb.HGetAll(ctx, s.jobs).Result()
+	if err != nil {
+		return nil, fmt.Errorf("d3redis: failed to load jobs: %w", err)
+	}
+	jobs := make([]d3.JobState, 0, len(records))
+	for _, data := range records {
+		var job d3.JobState
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return nil, fmt.Errorf("d3redis: failed to decode job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].SubmittedAt.Equal(jobs[j].SubmittedAt) {
+			return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt)
+		}
+		return jobs[i].MainTaskID < jobs[j].MainTaskID
+	})
+	return jobs, nil
+}
+
+// DeleteJob implements d3.StateStore
+func (s *Store) DeleteJob(ctx context.Context, mainTaskID d3.MainTaskID) error {
+	return s.delete(ctx, s.jobs, string(mainTaskID))
+}
+
+func (s *Store) put(ctx context.Context, key, field string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("d3redis: failed to encode %s: %w", key, err)
+	}
+	if err := s.rdb.HSet(ctx, key, field, data).Err(); err != nil {
+		return fmt.Errorf("d3redis: failed to save to %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *Store) delete(ctx context.Context, key, field string) error {
+	if err := s.rdb.HDel(ctx, key, field).Err(); err != nil {
+		return fmt.Errorf("d3redis: failed to delete from %s: %w", key, err)
+	}
+	return nil
+}
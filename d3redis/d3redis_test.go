@@ -0,0 +1,43 @@
+package d3redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/dragdropdo/dragdropdo-sdk-go/d3test"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+
+	d3test.RunStateStoreTests(t, New(rdb, ""))
+	if !server.Exists("d3:jobs") {
+		t.Error("Expected jobs under the default prefix")
+	}
+}
+
+func TestStore_SharedByInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	first := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer first.Close()
+	second := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer second.Close()
+
+	if err := New(first, "app:").SaveJob(ctx, d3.JobState{MainTaskID: "task-1", Action: d3.ActionZip}); err != nil {
+		t.Fatalf("SaveJob failed: %v", err)
+	}
+	jobs, err := New(second, "app:").LoadJobs(ctx)
+	if err != nil || len(jobs) != 1 || jobs[0].MainTaskID != "task-1" {
+		t.Errorf("Expected the other instance's job, got %+v, %v", jobs, err)
+	}
+	if jobs, _ := New(second, "other:").LoadJobs(ctx); len(jobs) != 0 {
+		t.Errorf("Expected prefixes to separate state, got %+v", jobs)
+	}
+}
@@ -0,0 +1,150 @@
+// Package d3sql stores D3 client state in an SQL database through
+// database/sql, so several instances of a service can share it: a restarted
+// instance resumes the operations another one submitted, e.g. with
+// Manager.Resume. It works with SQLite and PostgreSQL; the driver is the
+// caller's choice.
+//
+//	db, err := sql.Open("sqlite3", "d3-state.db")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	store, err := d3sql.New(ctx, db)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := d3.NewDragdropdo(d3.Config{APIKey: apiKey, StateStore: store})
+package d3sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+var _ d3.StateStore = (*Store)(nil)
+
+// schema creates the tables a Store uses. Times are stored as RFC 3339 text
+// in UTC, which sorts chronologically.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS d3_uploads (
+		file TEXT PRIMARY KEY,
+		state TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS d3_jobs (
+		main_task_id TEXT PRIMARY KEY,
+		submitted_at TEXT NOT NULL,
+		state TEXT NOT NULL
+	)`,
+}
+
+// Store is a d3.StateStore keeping its state in the tables d3_uploads and
+// d3_jobs, holding JSON records
+type Store struct {
+	db *sql.DB
+}
+
+// New creates the tables in db if needed and returns a Store using them.
+// The caller keeps ownership of db and closes it.
+func New(ctx context.Context, db *sql.DB) (*Store, error) {
+	for _, statement := range schema {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, fmt.Errorf("d3sql: failed to create tables: %w", err)
+		}
+	}
+	return &Store{db: db}, nil
+}
+
+// SaveUpload implements d3.StateStore
+func (s *Store) SaveUpload(ctx context.Context, state d3.UploadState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("d3sql: failed to encode upload: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO d3_uploads (file, state) VALUES ($1, $2)
+		ON CONFLICT (file) DO UPDATE SET state = excluded.state`,
+		state.File, string(data))
+	if err != nil {
+		return fmt.Errorf("d3sql: failed to save upload: %w", err)
+	}
+	return nil
+}
+
+// LoadUpload implements d3.StateStore
+func (s *Store) LoadUpload(ctx context.Context, file string) (*d3.UploadState, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT state FROM d3_uploads WHERE file = $1`, file).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("d3sql: failed to load upload: %w", err)
+	}
+	var state d3.UploadState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("d3sql: failed to decode upload: %w", err)
+	}
+	return &state, nil
+}
+
+// DeleteUpload implements d3.StateStore
+func (s *Store) DeleteUpload(ctx context.Context, file string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM d3_uploads WHERE file = $1`, file); err != nil {
+		return fmt.Errorf("d3sql: failed to delete upload: %w", err)
+	}
+	return nil
+}
+
+// SaveJob implements d3.StateStore
+func (s *Store) SaveJob(ctx context.Context, state d3.JobState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("d3sql: failed to encode job: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO d3_jobs (main_task_id, submitted_at, state) VALUES ($1, $2, $3)
+		ON CONFLICT (main_task_id) DO UPDATE SET submitted_at = excluded.submitted_at, state = excluded.state`,
+		string(state.MainTaskID), state.SubmittedAt.UTC().Format("2006-01-02T15:04:05.000000000Z"), string(data))
+	if err != nil {
+		return fmt.Errorf("d3sql: failed to save job: %w", err)
+	}
+	return nil
+}
+
+// LoadJobs implements d3.StateStore
+func (s *Store) LoadJobs(ctx context.Context) ([]d3.JobState, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT state FROM d3_jobs ORDER BY submitted_at, main_task_id`)
+	if err != nil {
+		return nil, fmt.Errorf("d3sql: failed to load jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []d3.JobState
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("d3sql: failed to load jobs: %w", err)
+		}
+		var job d3.JobState
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return nil, fmt.Errorf("d3sql: failed to decode job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("d3sql: failed to load jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// DeleteJob implements d3.StateStore
+func (s *Store) DeleteJob(ctx context.Context, mainTaskID d3.MainTaskID) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM d3_jobs WHERE main_task_id = $1`, string(mainTaskID)); err != nil {
+		return fmt.Errorf("d3sql: failed to delete job: %w", err)
+	}
+	return nil
+}
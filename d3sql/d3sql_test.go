@@ -0,0 +1,34 @@
+package d3sql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/dragdropdo/dragdropdo-sdk-go/d3test"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestStore_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		// The driver needs cgo
+		t.Skipf("SQLite unavailable: %v", err)
+	}
+
+	store, err := New(context.Background(), db)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	d3test.RunStateStoreTests(t, store)
+
+	// Creating the tables again is harmless
+	if _, err := New(context.Background(), db); err != nil {
+		t.Errorf("Expected New on an existing schema to succeed, got %v", err)
+	}
+}
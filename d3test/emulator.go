@@ -0,0 +1,558 @@
+package d3test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmulatorOptions configures the fake processing of an Emulator
+type EmulatorOptions struct {
+	// ProcessingTime is how long operations stay queued and running
+	ProcessingTime time.Duration
+	// FailPattern makes files whose names match it fail to process
+	FailPattern string
+}
+
+// Emulator is an http.Handler implementing the D3 API's upload, operation
+// and status endpoints in memory. Operations stay queued, then running, for
+// the processing time and then complete with fake results: conversions copy
+// the input bytes under the new extension, merges concatenate the inputs,
+// zips build a real zip archive and other actions return the input
+// unchanged. Results are downloaded from their DownloadLink, served by the
+// Emulator as well. Any API key is accepted.
+type Emulator struct {
+	options EmulatorOptions
+	now     func() time.Time
+
+	mu      sync.Mutex
+	nextID  int
+	uploads map[string]*pendingUpload
+	files   map[string]*storedFile
+	tasks   map[string]*task
+}
+
+// pendingUpload is a multipart upload that has not been completed yet
+type pendingUpload struct {
+	fileKey  string
+	fileName string
+	mimeType string
+	parts    map[int][]byte
+}
+
+// storedFile is an uploaded file
+type storedFile struct {
+	name     string
+	mimeType string
+	data     []byte
+}
+
+// task is an operation, whose results are computed when it is created and
+// revealed once the processing time has passed
+type task struct {
+	created time.Time
+	results []taskResult
+	// cancelled is set when the task was cancelled before finishing
+	cancelled bool
+}
+
+// taskResult is the outcome for one file of an operation
+type taskResult struct {
+	fileKey      string
+	name         string
+	data         []byte
+	errorMessage string
+}
+
+// NewEmulator returns an Emulator with no files or operations
+func NewEmulator(options EmulatorOptions) *Emulator {
+	return &Emulator{
+		options: options,
+		now:     time.Now,
+		uploads: map[string]*pendingUpload{},
+		files:   map[string]*storedFile{},
+		tasks:   map[string]*task{},
+	}
+}
+
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Part uploads and downloads stand in for presigned storage URLs
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/upload/"):
+		e.uploadPart(w, r)
+		return
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/download/"):
+		e.download(w, r)
+		return
+	}
+
+	if !authorized(r) {
+		writeError(w, http.StatusUnauthorized, "missing API key")
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/biz/initiate-upload":
+		e.initiateUpload(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/biz/complete-upload":
+		e.completeUpload(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/biz/abort-upload":
+		e.abortUpload(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/biz/supported-operation":
+		e.supportedOperation(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/biz/supported-operations":
+		e.supportedOperations(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/biz/do":
+		e.createOperation(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/biz/status/"):
+		e.status(w, r)
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v1/biz/cancel/"):
+		e.cancel(w, r)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s is not implemented by the emulator", r.Method, r.URL.Path))
+	}
+}
+
+// authorized accepts any API key sent with any of the client's auth schemes
+func authorized(r *http.Request) bool {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != "" ||
+		r.Header.Get("X-API-Key") != "" ||
+		r.URL.Query().Get("api_key") != ""
+}
+
+// newID returns a unique ID with prefix
+func (e *Emulator) newID(prefix string) string {
+	e.nextID++
+	return fmt.Sprintf("%s-%d", prefix, e.nextID)
+}
+
+// baseURL returns the URL the request reached the emulator at
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func (e *Emulator) initiateUpload(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		FileName string `json:"file_name"`
+		MimeType string `json:"mime_type"`
+		Parts    int    `json:"parts"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.FileName == "" {
+		writeError(w, http.StatusBadRequest, "file_name is required")
+		return
+	}
+	if body.Parts < 1 {
+		body.Parts = 1
+	}
+
+	e.mu.Lock()
+	fileKey, uploadID := e.newID("file"), e.newID("upload")
+	e.uploads[uploadID] = &pendingUpload{
+		fileKey:  fileKey,
+		fileName: body.FileName,
+		mimeType: body.MimeType,
+		parts:    map[int][]byte{},
+	}
+	e.mu.Unlock()
+
+	urls := make([]string, body.Parts)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("%s/upload/%s/%d?X-Amz-Signature=emulated", baseURL(r), uploadID, i+1)
+	}
+	writeData(w, map[string]interface{}{
+		"file_key":       fileKey,
+		"upload_id":      uploadID,
+		"object_name":    "uploads/" + fileKey,
+		"presigned_urls": urls,
+	})
+}
+
+func (e *Emulator) uploadPart(w http.ResponseWriter, r *http.Request) {
+	uploadID, number, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/upload/"), "/")
+	partNumber, err := strconv.Atoi(number)
+	if err != nil {
+		http.Error(w, "invalid part number", http.StatusBadRequest)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	e.mu.Lock()
+	upload, ok := e.uploads[uploadID]
+	if ok {
+		upload.parts[partNumber] = data
+	}
+	e.mu.Unlock()
+	if !ok {
+		http.Error(w, "NoSuchUpload", http.StatusNotFound)
+		return
+	}
+
+	sum := md5.Sum(data)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+}
+
+func (e *Emulator) completeUpload(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		FileKey  string `json:"file_key"`
+		UploadID string `json:"upload_id"`
+		Parts    []struct {
+			PartNumber int `json:"part_number"`
+		} `json:"parts"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	upload, ok := e.uploads[body.UploadID]
+	if !ok || upload.fileKey != body.FileKey {
+		writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+
+	// Assemble the parts and compute the multipart ETag storage would give
+	var data []byte
+	sums := md5.New()
+	for i := 1; i <= len(upload.parts); i++ {
+		part, ok := upload.parts[i]
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("part %d was not uploaded", i))
+			return
+		}
+		data = append(data, part...)
+		sum := md5.Sum(part)
+		sums.Write(sum[:])
+	}
+	if len(body.Parts) != len(upload.parts) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("expected %d parts, got %d", len(upload.parts), len(body.Parts)))
+		return
+	}
+
+	delete(e.uploads, body.UploadID)
+	e.files[upload.fileKey] = &storedFile{name: upload.fileName, mimeType: upload.mimeType, data: data}
+	writeData(w, map[string]interface{}{
+		"message":  "Upload completed",
+		"file_key": upload.fileKey,
+		"etag":     fmt.Sprintf("%s-%d", hex.EncodeToString(sums.Sum(nil)), len(upload.parts)),
+	})
+}
+
+func (e *Emulator) abortUpload(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UploadID string `json:"upload_id"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	e.mu.Lock()
+	delete(e.uploads, body.UploadID)
+	e.mu.Unlock()
+	writeData(w, map[string]interface{}{"message": "Upload aborted"})
+}
+
+// emulatedActions are the actions the emulator implements
+var emulatedActions = []string{"convert", "compress", "merge", "zip", "share", "lock", "unlock", "reset_password"}
+
+func (e *Emulator) supportedOperation(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Ext    string `json:"ext"`
+		Action string `json:"action"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	supported := body.Action == ""
+	for _, action := range emulatedActions {
+		supported = supported || action == body.Action
+	}
+	writeData(w, map[string]interface{}{
+		"supported":         supported,
+		"ext":               body.Ext,
+		"action":            body.Action,
+		"available_actions": emulatedActions,
+	})
+}
+
+// emulatedExtensions are the extensions listed in the emulator's catalog,
+// each supporting every emulated action
+var emulatedExtensions = []string{"docx", "jpg", "pdf", "png", "txt", "xlsx"}
+
+func (e *Emulator) supportedOperations(w http.ResponseWriter, r *http.Request) {
+	required := func(values ...string) map[string]interface{} {
+		spec := map[string]interface{}{"type": "string", "required": true}
+		if len(values) > 0 {
+			spec["values"] = values
+		}
+		return spec
+	}
+	params := map[string]map[string]interface{}{
+		"convert":        {"convert_to": required(emulatedExtensions...)},
+		"compress":       {"compression_value": required("low", "recommended", "extreme")},
+		"lock":           {"password": required()},
+		"unlock":         {"password": required()},
+		"reset_password": {"old_password": required(), "new_password": required()},
+	}
+
+	extensions := map[string]interface{}{}
+	for _, ext := range emulatedExtensions {
+		actions := map[string]interface{}{}
+		for _, action := range emulatedActions {
+			spec := map[string]interface{}{}
+			if p, ok := params[action]; ok {
+				spec["parameters"] = p
+			}
+			actions[action] = spec
+		}
+		extensions[ext] = actions
+	}
+	writeData(w, map[string]interface{}{"extensions": extensions})
+}
+
+func (e *Emulator) createOperation(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Action     string                 `json:"action"`
+		FileKeys   []string               `json:"file_keys"`
+		Parameters map[string]interface{} `json:"parameters"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	convertTo, _ := body.Parameters["convert_to"].(string)
+	switch {
+	case body.Action == "":
+		writeError(w, http.StatusBadRequest, "action is required")
+		return
+	case len(body.FileKeys) == 0:
+		writeError(w, http.StatusBadRequest, "file_keys is required")
+		return
+	case body.Action == "convert" && convertTo == "":
+		writeError(w, http.StatusBadRequest, "parameters.convert_to is required")
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	inputs := make([]taskResult, len(body.FileKeys))
+	for i, key := range body.FileKeys {
+		inputs[i] = e.process(key)
+	}
+
+	var results []taskResult
+	switch body.Action {
+	case "convert":
+		for _, input := range inputs {
+			input.name = strings.TrimSuffix(input.name, path.Ext(input.name)) + "." + convertTo
+			results = append(results, input)
+		}
+	case "merge", "zip":
+		results = []taskResult{combine(body.Action, inputs)}
+	default:
+		results = inputs
+	}
+
+	taskID := e.newID("task")
+	e.tasks[taskID] = &task{created: e.now(), results: results}
+	writeData(w, map[string]interface{}{"main_task_id": taskID})
+}
+
+// process returns the result of processing the file with key, failing if
+// the file is unknown or its name matches the fail pattern
+func (e *Emulator) process(key string) taskResult {
+	file, ok := e.files[key]
+	if !ok {
+		return taskResult{fileKey: key, errorMessage: "file not found"}
+	}
+	if matched, _ := path.Match(e.options.FailPattern, file.name); matched {
+		return taskResult{fileKey: key, name: file.name, errorMessage: "emulated processing failure"}
+	}
+	return taskResult{fileKey: key, name: file.name, data: file.data}
+}
+
+// combine returns the single result of a merge or zip of inputs, which
+// fails if any input failed
+func combine(action string, inputs []taskResult) taskResult {
+	result := taskResult{fileKey: inputs[0].fileKey}
+	for _, input := range inputs {
+		if input.errorMessage != "" {
+			result.errorMessage = fmt.Sprintf("%s: %s", input.fileKey, input.errorMessage)
+			return result
+		}
+	}
+
+	if action == "merge" {
+		result.name = "merged" + path.Ext(inputs[0].name)
+		for _, input := range inputs {
+			result.data = append(result.data, input.data...)
+		}
+		return result
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, input := range inputs {
+		f, err := archive.Create(input.name)
+		if err == nil {
+			_, err = f.Write(input.data)
+		}
+		if err != nil {
+			result.errorMessage = err.Error()
+			return result
+		}
+	}
+	if err := archive.Close(); err != nil {
+		result.errorMessage = err.Error()
+		return result
+	}
+	result.name = "archive.zip"
+	result.data = buf.Bytes()
+	return result
+}
+
+func (e *Emulator) status(w http.ResponseWriter, r *http.Request) {
+	taskID, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/biz/status/"), "/")
+
+	e.mu.Lock()
+	t, ok := e.tasks[taskID]
+	cancelled := ok && t.cancelled
+	e.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+
+	// Queued for the first half of the processing time, then running, unless
+	// cancelled before finishing
+	elapsed := e.now().Sub(t.created)
+	running := ""
+	switch {
+	case cancelled:
+		running = "cancelled"
+	case elapsed < e.options.ProcessingTime/2:
+		running = "queued"
+	case elapsed < e.options.ProcessingTime:
+		running = "running"
+	}
+
+	files := make([]map[string]interface{}, len(t.results))
+	completed := 0
+	for i, result := range t.results {
+		file := map[string]interface{}{"file_key": result.fileKey}
+		switch {
+		case running != "":
+			file["status"] = running
+		case result.errorMessage != "":
+			file["status"] = "failed"
+			file["error_code"] = "PROCESSING_FAILED"
+			file["error_message"] = result.errorMessage
+		default:
+			completed++
+			file["status"] = "completed"
+			file["download_link"] = fmt.Sprintf("%s/download/%s/%d/%s", baseURL(r), taskID, i, url.PathEscape(result.name))
+		}
+		files[i] = file
+	}
+
+	status := running
+	if status == "" {
+		switch completed {
+		case len(t.results):
+			status = "completed"
+		case 0:
+			status = "failed"
+		default:
+			status = "partially_completed"
+		}
+	}
+	writeData(w, map[string]interface{}{
+		"operation_status": status,
+		"files_data":       files,
+	})
+}
+
+// cancel cancels a task that has not finished; a finished task keeps its
+// results
+func (e *Emulator) cancel(w http.ResponseWriter, r *http.Request) {
+	taskID := strings.TrimPrefix(r.URL.Path, "/v1/biz/cancel/")
+
+	e.mu.Lock()
+	t, ok := e.tasks[taskID]
+	if ok && e.now().Sub(t.created) < e.options.ProcessingTime {
+		t.cancelled = true
+	}
+	e.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	writeData(w, map[string]interface{}{"message": "Operation cancelled"})
+}
+
+func (e *Emulator) download(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/download/"), "/", 3)
+	if len(parts) != 3 {
+		http.NotFound(w, r)
+		return
+	}
+	index, err := strconv.Atoi(parts[1])
+
+	e.mu.Lock()
+	t, ok := e.tasks[parts[0]]
+	e.mu.Unlock()
+	if err != nil || !ok || index < 0 || index >= len(t.results) || t.results[index].errorMessage != "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	result := t.results[index]
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(result.data)))
+	w.Write(result.data)
+}
+
+// decodeBody decodes the JSON request body into v, answering 400 if it is
+// invalid
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeData writes a successful response in the API's envelope
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+// writeError writes an error response in the API's envelope
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error":   map[string]interface{}{"message": message},
+	})
+}
@@ -0,0 +1,240 @@
+package d3test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// newTestClient starts a server and returns a client using it
+func newTestClient(t *testing.T, options EmulatorOptions) (*d3.Dragdropdo, *Emulator) {
+	t.Helper()
+	server := NewServerWithOptions(t, options)
+	return server.Client(t, d3.WithConfig(d3.Config{ChunkSize: 5 * 1024 * 1024})), server.Emulator
+}
+
+// upload uploads content as a file named name
+func upload(t *testing.T, client *d3.Dragdropdo, name string, content []byte) d3.FileKey {
+	t.Helper()
+	resp, err := client.UploadFile(d3.UploadFileOptions{File: TempFileWithContent(t, name, content), FileName: name})
+	if err != nil {
+		t.Fatalf("Upload of %s failed: %v", name, err)
+	}
+	return resp.FileKey
+}
+
+// fetch downloads link
+func fetch(t *testing.T, link string) []byte {
+	t.Helper()
+	resp, err := http.Get(link)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200 for %s, got %d", link, resp.StatusCode)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	return data
+}
+
+func TestEmulator_ConvertRoundTrip(t *testing.T) {
+	client, _ := newTestClient(t, EmulatorOptions{})
+
+	// Large enough for a multipart upload, whose ETag the client verifies
+	content := bytes.Repeat([]byte("report "), 2*1024*1024)
+	key := upload(t, client, "report.docx", content)
+
+	op, err := client.Convert([]d3.FileKey{key}, "pdf", nil)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	status, err := client.PollStatus(d3.PollStatusOptions{
+		StatusOptions: d3.StatusOptions{MainTaskID: op.MainTaskID},
+		Interval:      time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if status.OperationStatus != d3.StatusCompleted || len(status.FilesData) != 1 {
+		t.Fatalf("Expected one completed file, got %+v", status)
+	}
+	link := status.FilesData[0].DownloadLink
+	if filepath.Base(link) != "report.pdf" {
+		t.Errorf("Expected the result to be named report.pdf, got %s", link)
+	}
+	if !bytes.Equal(fetch(t, link), content) {
+		t.Error("Expected the converted file to copy the input bytes")
+	}
+}
+
+func TestEmulator_ZipAndMerge(t *testing.T) {
+	client, _ := newTestClient(t, EmulatorOptions{})
+	a := upload(t, client, "a.txt", []byte("alpha"))
+	b := upload(t, client, "b.txt", []byte("beta"))
+
+	op, err := client.Merge([]d3.FileKey{a, b}, nil)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	status, _ := client.GetStatus(d3.StatusOptions{MainTaskID: op.MainTaskID})
+	if got := fetch(t, status.FilesData[0].DownloadLink); string(got) != "alphabeta" {
+		t.Errorf("Expected the merged inputs, got %q", got)
+	}
+
+	op, err = client.Zip([]d3.FileKey{a, b}, nil)
+	if err != nil {
+		t.Fatalf("Zip failed: %v", err)
+	}
+	status, _ = client.GetStatus(d3.StatusOptions{MainTaskID: op.MainTaskID})
+	data := fetch(t, status.FilesData[0].DownloadLink)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil || len(archive.File) != 2 || archive.File[0].Name != "a.txt" || archive.File[1].Name != "b.txt" {
+		t.Errorf("Expected a zip of a.txt and b.txt, got %v", err)
+	}
+}
+
+func TestEmulator_StatusTransitionsAndFailures(t *testing.T) {
+	client, emulator := newTestClient(t, EmulatorOptions{ProcessingTime: time.Minute, FailPattern: "*.bad"})
+	now := time.Now()
+	emulator.now = func() time.Time { return now }
+
+	good := upload(t, client, "good.txt", []byte("ok"))
+	bad := upload(t, client, "broken.bad", []byte("ko"))
+	op, err := client.Compress([]d3.FileKey{good, bad}, "recommended", nil)
+	if err != nil {
+		t.Fatalf("Compress failed: %v", err)
+	}
+
+	for _, step := range []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{0, "queued"},
+		{40 * time.Second, "running"},
+		{time.Minute, d3.StatusPartiallyCompleted},
+	} {
+		now = now.Add(step.elapsed)
+		status, err := client.GetStatus(d3.StatusOptions{MainTaskID: op.MainTaskID})
+		if err != nil || status.OperationStatus != step.want {
+			t.Fatalf("Expected %s, got %+v, %v", step.want, status, err)
+		}
+	}
+
+	status, _ := client.GetStatus(d3.StatusOptions{MainTaskID: op.MainTaskID})
+	if failed := status.FailedFiles(); len(failed) != 1 || failed[0].FileKey != bad {
+		t.Errorf("Expected %s to fail, got %+v", bad, status.FilesData)
+	}
+
+	if _, err := client.Convert([]d3.FileKey{good}, "", nil); !d3.IsD3APIError(err) {
+		t.Errorf("Expected an API error for a conversion without a target, got %v", err)
+	}
+}
+
+func TestEmulator_SupportedOperations(t *testing.T) {
+	client, _ := newTestClient(t, EmulatorOptions{})
+
+	catalog, err := client.GetSupportedOperations()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !catalog.Supports("pdf", "lock") || len(catalog.Actions("docx")) != len(emulatedActions) {
+		t.Errorf("Expected every emulated action for each extension, got %v", catalog.Extensions)
+	}
+	if spec, _ := catalog.Lookup("docx", "convert"); !spec.Parameters["convert_to"].Required {
+		t.Errorf("Expected convert_to to be required, got %+v", spec)
+	}
+}
+
+func TestEmulator_JobCancel(t *testing.T) {
+	client, _ := newTestClient(t, EmulatorOptions{ProcessingTime: time.Minute})
+	key := upload(t, client, "report.docx", []byte("report"))
+
+	ctx := context.Background()
+	job, err := client.Submit(ctx, d3.OperationOptions{
+		Action:     d3.ActionConvert,
+		FileKeys:   []d3.FileKey{key},
+		Parameters: map[string]interface{}{"convert_to": "pdf"},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if err := job.Cancel(ctx); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+
+	_, err = job.Wait(ctx)
+	var failure *d3.PartialFailure
+	if !errors.As(err, &failure) || failure.OperationStatus != d3.StatusCancelled {
+		t.Fatalf("Expected a cancelled PartialFailure, got %v", err)
+	}
+	if status := job.Status(); status == nil || status.OperationStatus != d3.StatusCancelled {
+		t.Errorf("Expected cancelled status, got %+v", status)
+	}
+}
+
+func TestEmulator_BulkProcessor(t *testing.T) {
+	client, _ := newTestClient(t, EmulatorOptions{FailPattern: "*.bad"})
+
+	source := t.TempDir()
+	for name, content := range map[string]string{
+		"a.docx":     "alpha",
+		"b.docx":     "beta",
+		"broken.bad": "ko",
+		"notes.txt":  "skipped",
+		"sub/c.docx": "gamma",
+	} {
+		path := filepath.Join(source, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+	}
+
+	dest := t.TempDir()
+	report, err := d3.NewBulkProcessor(client, d3.BulkOptions{
+		Source:      source,
+		Pattern:     "*.docx",
+		Recursive:   true,
+		Action:      d3.ActionConvert,
+		Parameters:  map[string]interface{}{"convert_to": "pdf"},
+		Destination: dest,
+		Manager:     d3.ManagerOptions{Wait: d3.WaitOptions{Interval: time.Millisecond}},
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Succeeded != 3 || report.Failed != 0 {
+		t.Fatalf("Expected 3 files to succeed, got %s", report)
+	}
+	for _, want := range []string{"a.pdf", "b.pdf", filepath.Join("sub", "c.pdf")} {
+		if _, err := os.Stat(filepath.Join(dest, want)); err != nil {
+			t.Errorf("Expected output %s: %v", want, err)
+		}
+	}
+
+	// Failed files are reported without stopping the others
+	report, err = d3.NewBulkProcessor(client, d3.BulkOptions{
+		Source:      source,
+		Action:      d3.ActionZip,
+		BatchSize:   2,
+		Destination: t.TempDir(),
+		Manager:     d3.ManagerOptions{Wait: d3.WaitOptions{Interval: time.Millisecond}},
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Files) != 4 || report.Failed != 2 || report.Succeeded != 2 {
+		t.Errorf("Expected the batch with broken.bad to fail, got %s", report)
+	}
+	if report.Err() == nil || report.Files[0].Outputs[0] != report.Files[1].Outputs[0] {
+		t.Errorf("Expected a shared archive for the first batch and an error, got %+v", report.Files)
+	}
+}
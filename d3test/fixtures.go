@@ -0,0 +1,61 @@
+package d3test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fixturePattern is repeated to fill fixture files, so that every part of
+// a multipart upload has different content
+var fixturePattern = []byte("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-_")
+
+// TempFile creates a file named name of size bytes in a temporary directory
+// removed when the test ends, and returns its path. The content is a
+// deterministic pattern; see FixtureContent.
+func TempFile(t testing.TB, name string, size int64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("d3test: failed to create fixture: %v", err)
+	}
+	defer f.Close()
+
+	chunk := bytes.Repeat(fixturePattern, 16*1024)
+	for written := int64(0); written < size; {
+		n := int64(len(chunk))
+		if size-written < n {
+			n = size - written
+		}
+		if _, err := f.Write(chunk[:n]); err != nil {
+			t.Fatalf("d3test: failed to write fixture: %v", err)
+		}
+		written += n
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("d3test: failed to write fixture: %v", err)
+	}
+	return path
+}
+
+// TempFileWithContent creates a file named name with content in a
+// temporary directory removed when the test ends, and returns its path
+func TempFileWithContent(t testing.TB, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("d3test: failed to create fixture: %v", err)
+	}
+	return path
+}
+
+// FixtureContent returns the content of a TempFile of size bytes
+func FixtureContent(size int64) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = fixturePattern[i%len(fixturePattern)]
+	}
+	return content
+}
@@ -0,0 +1,205 @@
+// Package d3test provides helpers for integration tests of code that uses
+// the D3 client: a fake API server that records the requests it receives,
+// fixture files of any size and assertions on the recorded requests.
+//
+//	server := d3test.NewServer(t)
+//	client := server.Client(t)
+//	file := d3test.TempFile(t, "report.pdf", 12<<20)
+//
+//	resp, err := client.UploadFile(d3.UploadFileOptions{File: file, FileName: "report.pdf"})
+//	...
+//	server.AssertRequested(t, "POST", "/v1/biz/complete-upload")
+//
+// The server is backed by an Emulator, so uploads, operations, status
+// polls and downloads behave like the real API. Handle overrides single
+// endpoints, e.g. to inject failures. For unit tests without a server, see
+// the d3mock package.
+package d3test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// Request is a request received by a Server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// DecodeJSON decodes the request's JSON body into v
+func (r Request) DecodeJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Server is a fake D3 API listening on a local port
+type Server struct {
+	*httptest.Server
+	// Emulator serves the requests that no handler set with Handle matches
+	Emulator *Emulator
+
+	mu       sync.Mutex
+	requests []Request
+	handlers map[string]http.HandlerFunc
+}
+
+// NewServer starts a Server backed by an Emulator whose operations complete
+// immediately. It is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	return NewServerWithOptions(t, EmulatorOptions{})
+}
+
+// NewServerWithOptions starts a Server backed by an Emulator with options.
+// It is closed when the test ends.
+func NewServerWithOptions(t testing.TB, options EmulatorOptions) *Server {
+	t.Helper()
+	s := &Server{
+		Emulator: NewEmulator(options),
+		handlers: map[string]http.HandlerFunc{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	handler := s.handlers[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+	s.Emulator.ServeHTTP(w, r)
+}
+
+// Client returns a client with a test API key and opts applied, using the
+// server as its base URL. It is closed when the test ends.
+func (s *Server) Client(t testing.TB, opts ...d3.Option) *d3.Dragdropdo {
+	t.Helper()
+	opts = append(opts, d3.WithBaseURL(s.URL))
+	client, err := d3.NewClient("test-key", opts...)
+	if err != nil {
+		t.Fatalf("d3test: failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close(context.Background()) })
+	return client
+}
+
+// Handle makes handler answer requests with method to path, e.g.
+// "POST", "/v1/biz/do", instead of the Emulator. A nil handler restores
+// the Emulator.
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if handler == nil {
+		delete(s.handlers, method+" "+path)
+		return
+	}
+	s.handlers[method+" "+path] = handler
+}
+
+// Fail makes requests with method to path fail with status and message in
+// the API's error format
+func (s *Server) Fail(method, path string, status int, message string) {
+	s.Handle(method, path, func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, status, message)
+	})
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received with method to path. A path
+// ending in "/" matches every path it is a prefix of, e.g. "/upload/" for
+// the part uploads.
+func (s *Server) RequestsTo(method, path string) []Request {
+	var matched []Request
+	for _, r := range s.Requests() {
+		if r.Method != method {
+			continue
+		}
+		if r.Path == path || strings.HasSuffix(path, "/") && strings.HasPrefix(r.Path, path) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// Reset forgets the requests received so far
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// AssertRequested fails the test unless a request with method to path was
+// received, and returns the last such request
+func (s *Server) AssertRequested(t testing.TB, method, path string) Request {
+	t.Helper()
+	matched := s.RequestsTo(method, path)
+	if len(matched) == 0 {
+		t.Errorf("Expected a %s %s request, got %s", method, path, s.summary())
+		return Request{}
+	}
+	return matched[len(matched)-1]
+}
+
+// AssertRequestCount fails the test unless exactly n requests with method
+// to path were received
+func (s *Server) AssertRequestCount(t testing.TB, method, path string, n int) {
+	t.Helper()
+	if got := len(s.RequestsTo(method, path)); got != n {
+		t.Errorf("Expected %d %s %s requests, got %d", n, method, path, got)
+	}
+}
+
+// AssertNotRequested fails the test if a request with method to path was
+// received
+func (s *Server) AssertNotRequested(t testing.TB, method, path string) {
+	t.Helper()
+	if got := len(s.RequestsTo(method, path)); got != 0 {
+		t.Errorf("Expected no %s %s request, got %d", method, path, got)
+	}
+}
+
+// summary lists the requests received, for failure messages
+func (s *Server) summary() string {
+	requests := s.Requests()
+	if len(requests) == 0 {
+		return "no requests"
+	}
+	lines := make([]string, len(requests))
+	for i, r := range requests {
+		lines[i] = r.Method + " " + r.Path
+	}
+	return strings.Join(lines, ", ")
+}
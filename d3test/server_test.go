@@ -0,0 +1,75 @@
+package d3test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+func TestServer_RecordsRequests(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t, d3.WithConfig(d3.Config{ChunkSize: 5 * 1024 * 1024}))
+	file := TempFile(t, "video.mp4", 12*1024*1024)
+
+	resp, err := client.UploadFile(d3.UploadFileOptions{File: file, FileName: "video.mp4"})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	initiate := server.AssertRequested(t, "POST", "/v1/biz/initiate-upload")
+	var body struct {
+		Parts int `json:"parts"`
+	}
+	if err := initiate.DecodeJSON(&body); err != nil || body.Parts != 3 {
+		t.Errorf("Expected 3 parts requested, got %d, %v", body.Parts, err)
+	}
+	if got := initiate.Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Expected the test API key, got %q", got)
+	}
+	server.AssertRequestCount(t, "PUT", "/upload/", 3)
+	complete := server.AssertRequested(t, "POST", "/v1/biz/complete-upload")
+	if !bytes.Contains(complete.Body, []byte(resp.FileKey)) {
+		t.Errorf("Expected the completion to name %s, got %s", resp.FileKey, complete.Body)
+	}
+	server.AssertNotRequested(t, "POST", "/v1/biz/do")
+
+	server.Reset()
+	if len(server.Requests()) != 0 {
+		t.Error("Expected Reset to forget the requests")
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	server := NewServer(t)
+	client := server.Client(t)
+
+	server.Fail("POST", "/v1/biz/do", http.StatusTooManyRequests, "slow down")
+	_, err := client.Convert([]d3.FileKey{"file-1"}, "pdf", nil)
+	var apiErr *d3.D3APIError
+	if !errors.As(err, &apiErr) || *apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected the injected 429, got %v", err)
+	}
+
+	server.Handle("POST", "/v1/biz/do", nil)
+	if _, err := client.Convert([]d3.FileKey{"file-1"}, "pdf", nil); err != nil {
+		t.Errorf("Expected the emulator to answer again, got %v", err)
+	}
+}
+
+func TestTempFile(t *testing.T) {
+	const size = 3*1024*1024 + 17
+	data, err := os.ReadFile(TempFile(t, "data.bin", size))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if !bytes.Equal(data, FixtureContent(size)) {
+		t.Errorf("Expected the fixture pattern of %d bytes, got %d bytes", size, len(data))
+	}
+	if data, _ := os.ReadFile(TempFile(t, "empty.bin", 0)); len(data) != 0 {
+		t.Errorf("Expected an empty fixture, got %d bytes", len(data))
+	}
+}
@@ -0,0 +1,78 @@
+package d3test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// RunStateStoreTests checks that store, which must be empty, implements the
+// d3.StateStore contract. Backends outside this module can run it from their
+// own tests.
+func RunStateStoreTests(t *testing.T, store d3.StateStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if saved, err := store.LoadUpload(ctx, "/data/missing.pdf"); err != nil || saved != nil {
+		t.Errorf("Expected no upload for an unknown file, got %+v, %v", saved, err)
+	}
+	if err := store.DeleteUpload(ctx, "/data/missing.pdf"); err != nil {
+		t.Errorf("Expected deleting an unknown upload to succeed, got %v", err)
+	}
+
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	upload := d3.UploadState{
+		File:       "/data/report.pdf",
+		Size:       3000,
+		ModTime:    modTime,
+		FileName:   "report.pdf",
+		FileKey:    "file-key-1",
+		UploadID:   "upload-id-1",
+		ObjectName: "objects/report.pdf",
+		Parts:      3,
+		Completed:  []d3.UploadedPart{{PartNumber: 1, ETag: "etag-1", MD5: "0cc175b9c0f1b6a831c399e269772661"}},
+		UpdatedAt:  modTime,
+	}
+	if err := store.SaveUpload(ctx, upload); err != nil {
+		t.Fatalf("SaveUpload failed: %v", err)
+	}
+	upload.Completed = append(upload.Completed, d3.UploadedPart{PartNumber: 2, ETag: "etag-2", MD5: "92eb5ffee6ae2fec3ad71c777531578f"})
+	if err := store.SaveUpload(ctx, upload); err != nil {
+		t.Fatalf("SaveUpload failed: %v", err)
+	}
+	saved, err := store.LoadUpload(ctx, upload.File)
+	if err != nil || saved == nil || !reflect.DeepEqual(*saved, upload) {
+		t.Errorf("Expected the last saved upload %+v, got %+v, %v", upload, saved, err)
+	}
+	if err := store.DeleteUpload(ctx, upload.File); err != nil {
+		t.Errorf("DeleteUpload failed: %v", err)
+	}
+	if saved, err := store.LoadUpload(ctx, upload.File); err != nil || saved != nil {
+		t.Errorf("Expected the upload to be deleted, got %+v, %v", saved, err)
+	}
+
+	jobs, err := store.LoadJobs(ctx)
+	if err != nil || len(jobs) != 0 {
+		t.Errorf("Expected no jobs, got %+v, %v", jobs, err)
+	}
+	newer := d3.JobState{MainTaskID: "task-2", Action: d3.ActionZip, FileKeys: []d3.FileKey{"file-key-1", "file-key-2"}, SubmittedAt: modTime}
+	older := d3.JobState{MainTaskID: "task-1", Action: d3.ActionMerge, FileKeys: []d3.FileKey{"file-key-3"}, SubmittedAt: modTime.Add(-time.Minute)}
+	for _, job := range []d3.JobState{newer, older} {
+		if err := store.SaveJob(ctx, job); err != nil {
+			t.Fatalf("SaveJob failed: %v", err)
+		}
+	}
+	jobs, err = store.LoadJobs(ctx)
+	if err != nil || !reflect.DeepEqual(jobs, []d3.JobState{older, newer}) {
+		t.Errorf("Expected %+v, oldest first, got %+v, %v", []d3.JobState{older, newer}, jobs, err)
+	}
+	if err := store.DeleteJob(ctx, older.MainTaskID); err != nil {
+		t.Errorf("DeleteJob failed: %v", err)
+	}
+	if jobs, err := store.LoadJobs(ctx); err != nil || len(jobs) != 1 || jobs[0].MainTaskID != newer.MainTaskID {
+		t.Errorf("Expected only %s left, got %+v, %v", newer.MainTaskID, jobs, err)
+	}
+}
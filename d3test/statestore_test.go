@@ -0,0 +1,12 @@
+package d3test
+
+import (
+	"path/filepath"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+func TestRunStateStoreTests_FileStateStore(t *testing.T) {
+	RunStateStoreTests(t, d3.NewFileStateStore(filepath.Join(t.TempDir(), "state.json")))
+}
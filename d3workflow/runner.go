@@ -0,0 +1,263 @@
+package d3workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// Runner executes workflows with a client
+type Runner struct {
+	// Client uploads the files and runs the operations
+	Client d3.D3API
+	// Logger receives workflow.step.started, workflow.step.completed and
+	// workflow.step.skipped events
+	Logger *slog.Logger
+}
+
+// NewRunner returns a Runner using client
+func NewRunner(client d3.D3API) *Runner {
+	return &Runner{Client: client}
+}
+
+// Result describes a finished workflow run
+type Result struct {
+	// Outputs are the files written to the output directory
+	Outputs []string
+	// TaskIDs are the main task IDs of the steps, in order
+	TaskIDs []d3.MainTaskID
+	// Resumed is the number of steps completed by an earlier run
+	Resumed int
+}
+
+// Run executes w, resuming from the progress saved by an earlier run of the
+// same workflow over the same inputs. Progress is kept in a .d3workflow
+// directory inside the output directory and removed once the workflow
+// completes. A step whose operation does not complete fails the run; running
+// it again submits the step anew.
+func (r *Runner) Run(ctx context.Context, w *Workflow) (*Result, error) {
+	if err := w.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	inputs, err := w.inputFiles()
+	if err != nil {
+		return nil, err
+	}
+	output := w.path(w.Output)
+	st, err := loadState(filepath.Join(output, stateDir), inputs, w.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	files := inputs
+	for i, step := range w.Steps {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		progress := &st.Steps[i]
+		if progress.done() {
+			r.log(ctx, "workflow.step.skipped", w, i, progress.TaskID)
+			result.TaskIDs = append(result.TaskIDs, progress.TaskID)
+			result.Resumed++
+			files = progress.Outputs
+			continue
+		}
+
+		dir := filepath.Join(st.dir, fmt.Sprintf("step-%d", i+1))
+		if i == len(w.Steps)-1 {
+			dir = output
+		}
+		if files, err = r.runStep(ctx, w, st, i, files, dir); err != nil {
+			return nil, fmt.Errorf("workflow %s: step %d (%s): %w", w.Name, i+1, step.Action, err)
+		}
+		result.TaskIDs = append(result.TaskIDs, progress.TaskID)
+	}
+
+	if err := os.RemoveAll(st.dir); err != nil {
+		return nil, fmt.Errorf("failed to remove workflow state: %w", err)
+	}
+	result.Outputs = files
+	return result, nil
+}
+
+// runStep uploads files, runs step i on them and downloads its results into
+// dir, saving progress after each stage
+func (r *Runner) runStep(ctx context.Context, w *Workflow, st *state, i int, files []string, dir string) ([]string, error) {
+	step := w.Steps[i]
+	progress := &st.Steps[i]
+	r.log(ctx, "workflow.step.started", w, i, progress.TaskID)
+
+	if progress.TaskID == "" {
+		keys := make([]d3.FileKey, len(files))
+		for j, file := range files {
+			key, err := r.upload(ctx, st, file)
+			if err != nil {
+				return nil, err
+			}
+			keys[j] = key
+		}
+
+		resp, err := r.Client.CreateOperation(d3.OperationOptions{
+			Action:     step.Action,
+			FileKeys:   keys,
+			Parameters: step.Parameters,
+			Notes:      step.Notes,
+		})
+		if err != nil {
+			return nil, err
+		}
+		progress.TaskID = resp.MainTaskID
+		if err := st.save(); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := r.poll(ctx, w, progress.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if status.OperationStatus != d3.StatusCompleted {
+		// Submit the step again on the next run
+		progress.TaskID = ""
+		if err := st.save(); err != nil {
+			return nil, err
+		}
+		return nil, operationError(status)
+	}
+
+	outputs, err := r.downloadAll(ctx, status, dir)
+	if err != nil {
+		return nil, err
+	}
+	progress.Outputs = outputs
+	progress.Done = true
+	if err := st.save(); err != nil {
+		return nil, err
+	}
+	r.log(ctx, "workflow.step.completed", w, i, progress.TaskID)
+	return outputs, nil
+}
+
+// upload uploads file unless an earlier run already uploaded it unchanged
+func (r *Runner) upload(ctx context.Context, st *state, file string) (d3.FileKey, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+	if u, ok := st.Uploads[file]; ok && u.Size == info.Size() && u.ModTime.Equal(info.ModTime()) {
+		return u.FileKey, nil
+	}
+
+	resp, err := r.Client.UploadFileContext(ctx, d3.UploadFileOptions{
+		File:     file,
+		FileName: filepath.Base(file),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", file, err)
+	}
+	st.Uploads[file] = uploadState{FileKey: resp.FileKey, Size: info.Size(), ModTime: info.ModTime()}
+	return resp.FileKey, st.save()
+}
+
+// poll fetches the status of taskID until the operation finishes, ctx is
+// done or the workflow's poll timeout passes
+func (r *Runner) poll(ctx context.Context, w *Workflow, taskID d3.MainTaskID) (*d3.StatusResponse, error) {
+	interval, timeout, _ := w.pollDurations()
+	if interval <= 0 {
+		interval = d3.DefaultPollInterval
+	}
+	if timeout <= 0 {
+		timeout = d3.DefaultPollTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := r.Client.GetStatus(d3.StatusOptions{MainTaskID: taskID})
+		if err != nil {
+			return nil, err
+		}
+		if status.Done() {
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("operation %s still %s after %s", taskID, status.OperationStatus, timeout)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// operationError describes an operation that finished without completing
+func operationError(status *d3.StatusResponse) error {
+	var errs []error
+	for _, file := range status.FailedFiles() {
+		errs = append(errs, fmt.Errorf("%s: %s", file.FileKey, file.ErrorMessage))
+	}
+	err := fmt.Errorf("operation %s", status.OperationStatus)
+	if len(errs) > 0 {
+		err = fmt.Errorf("%w: %w", err, errors.Join(errs...))
+	}
+	return err
+}
+
+// downloadAll downloads the results of status into dir, decrypted with the
+// client's Config.Encryption if set
+func (r *Runner) downloadAll(ctx context.Context, status *d3.StatusResponse, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	results, err := r.Client.Results(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	outputs := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.DownloadLink == "" {
+			return nil, fmt.Errorf("no download link for %s", result.FileKey)
+		}
+		dest, err := result.DownloadContext(ctx, filepath.Join(dir, uniqueName(result.Name, used)))
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, dest)
+	}
+	return outputs, nil
+}
+
+// uniqueName returns name, or name with a numeric suffix if it is in used,
+// and marks the result used
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	ext := filepath.Ext(name)
+	for n := 2; used[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), n, ext)
+	}
+	used[unique] = true
+	return unique
+}
+
+func (r *Runner) log(ctx context.Context, event string, w *Workflow, i int, taskID d3.MainTaskID) {
+	if r.Logger == nil {
+		return
+	}
+	r.Logger.LogAttrs(ctx, slog.LevelInfo, event,
+		slog.String("workflow", w.Name),
+		slog.Int("step", i+1),
+		slog.String("action", string(w.Steps[i].Action)),
+		slog.String("main_task_id", string(taskID)))
+}
@@ -0,0 +1,165 @@
+package d3workflow
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"github.com/dragdropdo/dragdropdo-sdk-go/d3test"
+)
+
+func newWorkflow(t *testing.T, steps ...Step) *Workflow {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "in", "a.txt"), "alpha ")
+	writeFile(t, filepath.Join(dir, "in", "b.txt"), "beta")
+	return &Workflow{
+		Name:         "test",
+		Inputs:       []string{"in/*.txt"},
+		Steps:        steps,
+		Output:       "out",
+		PollInterval: "10ms",
+		dir:          dir,
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	server := d3test.NewServer(t)
+	w := newWorkflow(t,
+		Step{Action: "convert", Parameters: map[string]interface{}{"convert_to": "md"}},
+		Step{Action: "merge"},
+	)
+
+	result, err := NewRunner(server.Client(t)).Run(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Outputs) != 1 || filepath.Base(result.Outputs[0]) != "merged.md" {
+		t.Fatalf("Expected merged.md, got %v", result.Outputs)
+	}
+	data, err := os.ReadFile(result.Outputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "alpha beta" {
+		t.Errorf("Expected merged content, got %q", data)
+	}
+	if len(result.TaskIDs) != 2 || result.Resumed != 0 {
+		t.Errorf("Expected 2 fresh steps, got %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(w.path("out"), stateDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected state removed after completion, got %v", err)
+	}
+
+	// Two inputs, then the two converted files
+	server.AssertRequestCount(t, "POST", "/v1/biz/initiate-upload", 4)
+	server.AssertRequestCount(t, "POST", "/v1/biz/do", 2)
+}
+
+func TestRunner_Encryption(t *testing.T) {
+	server := d3test.NewServer(t)
+	enc, err := d3.NewAESGCM(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := newWorkflow(t, Step{Action: "convert", Parameters: map[string]interface{}{"convert_to": "md"}})
+
+	client := server.Client(t, d3.WithConfig(d3.Config{Encryption: enc}))
+	result, err := NewRunner(client).Run(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Outputs) != 2 {
+		t.Fatalf("Expected 2 outputs, got %v", result.Outputs)
+	}
+	data, err := os.ReadFile(result.Outputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "alpha " {
+		t.Errorf("Expected the output to be decrypted, got %q", data)
+	}
+}
+
+func TestRunner_Resume(t *testing.T) {
+	server := d3test.NewServer(t)
+	client := server.Client(t)
+	w := newWorkflow(t,
+		Step{Action: "convert", Parameters: map[string]interface{}{"convert_to": "md"}},
+		Step{Action: "zip"},
+	)
+
+	// The zip submission fails after the convert step completed
+	calls := 0
+	server.Handle("POST", "/v1/biz/do", func(rw http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			http.Error(rw, `{"success": false, "message": "unavailable"}`, http.StatusBadRequest)
+			return
+		}
+		server.Emulator.ServeHTTP(rw, r)
+	})
+	_, err := NewRunner(client).Run(context.Background(), w)
+	if err == nil || !strings.Contains(err.Error(), "step 2 (zip)") {
+		t.Fatalf("Expected step 2 error, got %v", err)
+	}
+
+	server.Handle("POST", "/v1/biz/do", nil)
+	server.Reset()
+	result, err := NewRunner(client).Run(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Resumed != 1 {
+		t.Errorf("Expected 1 resumed step, got %d", result.Resumed)
+	}
+	if len(result.Outputs) != 1 || filepath.Base(result.Outputs[0]) != "archive.zip" {
+		t.Errorf("Expected archive.zip, got %v", result.Outputs)
+	}
+	// Only the zip ran again, reusing the converted files' uploads
+	server.AssertRequestCount(t, "POST", "/v1/biz/do", 1)
+	server.AssertNotRequested(t, "POST", "/v1/biz/initiate-upload")
+}
+
+func TestRunner_FailedOperation(t *testing.T) {
+	server := d3test.NewServerWithOptions(t, d3test.EmulatorOptions{FailPattern: "b.txt"})
+	w := newWorkflow(t, Step{Action: "convert", Parameters: map[string]interface{}{"convert_to": "md"}})
+
+	_, err := NewRunner(server.Client(t)).Run(context.Background(), w)
+	if err == nil || !strings.Contains(err.Error(), "operation partially_completed") {
+		t.Fatalf("Expected partially_completed error, got %v", err)
+	}
+
+	// The failed step is submitted again, its inputs are not uploaded again
+	server.Reset()
+	NewRunner(server.Client(t)).Run(context.Background(), w)
+	server.AssertRequestCount(t, "POST", "/v1/biz/do", 1)
+	server.AssertNotRequested(t, "POST", "/v1/biz/initiate-upload")
+}
+
+func TestRunner_ChangedWorkflowStartsOver(t *testing.T) {
+	server := d3test.NewServer(t)
+	w := newWorkflow(t,
+		Step{Action: "convert", Parameters: map[string]interface{}{"convert_to": "md"}},
+		Step{Action: "zip"},
+	)
+	server.Fail("POST", "/v1/biz/do", http.StatusBadRequest, "unavailable")
+	if _, err := NewRunner(server.Client(t)).Run(context.Background(), w); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	server.Handle("POST", "/v1/biz/do", nil)
+	w.Steps[0].Parameters["convert_to"] = "txt"
+	result, err := NewRunner(server.Client(t)).Run(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Resumed != 0 {
+		t.Errorf("Expected a fresh run, got %d resumed steps", result.Resumed)
+	}
+}
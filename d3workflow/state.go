@@ -0,0 +1,148 @@
+package d3workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+)
+
+// stateDir is the directory inside the output directory holding a run's
+// progress and intermediate results
+const stateDir = ".d3workflow"
+
+// state is the progress of a workflow run, saved as state.json
+type state struct {
+	// Fingerprint identifies the inputs and steps the progress applies to
+	Fingerprint string                 `json:"fingerprint"`
+	Uploads     map[string]uploadState `json:"uploads"`
+	Steps       []stepState            `json:"steps"`
+
+	dir string
+}
+
+// uploadState is a file uploaded by an earlier run, reused while the file
+// is unchanged
+type uploadState struct {
+	FileKey d3.FileKey `json:"file_key"`
+	Size    int64      `json:"size"`
+	ModTime time.Time  `json:"mod_time"`
+}
+
+// stepState is the progress of one step: submitted once TaskID is set,
+// done once its results are downloaded to Outputs
+type stepState struct {
+	TaskID  d3.MainTaskID `json:"task_id,omitempty"`
+	Outputs []string      `json:"outputs,omitempty"`
+	Done    bool          `json:"done,omitempty"`
+}
+
+// done reports whether the step finished and its results are still on disk
+func (s stepState) done() bool {
+	if !s.Done {
+		return false
+	}
+	for _, output := range s.Outputs {
+		if _, err := os.Stat(output); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// loadState reads the progress saved in dir, starting afresh if there is
+// none or it was saved for different inputs or steps
+func loadState(dir string, inputs []string, steps []Step) (*state, error) {
+	fingerprint, err := fingerprint(inputs, steps)
+	if err != nil {
+		return nil, err
+	}
+	fresh := &state{
+		Fingerprint: fingerprint,
+		Uploads:     map[string]uploadState{},
+		Steps:       make([]stepState, len(steps)),
+		dir:         dir,
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "state.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow state: %w", err)
+	}
+	var saved state
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Fingerprint != fingerprint || len(saved.Steps) != len(steps) {
+		return fresh, nil
+	}
+	if saved.Uploads == nil {
+		saved.Uploads = map[string]uploadState{}
+	}
+	// A step whose results are gone is run again, and so are the steps after
+	for i := range saved.Steps {
+		if !saved.Steps[i].done() {
+			for j := i; j < len(saved.Steps); j++ {
+				if saved.Steps[j].Done {
+					saved.Steps[j] = stepState{}
+				}
+			}
+			break
+		}
+	}
+	saved.dir = dir
+	return &saved, nil
+}
+
+// save writes the progress atomically
+func (s *state) save() error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to save workflow state: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save workflow state: %w", err)
+	}
+	tmp := filepath.Join(s.dir, "state.json.tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save workflow state: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, "state.json")); err != nil {
+		return fmt.Errorf("failed to save workflow state: %w", err)
+	}
+	return nil
+}
+
+// fingerprint hashes the input files, with their sizes and modification
+// times, and the steps
+func fingerprint(inputs []string, steps []Step) (string, error) {
+	type input struct {
+		Path    string    `json:"path"`
+		Size    int64     `json:"size"`
+		ModTime time.Time `json:"mod_time"`
+	}
+	files := make([]input, len(inputs))
+	for i, path := range inputs {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		files[i] = input{Path: path, Size: info.Size(), ModTime: info.ModTime()}
+	}
+
+	data, err := json.Marshal(struct {
+		Inputs []input `json:"inputs"`
+		Steps  []Step  `json:"steps"`
+	}{files, steps})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,196 @@
+// Package d3workflow runs declarative D3 pipelines. A workflow file names
+// the input files, the operations to apply to them in order and where to
+// put the results:
+//
+//	name: invoices
+//	inputs:
+//	  - invoices/*.docx
+//	steps:
+//	  - action: convert
+//	    parameters:
+//	      convert_to: pdf
+//	  - action: merge
+//	  - action: lock
+//	    parameters:
+//	      password: ${INVOICE_PASSWORD}
+//	output: out
+//
+// Each step's results are downloaded and uploaded as the next step's
+// inputs; the last step's results are written to the output directory.
+// A Runner saves its progress next to the output, so running an
+// interrupted or failed workflow again resumes it instead of starting over.
+//
+//	workflow, err := d3workflow.Load("invoices.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	result, err := d3workflow.NewRunner(client).Run(ctx, workflow)
+package d3workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Workflow is a pipeline of D3 operations read by Load
+type Workflow struct {
+	// Name identifies the workflow in logs and errors
+	Name string `json:"name" yaml:"name"`
+	// Inputs are file paths or glob patterns, relative to the workflow file
+	Inputs []string `json:"inputs" yaml:"inputs"`
+	// Steps are applied in order, each to the previous step's results
+	Steps []Step `json:"steps" yaml:"steps"`
+	// Output is the directory the last step's results are written to,
+	// relative to the workflow file
+	Output string `json:"output" yaml:"output"`
+	// PollInterval and PollTimeout override the client's status polling
+	// defaults, e.g. "5s" and "30m"
+	PollInterval string `json:"poll_interval" yaml:"poll_interval"`
+	PollTimeout  string `json:"poll_timeout" yaml:"poll_timeout"`
+
+	// dir resolves relative inputs and output
+	dir string
+}
+
+// Step is one operation of a Workflow
+type Step struct {
+	// Action is the operation to create, e.g. "convert" or "merge"
+	Action d3.Action `json:"action" yaml:"action"`
+	// Parameters are sent with the operation, e.g. convert_to for convert
+	Parameters map[string]interface{} `json:"parameters" yaml:"parameters"`
+	// Notes are attached to the operation
+	Notes map[string]string `json:"notes" yaml:"notes"`
+}
+
+// Load reads a workflow from a JSON (.json) or YAML (.yaml, .yml) file.
+// ${VAR} and $VAR references are expanded from the environment before
+// parsing ($$ yields a literal $), as in d3.LoadConfig.
+func Load(path string) (*Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+
+	data = []byte(os.Expand(string(data), func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return os.Getenv(name)
+	}))
+
+	var w Workflow
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&w)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&w)
+	default:
+		return nil, fmt.Errorf("unsupported workflow format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow %s: %w", path, err)
+	}
+
+	w.dir = filepath.Dir(path)
+	if err := w.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+	return &w, nil
+}
+
+// Validate checks that the workflow has inputs, an output and valid steps
+// and durations
+func (w *Workflow) Validate() error {
+	var errs []error
+	if len(w.Inputs) == 0 {
+		errs = append(errs, errors.New("inputs are required"))
+	}
+	if len(w.Steps) == 0 {
+		errs = append(errs, errors.New("at least one step is required"))
+	}
+	for i, step := range w.Steps {
+		if strings.TrimSpace(string(step.Action)) == "" {
+			errs = append(errs, fmt.Errorf("step %d: action is required", i+1))
+		}
+	}
+	if w.Output == "" {
+		errs = append(errs, errors.New("output is required"))
+	}
+	if _, _, err := w.pollDurations(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// pollDurations parses PollInterval and PollTimeout, zero if unset
+func (w *Workflow) pollDurations() (interval, timeout time.Duration, err error) {
+	if interval, err = parseDuration("poll_interval", w.PollInterval); err != nil {
+		return 0, 0, err
+	}
+	if timeout, err = parseDuration("poll_timeout", w.PollTimeout); err != nil {
+		return 0, 0, err
+	}
+	return interval, timeout, nil
+}
+
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return d, nil
+}
+
+// path resolves p against the workflow file's directory
+func (w *Workflow) path(p string) string {
+	if filepath.IsAbs(p) || w.dir == "" {
+		return p
+	}
+	return filepath.Join(w.dir, p)
+}
+
+// inputFiles expands the input patterns into a sorted list of files,
+// failing if a pattern matches nothing
+func (w *Workflow) inputFiles() ([]string, error) {
+	seen := map[string]bool{}
+	var files []string
+	for _, pattern := range w.Inputs {
+		matches, err := filepath.Glob(w.path(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid input pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("input %q matches no files", pattern)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if info.IsDir() || seen[match] {
+				continue
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,116 @@
+package d3workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad_YAML(t *testing.T) {
+	t.Setenv("WORKFLOW_PASSWORD", "s3cret")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "invoices.yaml")
+	writeFile(t, path, `name: invoices
+inputs:
+  - in/*.docx
+steps:
+  - action: convert
+    parameters:
+      convert_to: pdf
+  - action: lock
+    parameters:
+      password: ${WORKFLOW_PASSWORD}
+    notes:
+      team: billing
+output: out
+poll_interval: 5s
+`)
+
+	w, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if w.Name != "invoices" || len(w.Steps) != 2 {
+		t.Fatalf("Expected 2 steps of invoices, got %+v", w)
+	}
+	if got := w.Steps[1].Parameters["password"]; got != "s3cret" {
+		t.Errorf("Expected expanded password, got %v", got)
+	}
+	if got := w.Steps[1].Notes["team"]; got != "billing" {
+		t.Errorf("Expected note team=billing, got %q", got)
+	}
+	if got := w.path(w.Output); got != filepath.Join(dir, "out") {
+		t.Errorf("Expected output relative to the workflow file, got %s", got)
+	}
+}
+
+func TestLoad_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zip.json")
+	writeFile(t, path, `{"inputs": ["a.txt"], "steps": [{"action": "zip"}], "output": "/tmp/out"}`)
+
+	w, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if w.Steps[0].Action != "zip" || w.path(w.Output) != "/tmp/out" {
+		t.Errorf("Unexpected workflow %+v", w)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"unknown field", "w.yaml", "inputs: [a]\nsteps: [{action: zip}]\noutput: out\nretries: 3\n", "field retries not found"},
+		{"missing parts", "w.yaml", "name: empty\n", "inputs are required"},
+		{"missing action", "w.json", `{"inputs": ["a"], "steps": [{}], "output": "out"}`, "step 1: action is required"},
+		{"bad duration", "w.yaml", "inputs: [a]\nsteps: [{action: zip}]\noutput: out\npoll_timeout: soon\n", "invalid poll_timeout"},
+		{"format", "w.toml", "", "unsupported workflow format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			writeFile(t, path, tt.content)
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWorkflow_InputFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "in", "b.docx"), "b")
+	writeFile(t, filepath.Join(dir, "in", "a.docx"), "a")
+	writeFile(t, filepath.Join(dir, "in", "c.txt"), "c")
+	w := &Workflow{Inputs: []string{"in/*.docx", "in/a.docx"}, dir: dir}
+
+	files, err := w.inputFiles()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []string{filepath.Join(dir, "in", "a.docx"), filepath.Join(dir, "in", "b.docx")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, files)
+	}
+
+	w.Inputs = []string{"in/*.pdf"}
+	if _, err := w.inputFiles(); err == nil || !strings.Contains(err.Error(), "matches no files") {
+		t.Errorf("Expected matches no files error, got %v", err)
+	}
+}
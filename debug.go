@@ -0,0 +1,47 @@
+package d3
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// debugBodyLimit is the number of body bytes included in debug logs
+const debugBodyLimit = 1024
+
+// newDebugLogger returns logger, or a stderr logger when nil
+func newDebugLogger(logger *log.Logger) *log.Logger {
+	if logger != nil {
+		return logger
+	}
+	return log.New(os.Stderr, "[d3] ", log.LstdFlags)
+}
+
+// debugHook logs each API call with credentials, signatures and passwords
+// redacted
+func debugHook(logger *log.Logger) AfterResponseHook {
+	return func(resp *ResponseInfo) {
+		req := resp.Request
+		if resp.Err != nil {
+			logger.Printf("%s %s attempt=%d error=%q latency=%v headers=%v body=%s",
+				req.Method, req.Path, req.Attempt, resp.Err.Error(), resp.Latency,
+				redactHeader(req.Header), redactBody(req.Body, debugBodyLimit))
+			return
+		}
+		logger.Printf("%s %s attempt=%d status=%d latency=%v headers=%v body=%s response=%s",
+			req.Method, req.Path, req.Attempt, resp.StatusCode, resp.Latency,
+			redactHeader(req.Header), redactBody(req.Body, debugBodyLimit), redactBody(resp.Body, debugBodyLimit))
+	}
+}
+
+// debugPart logs a presigned part upload with its signature redacted
+func (c *Dragdropdo) debugPart(url string, partNumber int, statusCode int, latency time.Duration, err error) {
+	if c.debugLogger == nil {
+		return
+	}
+	if err != nil {
+		c.debugLogger.Printf("PUT %s part=%d error=%q latency=%v", redactURL(url), partNumber, err.Error(), latency)
+		return
+	}
+	c.debugLogger.Printf("PUT %s part=%d status=%d latency=%v", redactURL(url), partNumber, statusCode, latency)
+}
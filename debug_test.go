@@ -0,0 +1,56 @@
+package d3
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_DebugLogging_Redacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"main_task_id": "task-123",
+				"link":         "https://bucket.s3.amazonaws.com/obj?X-Amz-Credential=AKIA&X-Amz-Signature=deadbeef",
+			},
+		})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client, err := NewDragdropdo(Config{
+		APIKey:      "secret-api-key",
+		BaseURL:     server.URL,
+		Debug:       true,
+		DebugLogger: log.New(&buf, "", 0),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.LockPdf([]FileKey{"file-key-123"}, "hunter2", nil); err != nil {
+		t.Fatalf("Failed to create operation: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "POST /v1/biz/do") || !strings.Contains(output, "status=200") {
+		t.Errorf("Expected method, path and status in debug log, got: %s", output)
+	}
+	for _, secret := range []string{"secret-api-key", "hunter2", "deadbeef", "AKIA"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Debug log leaked %q: %s", secret, output)
+		}
+	}
+}
+
+func TestRedactBody_Truncates(t *testing.T) {
+	out := redactBody([]byte(strings.Repeat("x", 100)), 10)
+	if out != "xxxxxxxxxx...(truncated)" {
+		t.Errorf("Unexpected truncated body: %s", out)
+	}
+}
@@ -0,0 +1,57 @@
+package d3
+
+import "time"
+
+// Defaults used in place of zero values. A negative duration or size is
+// treated like zero, so a zero-value Config or options struct is always
+// usable; nil maps and callbacks are simply skipped.
+const (
+	// DefaultTimeout bounds each API request attempt (Config.Timeout)
+	DefaultTimeout = 30 * time.Second
+	// DefaultDialTimeout bounds establishing a connection
+	// (Config.DialTimeout)
+	DefaultDialTimeout = 30 * time.Second
+	// DefaultFailoverCooldown is how long a failed endpoint is skipped
+	// (Config.FailoverCooldown)
+	DefaultFailoverCooldown = 30 * time.Second
+	// DefaultChunkSize is the target part size of uploads (Config.ChunkSize)
+	DefaultChunkSize int64 = 5 * 1024 * 1024
+	// DefaultUploadConcurrency is the number of parts uploaded in parallel
+	// (Config.UploadConcurrency)
+	DefaultUploadConcurrency = 1
+	// DefaultPollInterval is the wait between status checks
+	// (PollStatusOptions.Interval)
+	DefaultPollInterval = 2 * time.Second
+	// DefaultPollTimeout is how long PollStatus waits for an operation to
+	// finish (PollStatusOptions.Timeout)
+	DefaultPollTimeout = 5 * time.Minute
+	// DefaultManagerConcurrency is the number of operations a Manager runs
+	// at once (ManagerOptions.MaxConcurrent)
+	DefaultManagerConcurrency = 4
+	// DefaultManagerAttempts is the number of times a Manager tries an
+	// operation that fails transiently (ManagerOptions.MaxAttempts)
+	DefaultManagerAttempts = 3
+	// DefaultUploadCacheTTL is how long a MemoryUploadCache keeps the file
+	// key of uploaded content (NewMemoryUploadCache)
+	DefaultUploadCacheTTL = time.Hour
+	// DefaultCompressionValue is the compression level used by Compress when
+	// none is given
+	DefaultCompressionValue = "recommended"
+)
+
+// durationOr returns d, or def if d is not positive
+func durationOr(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+// percentage returns done as a whole percentage of total. An empty total
+// counts as complete rather than dividing by zero.
+func percentage(done, total int64) int {
+	if total <= 0 {
+		return 100
+	}
+	return int(done * 100 / total)
+}
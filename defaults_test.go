@@ -0,0 +1,102 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_UploadFile_EmptyFileProgress(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(tmpFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	partServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag-part-1"`)
+	}))
+	defer partServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := map[string]interface{}{"file_key": "file-key-123"}
+		if r.URL.Path == "/v1/biz/initiate-upload" {
+			data["upload_id"] = "upload-id-456"
+			data["presigned_urls"] = []string{partServer.URL + "/part1"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer apiServer.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: apiServer.URL, Timeout: -1})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.OnError(nil)
+	client.OnBeforeRequest(nil)
+	client.OnAfterResponse(nil)
+
+	var last UploadProgress
+	_, err = client.UploadFile(UploadFileOptions{
+		File:       tmpFile,
+		FileName:   "empty.txt",
+		OnProgress: func(p UploadProgress) { last = p },
+	})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if last.Percentage != 100 || last.TotalBytes != 0 {
+		t.Errorf("Expected 100%% of 0 bytes, got %+v", last)
+	}
+}
+
+func TestClient_OperationOmitsEmptyMaps(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.CreateOperation(OperationOptions{
+		Action:     "zip",
+		FileKeys:   []FileKey{"file-key-123"},
+		Parameters: map[string]interface{}{},
+		Notes:      map[string]string{},
+	})
+	if err != nil {
+		t.Fatalf("CreateOperation failed: %v", err)
+	}
+	if _, ok := body["parameters"]; ok {
+		t.Errorf("Expected empty parameters to be omitted, got %v", body)
+	}
+	if _, ok := body["notes"]; ok {
+		t.Errorf("Expected empty notes to be omitted, got %v", body)
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	tests := []struct {
+		done, total int64
+		want        int
+	}{
+		{0, 0, 100},
+		{0, 10, 0},
+		{5, 10, 50},
+		{10, 10, 100},
+	}
+	for _, tt := range tests {
+		if got := percentage(tt.done, tt.total); got != tt.want {
+			t.Errorf("Expected percentage(%d, %d) = %d, got %d", tt.done, tt.total, tt.want, got)
+		}
+	}
+}
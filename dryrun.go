@@ -0,0 +1,54 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// dryRunContextKey marks the context of calls made with a per-call DryRun
+// option
+type dryRunContextKey struct{}
+
+// DryRunRequest is an API request built, but not sent, in dry-run mode.
+// Credentials, passwords and URL signatures are redacted.
+type DryRunRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	// Body is the JSON request body, nil if there is none
+	Body json.RawMessage
+}
+
+// withDryRun returns ctx marked so that the requests made with it are not
+// sent
+func withDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, true)
+}
+
+// isDryRun reports whether requests made with ctx are not to be sent
+func (c *Dragdropdo) isDryRun(ctx context.Context) bool {
+	return c.dryRun || ctx.Value(dryRunContextKey{}) != nil
+}
+
+// dryRunError returns the D3DryRunError describing the request with header
+// and body
+func (r *apiRequest) dryRunError(header http.Header, body []byte) *D3DryRunError {
+	request := DryRunRequest{
+		Method: r.method,
+		URL:    redactURL(r.client.baseURL + r.target),
+		Header: redactHeader(header),
+	}
+	if body != nil {
+		request.Body = json.RawMessage(redactBody(body, 0))
+	}
+	return &D3DryRunError{
+		D3ClientError: D3ClientError{
+			Message:  fmt.Sprintf("dry run: %s %s not sent", r.method, r.path),
+			Err:      ErrDryRun,
+			Endpoint: r.method + " " + r.path,
+		},
+		Request: request,
+	}
+}
@@ -0,0 +1,123 @@
+package d3
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newUnreachableServer fails the test on any request
+func newUnreachableServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request in dry-run mode, got %s %s", r.Method, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_DryRun(t *testing.T) {
+	server := newUnreachableServer(t)
+	tmpFile := filepath.Join(t.TempDir(), "large.pdf")
+	if err := os.WriteFile(tmpFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.Truncate(tmpFile, 12*1024*1024); err != nil {
+		t.Fatalf("Failed to size test file: %v", err)
+	}
+
+	client, err := NewDragdropdo(Config{
+		APIKey:            "test-key",
+		BaseURL:           server.URL,
+		DryRun:            true,
+		ChunkSize:         5 * 1024 * 1024,
+		CheckPlanFileSize: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	var reported int
+	client.OnError(func(info *ErrorInfo) { reported++ })
+
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "large.pdf"})
+	var dryRun *D3DryRunError
+	if !errors.As(err, &dryRun) || !errors.Is(err, ErrDryRun) {
+		t.Fatalf("Expected a *D3DryRunError, got %v", err)
+	}
+	if dryRun.Request.Method != http.MethodPost || dryRun.Request.URL != server.URL+"/v1/biz/initiate-upload" {
+		t.Errorf("Expected the initiate-upload request, got %s %s", dryRun.Request.Method, dryRun.Request.URL)
+	}
+	if got := dryRun.Request.Header.Get("Authorization"); got != redacted {
+		t.Errorf("Expected the API key to be redacted, got %q", got)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(dryRun.Request.Body, &body)
+	if body["parts"] != float64(3) || body["mime_type"] != "application/pdf" || body["size"] != float64(12*1024*1024) {
+		t.Errorf("Expected the computed parts, MIME type and size, got %v", body)
+	}
+
+	_, err = client.LockPdf([]FileKey{"file-key"}, "secret-password", nil)
+	if !errors.As(err, &dryRun) {
+		t.Fatalf("Expected a *D3DryRunError, got %v", err)
+	}
+	body = nil
+	json.Unmarshal(dryRun.Request.Body, &body)
+	params, _ := body["parameters"].(map[string]interface{})
+	if body["action"] != "lock" || params["password"] != redacted {
+		t.Errorf("Expected the lock request with the password redacted, got %v", body)
+	}
+
+	if _, err := client.UploadFile(UploadFileOptions{File: "missing.pdf", FileName: "missing.pdf"}); !IsD3ValidationError(err) {
+		t.Errorf("Expected inputs to be validated in dry-run mode, got %v", err)
+	}
+	if reported != 0 {
+		t.Errorf("Expected dry runs not to be reported to error hooks, got %d", reported)
+	}
+}
+
+func TestClient_DryRunPerCall(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+	tmpFile := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(tmpFile, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, PreflightValidation: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "doc.txt", DryRun: true})
+	if !IsD3DryRunError(err) {
+		t.Errorf("Expected a dry-run upload, got %v", err)
+	}
+	_, err = client.CreateOperation(OperationOptions{Action: "convert", FileKeys: []FileKey{"file-key"}, DryRun: true})
+	if !IsD3DryRunError(err) {
+		t.Errorf("Expected a dry-run operation, got %v", err)
+	}
+	if hits != 0 {
+		t.Errorf("Expected no requests for dry-run calls, got %d", hits)
+	}
+
+	if _, err := client.CreateOperation(OperationOptions{Action: "convert", FileKeys: []FileKey{"file-key"}}); err != nil || hits != 1 {
+		t.Errorf("Expected other calls to be sent, got %v after %d requests", err, hits)
+	}
+
+	clone, err := client.Clone(WithDryRun())
+	if err != nil {
+		t.Fatalf("Failed to clone client: %v", err)
+	}
+	if _, err := clone.GetUsage(); !IsD3DryRunError(err) || hits != 1 {
+		t.Errorf("Expected a dry-run clone, got %v after %d requests", err, hits)
+	}
+}
@@ -0,0 +1,194 @@
+package d3
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// EncryptionProvider encrypts files before UploadFile sends them and
+// decrypts outputs as Result.Open and Result.Download read them, so
+// documents never reach the processor's storage in plaintext. Set it with
+// Config.Encryption. Implementations must be safe for concurrent use.
+type EncryptionProvider interface {
+	// Encrypt writes the encryption of src to dst
+	Encrypt(dst io.Writer, src io.Reader) error
+	// Decrypt writes the plaintext of the encrypted src to dst, failing
+	// with ErrDecryptionFailed if src was not encrypted with the same key
+	// or was modified or truncated
+	Decrypt(dst io.Writer, src io.Reader) error
+}
+
+const (
+	// aesGCMMagic starts content encrypted by AESGCM
+	aesGCMMagic = "D3GCM\x01"
+	// aesGCMSegment is the size of the plaintext segments sealed one by one
+	aesGCMSegment = 64 * 1024
+	// aesGCMPrefix is the size of the random nonce prefix
+	aesGCMPrefix = 7
+)
+
+// AESGCM is an EncryptionProvider using AES-GCM with a key supplied by the
+// caller. Content is sealed in 64 KiB segments so files of any size stream
+// through a fixed buffer; each segment's nonce is a random per-file prefix,
+// the segment's index and a flag marking the last segment, so segments
+// cannot be reordered, dropped or cut off without Decrypt noticing.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM using key, which must be 16, 24 or 32 bytes
+// for AES-128, AES-192 or AES-256
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, NewD3ValidationError(fmt.Sprintf("invalid encryption key: %v", err), nil)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt implements EncryptionProvider
+func (a *AESGCM) Encrypt(dst io.Writer, src io.Reader) error {
+	prefix := make([]byte, aesGCMPrefix)
+	if _, err := rand.Read(prefix); err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := io.WriteString(dst, aesGCMMagic); err != nil {
+		return err
+	}
+	if _, err := dst.Write(prefix); err != nil {
+		return err
+	}
+
+	in := bufio.NewReaderSize(src, aesGCMSegment)
+	plain := make([]byte, aesGCMSegment)
+	sealed := make([]byte, 0, aesGCMSegment+a.aead.Overhead())
+	for index := uint32(0); ; index++ {
+		n, err := io.ReadFull(in, plain)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return fmt.Errorf("failed to encrypt: %w", err)
+		}
+		if !last {
+			// A full segment is the last one if nothing follows it
+			if _, err := in.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+		if !last && index == ^uint32(0) {
+			return errors.New("failed to encrypt: content too large")
+		}
+
+		sealed = a.aead.Seal(sealed[:0], aesGCMNonce(prefix, index, last), plain[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// Decrypt implements EncryptionProvider
+func (a *AESGCM) Decrypt(dst io.Writer, src io.Reader) error {
+	in := bufio.NewReaderSize(src, aesGCMSegment+a.aead.Overhead())
+	header := make([]byte, len(aesGCMMagic)+aesGCMPrefix)
+	if _, err := io.ReadFull(in, header); err != nil || !bytes.HasPrefix(header, []byte(aesGCMMagic)) {
+		return fmt.Errorf("%w: not encrypted by AESGCM", ErrDecryptionFailed)
+	}
+	prefix := header[len(aesGCMMagic):]
+
+	sealed := make([]byte, aesGCMSegment+a.aead.Overhead())
+	plain := make([]byte, 0, aesGCMSegment)
+	for index := uint32(0); ; index++ {
+		n, err := io.ReadFull(in, sealed)
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return fmt.Errorf("failed to decrypt: %w", err)
+		}
+		if !last {
+			if _, err := in.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+
+		plain, err = a.aead.Open(plain[:0], aesGCMNonce(prefix, index, last), sealed[:n], nil)
+		if err != nil {
+			return fmt.Errorf("%w: segment %d", ErrDecryptionFailed, index)
+		}
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// aesGCMNonce returns the nonce of segment index: the prefix, the index and
+// whether it is the last segment
+func aesGCMNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 0, 12)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, index)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptFile writes the encryption of the file at path to a temporary
+// file and returns its path; the caller removes it
+func (c *Dragdropdo) encryptFile(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrFileNotFound, err)
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp("", "d3-encrypted-*"+filepath.Ext(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt %s: %w", path, err)
+	}
+	if err := c.encryption.Encrypt(tmp, src); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to encrypt %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to encrypt %s: %w", path, err)
+	}
+	return tmp.Name(), nil
+}
+
+// decryptingBody streams the plaintext of the encrypted body
+func decryptingBody(encryption EncryptionProvider, body io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encryption.Decrypt(pw, body))
+	}()
+	return &decryptedBody{PipeReader: pr, body: body}
+}
+
+type decryptedBody struct {
+	*io.PipeReader
+	body io.Closer
+}
+
+func (d *decryptedBody) Close() error {
+	d.PipeReader.Close()
+	return d.body.Close()
+}
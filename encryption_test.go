@@ -0,0 +1,140 @@
+package d3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAESGCM_RoundTrip(t *testing.T) {
+	enc, err := NewAESGCM(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewAESGCM failed: %v", err)
+	}
+	for _, size := range []int{0, 1, aesGCMSegment - 1, aesGCMSegment, aesGCMSegment + 1, 3*aesGCMSegment + 100} {
+		plain := make([]byte, size)
+		for i := range plain {
+			plain[i] = byte(i * 31)
+		}
+		var sealed, opened bytes.Buffer
+		if err := enc.Encrypt(&sealed, bytes.NewReader(plain)); err != nil {
+			t.Fatalf("Encrypt of %d bytes failed: %v", size, err)
+		}
+		if size > 16 && bytes.Contains(sealed.Bytes(), plain) {
+			t.Errorf("Expected %d bytes to be encrypted", size)
+		}
+		if err := enc.Decrypt(&opened, bytes.NewReader(sealed.Bytes())); err != nil {
+			t.Fatalf("Decrypt of %d bytes failed: %v", size, err)
+		}
+		if !bytes.Equal(opened.Bytes(), plain) {
+			t.Errorf("Expected %d bytes back, got %d", size, opened.Len())
+		}
+	}
+}
+
+func TestAESGCM_RejectsTampering(t *testing.T) {
+	enc, _ := NewAESGCM(bytes.Repeat([]byte{7}, 16))
+	other, _ := NewAESGCM(bytes.Repeat([]byte{8}, 16))
+	var sealed bytes.Buffer
+	enc.Encrypt(&sealed, bytes.NewReader(make([]byte, 2*aesGCMSegment+10)))
+
+	header := len(aesGCMMagic) + aesGCMPrefix
+	segment := aesGCMSegment + 16
+	flipped := append([]byte(nil), sealed.Bytes()...)
+	flipped[header+5] ^= 1
+	cases := map[string]struct {
+		enc  *AESGCM
+		data []byte
+	}{
+		"wrong key":                {other, sealed.Bytes()},
+		"modified":                 {enc, flipped},
+		"truncated at a segment":   {enc, sealed.Bytes()[:header+2*segment]},
+		"truncated within segment": {enc, sealed.Bytes()[:header+segment+100]},
+		"not encrypted":            {enc, []byte("plain text")},
+	}
+	for name, tc := range cases {
+		if err := tc.enc.Decrypt(io.Discard, bytes.NewReader(tc.data)); !errors.Is(err, ErrDecryptionFailed) {
+			t.Errorf("%s: expected ErrDecryptionFailed, got %v", name, err)
+		}
+	}
+
+	var validationErr *D3ValidationError
+	if _, err := NewAESGCM([]byte("short")); !errors.As(err, &validationErr) {
+		t.Errorf("Expected D3ValidationError for a bad key, got %v", err)
+	}
+}
+
+func TestClient_Encryption(t *testing.T) {
+	enc, _ := NewAESGCM(bytes.Repeat([]byte{1}, 32))
+	plain := []byte("confidential contract")
+
+	var stored []byte
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-1",
+					"upload_id":      "upload-id",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case "/part/1":
+			stored, _ = io.ReadAll(r.Body)
+			w.Header().Set("ETag", `"etag"`)
+		case "/v1/biz/complete-upload":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`))
+		case "/download/contract.pdf":
+			w.Write(stored)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, Encryption: enc})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "contract.pdf")
+	os.WriteFile(file, plain, 0644)
+	if _, err := client.UploadFile(UploadFileOptions{File: file, FileName: "contract.pdf"}); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if len(stored) == 0 || bytes.Contains(stored, plain) {
+		t.Fatalf("Expected storage to receive ciphertext, got %q", stored)
+	}
+
+	results, err := client.Results(context.Background(), &StatusResponse{FilesData: []FileTaskStatus{{
+		FileKey:      "file-key-1",
+		Status:       StatusCompleted,
+		DownloadLink: server.URL + "/download/contract.pdf",
+	}}})
+	if err != nil {
+		t.Fatalf("Results failed: %v", err)
+	}
+	dest, err := results[0].Download(t.TempDir())
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, plain) {
+		t.Errorf("Expected the download to be decrypted, got %q", got)
+	}
+	if results[0].Size != -1 {
+		t.Errorf("Expected an unknown size for an encrypted output, got %d", results[0].Size)
+	}
+
+	// Outputs not encrypted with the key fail rather than pass through
+	stored = []byte("plain output")
+	if _, err := results[0].Download(t.TempDir()); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("Expected ErrDecryptionFailed, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package d3
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// endpointPool tracks the health of the API base URLs. Requests stick to the
+// current endpoint until it fails, then move to the next healthy one.
+type endpointPool struct {
+	urls     []string
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	current   int
+	downUntil []time.Time
+}
+
+func newEndpointPool(urls []string, cooldown time.Duration) *endpointPool {
+	cooldown = durationOr(cooldown, DefaultFailoverCooldown)
+	return &endpointPool{
+		urls:      urls,
+		cooldown:  cooldown,
+		downUntil: make([]time.Time, len(urls)),
+	}
+}
+
+// pick returns the endpoint to use next
+func (p *endpointPool) pick() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current, p.urls[p.current]
+}
+
+// markFailed marks endpoint i as down for the cooldown and moves to the next
+// healthy endpoint. It reports whether a different endpoint is available.
+func (p *endpointPool) markFailed(i int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.downUntil[i] = now.Add(p.cooldown)
+	if p.current != i {
+		// Another request already failed over
+		return true
+	}
+	for n := 1; n < len(p.urls); n++ {
+		next := (i + n) % len(p.urls)
+		if now.After(p.downUntil[next]) {
+			p.current = next
+			return true
+		}
+	}
+	return false
+}
+
+// markHealthy clears the down state of endpoint i
+func (p *endpointPool) markHealthy(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil[i] = time.Time{}
+}
+
+// shouldFailover reports whether the outcome of an attempt sent to an
+// endpoint, a transport error or a 502, 503 or 504, indicates an endpoint
+// outage rather than a problem with the request itself
+func shouldFailover(resp *apiResponse, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package d3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_FailoverBaseURLs(t *testing.T) {
+	primaryCalls := 0
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	secondaryCalls := 0
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+	}))
+	defer secondary.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:           "test-key",
+		BaseURL:          primary.URL,
+		FallbackBaseURLs: []string{secondary.URL},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		status, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+		if err != nil {
+			t.Fatalf("Failed to get status: %v", err)
+		}
+		if status.OperationStatus != "completed" {
+			t.Errorf("Expected completed status from fallback, got '%s'", status.OperationStatus)
+		}
+	}
+
+	// The client sticks to the working endpoint after failing over once
+	if primaryCalls != 1 || secondaryCalls != 3 {
+		t.Errorf("Expected 1 primary and 3 secondary calls, got %d and %d", primaryCalls, secondaryCalls)
+	}
+}
+
+func TestEndpointPool_AllDown(t *testing.T) {
+	pool := newEndpointPool([]string{"a", "b"}, 0)
+	if !pool.markFailed(0) {
+		t.Error("Expected failover to b")
+	}
+	if i, _ := pool.pick(); i != 1 {
+		t.Errorf("Expected current endpoint 1, got %d", i)
+	}
+	if pool.markFailed(1) {
+		t.Error("Expected no healthy endpoint left")
+	}
+}
+
+func TestClient_FailoverIgnoresHookErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:           "test-key",
+		BaseURL:          server.URL,
+		FallbackBaseURLs: []string{server.URL + "/fallback"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	rejected := errors.New("rejected by policy")
+	hooked := 0
+	client.OnBeforeRequest(func(*RequestInfo) error {
+		hooked++
+		return rejected
+	})
+
+	if _, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"}); !errors.Is(err, rejected) {
+		t.Fatalf("Expected the hook's error, got %v", err)
+	}
+	if hooked != 1 || calls != 0 {
+		t.Errorf("Expected one rejected attempt and no request, got %d hooks and %d requests", hooked, calls)
+	}
+	if i, _ := client.endpoints.pick(); i != 0 || !client.endpoints.downUntil[0].IsZero() {
+		t.Errorf("Expected the primary endpoint to stay healthy, got endpoint %d", i)
+	}
+}
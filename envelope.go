@@ -0,0 +1,206 @@
+package d3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// envelope is the JSON body of a successful API response: the result in
+// "data" and an optional informational "message"
+type envelope[T any] struct {
+	Data    T      `json:"data"`
+	Message string `json:"message,omitempty"`
+}
+
+// checkResponse returns the D3APIError reported by resp: a non-2xx status,
+// or a 2xx envelope with "success": false or an "error"
+func checkResponse(resp *apiResponse) error {
+	if !resp.IsSuccess() {
+		return newAPIErrorFromResponse(resp)
+	}
+	if apiErr := newAPIErrorFromEnvelope(resp); apiErr != nil {
+		return apiErr
+	}
+	return nil
+}
+
+// decodeEnvelope decodes resp into its envelope, or returns the D3APIError
+// it reports, see checkResponse. Keys of the result may follow the API's
+// snake_case or camelCase convention, e.g. file_key or fileKey. A body
+// without a "data" field is the result itself, and an empty body the zero
+// result.
+func decodeEnvelope[T any](resp *apiResponse) (*envelope[T], error) {
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	env := &envelope[T]{}
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		return env, nil
+	}
+
+	// A body that is not an object, e.g. an array, is the result itself
+	var raw map[string]json.RawMessage
+	json.Unmarshal(resp.Body, &raw)
+	data, ok := raw["data"]
+	if !ok {
+		data = resp.Body
+	}
+	if message, ok := raw["message"]; ok {
+		json.Unmarshal(message, &env.Message)
+	}
+
+	data, err := normalizeKeys(data, reflect.TypeOf(env.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	if err := json.Unmarshal(data, &env.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return env, nil
+}
+
+// fetch sends r and returns the data of its response, see decodeEnvelope
+func fetch[T any](r *apiRequest, method, route string) (*T, error) {
+	var env *envelope[T]
+	r.decode = func(resp *apiResponse) (err error) {
+		if env, err = decodeEnvelope[T](resp); err != nil {
+			return err
+		}
+		if r.client.onSchemaDrift != nil {
+			r.checkSchema(resp, (*envelope[T])(nil))
+		}
+		return nil
+	}
+	if _, err := r.Execute(method, route); err != nil {
+		return nil, err
+	}
+	return &env.Data, nil
+}
+
+// normalizeKeys renames the camelCase keys of the JSON document data that
+// match a snake_case field of t, e.g. fileKey to file_key. Keys of maps and
+// of types with their own decoding are left alone.
+func normalizeKeys(data []byte, t reflect.Type) ([]byte, error) {
+	if !hasCamelCaseFields(t, map[reflect.Type]bool{}) {
+		return data, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if !renameKeys(doc, t) {
+		return data, nil
+	}
+	return json.Marshal(doc)
+}
+
+// unmarshalerType is the type of json.Unmarshaler
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// hasCamelCaseFields reports whether values of t can hold a struct with a
+// snake_case field that normalizeKeys renames keys to
+func hasCamelCaseFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || seen[t] || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, f := range schemaFields(t) {
+			if strings.Contains(f.name, "_") || hasCamelCaseFields(f.typ, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasCamelCaseFields(t.Elem(), seen)
+	}
+	return false
+}
+
+// renameKeys renames the keys of doc in place as described by
+// normalizeKeys and reports whether any was renamed
+func renameKeys(doc interface{}, t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || doc == nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return false
+	}
+
+	renamed := false
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		fields := schemaFields(t)
+		for key, value := range object {
+			f, ok := fields[key]
+			if !ok {
+				f, ok = fields[strings.ToLower(key)]
+			}
+			if !ok {
+				snake := snakeCase(key)
+				if f, ok = fields[snake]; ok && !hasSchemaKey(object, snake) {
+					delete(object, key)
+					object[snake] = value
+					renamed = true
+				}
+			}
+			if ok && renameKeys(value, f.typ) {
+				renamed = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := doc.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if renameKeys(item, t.Elem()) {
+				renamed = true
+			}
+		}
+	case reflect.Map:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range object {
+			if renameKeys(item, t.Elem()) {
+				renamed = true
+			}
+		}
+	}
+	return renamed
+}
+
+// snakeCase converts a camelCase key to snake_case, e.g. presignedUrls to
+// presigned_urls
+func snakeCase(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Split before an upper-case letter that starts a word, keeping
+			// acronyms such as "ID" together
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
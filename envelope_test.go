@@ -0,0 +1,141 @@
+package d3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodeEnvelope(t *testing.T) {
+	resp := &apiResponse{
+		StatusCode: 200,
+		Body:       []byte(`{"data": {"file_key": "file-key-1", "file_name": "a.pdf"}, "message": "ok"}`),
+	}
+	env, err := decodeEnvelope[FileInfo](resp)
+	if err != nil {
+		t.Fatalf("Failed to decode envelope: %v", err)
+	}
+	if env.Data.FileKey != "file-key-1" || env.Data.FileName != "a.pdf" {
+		t.Errorf("Unexpected data: %+v", env.Data)
+	}
+	if env.Message != "ok" {
+		t.Errorf("Expected message ok, got %q", env.Message)
+	}
+}
+
+func TestDecodeEnvelope_CamelCase(t *testing.T) {
+	resp := &apiResponse{
+		StatusCode: 200,
+		Body:       []byte(`{"data": {"files": [{"fileKey": "file-key-1", "fileName": "a.pdf"}], "total": 1, "perPage": 20}}`),
+	}
+	env, err := decodeEnvelope[SearchFilesResponse](resp)
+	if err != nil {
+		t.Fatalf("Failed to decode envelope: %v", err)
+	}
+	if env.Data.PerPage != 20 || env.Data.Total != 1 {
+		t.Errorf("Expected total 1 and per_page 20, got %+v", env.Data)
+	}
+	if len(env.Data.Files) != 1 || env.Data.Files[0].FileKey != "file-key-1" || env.Data.Files[0].FileName != "a.pdf" {
+		t.Errorf("Expected camelCase file keys to decode, got %+v", env.Data.Files)
+	}
+}
+
+func TestDecodeEnvelope_BareBody(t *testing.T) {
+	resp := &apiResponse{
+		StatusCode: 200,
+		Body:       []byte(`{"folder_id": "folder-1", "name": "invoices"}`),
+	}
+	env, err := decodeEnvelope[Folder](resp)
+	if err != nil {
+		t.Fatalf("Failed to decode envelope: %v", err)
+	}
+	if env.Data.FolderID != "folder-1" || env.Data.Name != "invoices" {
+		t.Errorf("Expected a body without data to decode as the result, got %+v", env.Data)
+	}
+}
+
+func TestDecodeEnvelope_EmptyBody(t *testing.T) {
+	env, err := decodeEnvelope[Folder](&apiResponse{StatusCode: 204})
+	if err != nil {
+		t.Fatalf("Failed to decode envelope: %v", err)
+	}
+	if env.Data != (Folder{}) {
+		t.Errorf("Expected zero result for empty body, got %+v", env.Data)
+	}
+}
+
+func TestDecodeEnvelope_ErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *apiResponse
+	}{
+		{
+			name: "success false",
+			resp: &apiResponse{StatusCode: 200, Body: []byte(`{"success": false, "message": "quota exceeded"}`)},
+		},
+		{
+			name: "error status",
+			resp: &apiResponse{StatusCode: 400, Body: []byte(`{"message": "quota exceeded"}`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeEnvelope[Folder](tt.resp)
+			var apiErr *D3APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected D3APIError, got %v", err)
+			}
+			if apiErr.Message != "quota exceeded" {
+				t.Errorf("Expected message quota exceeded, got %q", apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvelope_InvalidBody(t *testing.T) {
+	_, err := decodeEnvelope[Folder](&apiResponse{StatusCode: 200, Body: []byte(`{"data": [1, 2]}`)})
+	if err == nil {
+		t.Fatal("Expected error for mismatched data")
+	}
+}
+
+func TestClient_CamelCaseResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"folderId": "folder-1", "name": "invoices", "parentId": "root"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	folder, err := client.CreateFolder(CreateFolderOptions{Name: "invoices"})
+	if err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	if folder.FolderID != "folder-1" || folder.ParentID != "root" {
+		t.Errorf("Expected camelCase response to decode, got %+v", folder)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"fileKey":       "file_key",
+		"presignedUrls": "presigned_urls",
+		"mainTaskID":    "main_task_id",
+		"HTTPStatus":    "http_status",
+		"name":          "name",
+	}
+	for key, want := range tests {
+		if got := snakeCase(key); got != want {
+			t.Errorf("Expected %s for %s, got %s", want, key, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package d3
+
+import "fmt"
+
+// Environment selects a D3 deployment whose base URL the client uses when
+// Config.BaseURL is not set
+type Environment string
+
+const (
+	// EnvironmentProduction is the production API (the default)
+	EnvironmentProduction Environment = "production"
+	// EnvironmentStaging is the pre-release staging API
+	EnvironmentStaging Environment = "staging"
+	// EnvironmentDevelopment is the development API, whose data may be reset
+	// at any time
+	EnvironmentDevelopment Environment = "development"
+)
+
+// environmentBaseURLs maps each environment to its API base URL
+var environmentBaseURLs = map[Environment]string{
+	EnvironmentProduction:  "https://api.dragdropdo.com",
+	EnvironmentStaging:     "https://api-staging.dragdropdo.com",
+	EnvironmentDevelopment: "https://api-dev.dragdropdo.com",
+}
+
+// BaseURL returns the API base URL of the environment
+func (e Environment) BaseURL() (string, error) {
+	if e == "" {
+		e = EnvironmentProduction
+	}
+	baseURL, ok := environmentBaseURLs[e]
+	if !ok {
+		return "", fmt.Errorf("unknown environment %q", string(e))
+	}
+	return baseURL, nil
+}
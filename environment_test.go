@@ -0,0 +1,32 @@
+package d3
+
+import "testing"
+
+func TestNewClient_Environment(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"default", Config{APIKey: "test-key"}, "https://api.dragdropdo.com"},
+		{"staging", Config{APIKey: "test-key", Environment: EnvironmentStaging}, "https://api-staging.dragdropdo.com"},
+		{"development", Config{APIKey: "test-key", Environment: EnvironmentDevelopment}, "https://api-dev.dragdropdo.com"},
+		{"base URL wins", Config{APIKey: "test-key", Environment: EnvironmentStaging, BaseURL: "https://d3.internal/"}, "https://d3.internal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewDragdropdo(tt.config)
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			if client.baseURL != tt.expected {
+				t.Errorf("Expected base URL '%s', got '%s'", tt.expected, client.baseURL)
+			}
+		})
+	}
+
+	if _, err := NewClient("test-key", WithEnvironment("prod")); err == nil {
+		t.Error("Expected error for unknown environment")
+	}
+}
@@ -0,0 +1,64 @@
+package d3
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrorCode is a documented API error code, sent as "code" in the API's
+// error body alongside the HTTP status
+type ErrorCode int
+
+const (
+	// ErrorCodeUnknown is reported for errors without a code or with a code
+	// not listed here; D3APIError.Code still holds the raw value
+	ErrorCodeUnknown ErrorCode = 0
+	// ErrorCodeInvalidFileKey means a file key does not exist or has expired
+	ErrorCodeInvalidFileKey ErrorCode = 4001
+	// ErrorCodeFileTooLarge means the file exceeds the plan's size limit
+	ErrorCodeFileTooLarge ErrorCode = 4131
+	// ErrorCodeUnsupportedFormat means the action does not support the
+	// file's format or the requested output format
+	ErrorCodeUnsupportedFormat ErrorCode = 4221
+	// ErrorCodePasswordIncorrect means the password for a protected file is
+	// missing or wrong
+	ErrorCodePasswordIncorrect ErrorCode = 4222
+	// ErrorCodeQuotaExceeded means the account's usage quota is used up.
+	// Unlike rate limiting, retrying does not help until the quota resets.
+	ErrorCodeQuotaExceeded ErrorCode = 4291
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	ErrorCodeInvalidFileKey:    "invalid_file_key",
+	ErrorCodeFileTooLarge:      "file_too_large",
+	ErrorCodeUnsupportedFormat: "unsupported_format",
+	ErrorCodePasswordIncorrect: "password_incorrect",
+	ErrorCodeQuotaExceeded:     "quota_exceeded",
+}
+
+// String returns the code's name, e.g. "quota_exceeded", or the number for
+// unknown codes
+func (c ErrorCode) String() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return strconv.Itoa(int(c))
+}
+
+// ErrorCode maps the error's numeric code to a documented ErrorCode, or
+// ErrorCodeUnknown
+func (e *D3APIError) ErrorCode() ErrorCode {
+	if e.Code == nil {
+		return ErrorCodeUnknown
+	}
+	if _, ok := errorCodeNames[ErrorCode(*e.Code)]; !ok {
+		return ErrorCodeUnknown
+	}
+	return ErrorCode(*e.Code)
+}
+
+// HasErrorCode reports whether err is or wraps a *D3APIError with code
+func HasErrorCode(err error, code ErrorCode) bool {
+	var apiErr *D3APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
@@ -0,0 +1,57 @@
+package d3
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestD3APIError_ErrorCode(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected ErrorCode
+	}{
+		{`{"message":"monthly quota exceeded","code":4291}`, ErrorCodeQuotaExceeded},
+		{`{"error":{"message":"unsupported format","code":"4221"}}`, ErrorCodeUnsupportedFormat},
+		{`{"message":"incorrect password","code":4222}`, ErrorCodePasswordIncorrect},
+		{`{"message":"file too large","code":4131}`, ErrorCodeFileTooLarge},
+		{`{"message":"something new","code":9999}`, ErrorCodeUnknown},
+		{`{"message":"no code"}`, ErrorCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		apiErr := newAPIErrorFromResponse(&apiResponse{StatusCode: http.StatusBadRequest, Header: http.Header{}, Body: []byte(tt.body)})
+		if code := apiErr.ErrorCode(); code != tt.expected {
+			t.Errorf("Expected %v for %s, got %v", tt.expected, tt.body, code)
+		}
+	}
+
+	if ErrorCodeQuotaExceeded.String() != "quota_exceeded" || ErrorCode(9999).String() != "9999" {
+		t.Errorf("Unexpected names %q, %q", ErrorCodeQuotaExceeded, ErrorCode(9999))
+	}
+}
+
+func TestClient_QuotaExceededNotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"monthly quota exceeded","code":4291}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithRetry(RetryPolicy{MaxAttempts: 3}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.Convert([]FileKey{"file-key-123"}, "png", nil)
+	if !HasErrorCode(fmt.Errorf("convert: %w", err), ErrorCodeQuotaExceeded) {
+		t.Errorf("Expected quota exceeded error, got %v", err)
+	}
+	if calls != 1 || IsRetryable(err) {
+		t.Errorf("Expected quota exceeded not to be retried, got %d calls", calls)
+	}
+}
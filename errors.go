@@ -1,6 +1,73 @@
 package d3
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors, matched with errors.Is. They are wrapped by the typed
+// errors below, so errors.As still yields the details.
+var (
+	// ErrClientClosed is returned by calls started after Close
+	ErrClientClosed = errors.New("client is closed")
+	// ErrFileNotFound is returned when the file to upload does not exist
+	ErrFileNotFound = errors.New("file not found")
+	// ErrFileTooLarge is returned when the file to upload exceeds the
+	// maximum upload size
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrMissingUploadID is returned when the API initiates an upload
+	// without an upload ID
+	ErrMissingUploadID = errors.New("upload ID not received from server")
+	// ErrPresignedURLMismatch is returned when the API returns a number of
+	// presigned URLs the file cannot be split into
+	ErrPresignedURLMismatch = errors.New("presigned URL count does not match part count")
+	// ErrMissingETag is returned when storage accepts a part without
+	// returning its ETag
+	ErrMissingETag = errors.New("ETag not received for part")
+	// ErrPollTimeout is returned when PollStatus gives up while the
+	// operation is still running
+	ErrPollTimeout = errors.New("polling timed out")
+	// ErrRequestTimeout is returned by PollStatus when a status request
+	// exceeded its timeout, saying nothing about the operation itself
+	ErrRequestTimeout = errors.New("request timed out")
+	// ErrChecksumMismatch is returned when the ETag of a completed upload
+	// does not match the parts sent, i.e. the stored object is corrupt
+	ErrChecksumMismatch = errors.New("uploaded object checksum mismatch")
+	// ErrUnsupportedOperation is returned by pre-flight validation when a
+	// file's extension does not support the requested action
+	ErrUnsupportedOperation = errors.New("operation not supported for file type")
+	// ErrNoRecording is returned by a replaying Cassette for a request it
+	// has no (unused) recording of
+	ErrNoRecording = errors.New("no recorded interaction matches the request")
+	// ErrDryRun is wrapped by the *D3DryRunError returned in dry-run mode
+	ErrDryRun = errors.New("dry run: request not sent")
+	// ErrUnauthorized is returned by Ping when the API rejects the API key
+	ErrUnauthorized = errors.New("API key rejected")
+	// ErrUnreachable is returned by Ping when no response was received,
+	// e.g. on DNS, connection or TLS failures
+	ErrUnreachable = errors.New("API unreachable")
+	// ErrServiceUnavailable is returned by Ping when the API answers with
+	// a server error or asks to slow down (429 or 5xx)
+	ErrServiceUnavailable = errors.New("service unavailable")
+	// ErrNoDownloadLink is returned when downloading the output of a file
+	// that did not complete and so has none
+	ErrNoDownloadLink = errors.New("no download link")
+	// ErrJobPending is returned by Job.Results before the operation has
+	// finished
+	ErrJobPending = errors.New("job has not finished")
+	// ErrDecryptionFailed is returned when an output cannot be decrypted
+	// with Config.Encryption: it was encrypted with another key, or was
+	// modified or truncated
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
 
 // D3ClientError is the base error class for D3 Client errors
 type D3ClientError struct {
@@ -8,15 +75,97 @@ type D3ClientError struct {
 	StatusCode *int
 	Code       *int
 	Details    interface{}
+	// Err is the underlying cause, if any, e.g. a network or file error
+	Err error
+
+	// Endpoint, RequestID and Body describe the failed HTTP response, when
+	// the error came from one. Endpoint is the method and path (without
+	// query, which may carry credentials), RequestID the server's request
+	// ID header, and Body the response body, redacted and truncated to
+	// errorBodyLimit bytes.
+	Endpoint  string
+	RequestID string
+	Body      string
+}
+
+// errorBodyLimit is the number of response body bytes kept on errors
+const errorBodyLimit = 1024
+
+// requestIDHeaders are the response headers checked, in order, for the
+// server's request ID
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amz-Request-Id"}
+
+// requestIDFromHeader returns the request ID the server sent, if any
+func requestIDFromHeader(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
 }
 
 func (e *D3ClientError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying cause so errors.Is and errors.As can see
+// through D3 errors
+func (e *D3ClientError) Unwrap() error {
+	return e.Err
+}
+
+// As lets errors.As match any D3 error type against *D3ClientError
+func (e *D3ClientError) As(target interface{}) bool {
+	if t, ok := target.(**D3ClientError); ok {
+		*t = e
+		return true
+	}
+	return false
+}
+
+// newValidationError returns a D3ValidationError with message caused by err
+func newValidationError(err error, message string) *D3ValidationError {
+	e := NewD3ValidationError(message, nil)
+	e.Err = err
+	return e
+}
+
+// newUploadError returns a D3UploadError with message caused by err
+func newUploadError(err error, message string) *D3UploadError {
+	e := NewD3UploadError(message, nil)
+	e.Err = err
+	return e
+}
+
+// wrapUploadError returns a D3UploadError for message caused by err
+func wrapUploadError(err error, message string) *D3UploadError {
+	e := NewD3UploadError(fmt.Sprintf("%s: %v", message, err), nil)
+	e.Err = err
+	return e
+}
+
 // D3APIError represents an error returned by the API
 type D3APIError struct {
 	D3ClientError
+
+	// retryable is the API's own retry hint, if it sent one
+	retryable *bool
+}
+
+// Retryable reports whether the failed call may succeed if repeated: the
+// API's "retryable" hint when present, otherwise whether the status code
+// indicates a transient failure (408, 429, 500, 502, 503 or 504) and the
+// error code is not a lasting one such as ErrorCodeQuotaExceeded. The
+// client's retry layer makes the same decision by default.
+func (e *D3APIError) Retryable() bool {
+	if e.retryable != nil {
+		return *e.retryable
+	}
+	if e.ErrorCode() == ErrorCodeQuotaExceeded {
+		return false
+	}
+	return e.StatusCode != nil && retryableStatus(*e.StatusCode)
 }
 
 func NewD3APIError(message string, statusCode int, code *int, details interface{}) *D3APIError {
@@ -30,6 +179,118 @@ func NewD3APIError(message string, statusCode int, code *int, details interface{
 	}
 }
 
+// apiErrorEnvelope is the error body returned by the API, either at the top
+// level or nested under "error"
+type apiErrorEnvelope struct {
+	Message   string          `json:"message"`
+	Error     json.RawMessage `json:"error"`
+	Code      json.RawMessage `json:"code"`
+	Details   interface{}     `json:"details"`
+	Retryable *bool           `json:"retryable"`
+}
+
+// decodeErrorEnvelope decodes the API's error envelope from body, lifting a
+// nested "error" object or string to the top level
+func decodeErrorEnvelope(body []byte) apiErrorEnvelope {
+	var envelope apiErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		// {"error": {"message": ..., "code": ...}} or {"error": "..."}
+		var nested apiErrorEnvelope
+		var text string
+		if json.Unmarshal(envelope.Error, &nested) == nil && (nested.Message != "" || len(nested.Code) > 0) {
+			if nested.Message != "" {
+				envelope.Message = nested.Message
+			}
+			envelope.Code, envelope.Details = nested.Code, nested.Details
+			if nested.Retryable != nil {
+				envelope.Retryable = nested.Retryable
+			}
+		} else if json.Unmarshal(envelope.Error, &text) == nil && envelope.Message == "" {
+			envelope.Message = text
+		}
+	}
+	return envelope
+}
+
+// newAPIErrorFromResponse builds a D3APIError from a non-2xx response,
+// decoding the API's error envelope when present
+func newAPIErrorFromResponse(resp *apiResponse) *D3APIError {
+	envelope := decodeErrorEnvelope(resp.Body)
+	message := envelope.Message
+	if message == "" {
+		message = fmt.Sprintf("API request failed with status %d", resp.StatusCode)
+		if text := http.StatusText(resp.StatusCode); text != "" {
+			message += " " + text
+		}
+	}
+	return newAPIError(resp, envelope, message)
+}
+
+// successEnvelope is the part of a 2xx response body that can still report
+// a failure
+type successEnvelope struct {
+	Success *bool           `json:"success"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// newAPIErrorFromEnvelope returns a D3APIError if a 2xx response reports
+// failure inside its envelope, with "success": false or an "error" object
+// or message, and nil otherwise
+func newAPIErrorFromEnvelope(resp *apiResponse) *D3APIError {
+	var success successEnvelope
+	if json.Unmarshal(resp.Body, &success) != nil {
+		return nil
+	}
+	if success.Success != nil {
+		if *success.Success {
+			return nil
+		}
+	} else {
+		// Without a success flag only a non-empty "error" counts; a
+		// top-level "message" alone is informational
+		var nested apiErrorEnvelope
+		var text string
+		hasError := json.Unmarshal(success.Error, &nested) == nil && (nested.Message != "" || len(nested.Code) > 0) ||
+			json.Unmarshal(success.Error, &text) == nil && text != ""
+		if !hasError {
+			return nil
+		}
+	}
+
+	envelope := decodeErrorEnvelope(resp.Body)
+
+	message := envelope.Message
+	if message == "" {
+		message = fmt.Sprintf("API request reported failure despite status %d", resp.StatusCode)
+	}
+	return newAPIError(resp, envelope, message)
+}
+
+// newAPIError builds a D3APIError for resp from its decoded envelope
+func newAPIError(resp *apiResponse, envelope apiErrorEnvelope, message string) *D3APIError {
+	apiErr := NewD3APIError(message, resp.StatusCode, parseErrorCode(envelope.Code), envelope.Details)
+	apiErr.retryable = envelope.Retryable
+	apiErr.RequestID = requestIDFromHeader(resp.Header)
+	if len(resp.Body) > 0 {
+		apiErr.Body = redactBody(resp.Body, errorBodyLimit)
+	}
+	return apiErr
+}
+
+// parseErrorCode reads a numeric error code, which the API sends either as a
+// number or as a numeric string
+func parseErrorCode(raw json.RawMessage) *int {
+	raw = bytes.Trim(bytes.TrimSpace(raw), `"`)
+	if len(raw) == 0 {
+		return nil
+	}
+	code, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return nil
+	}
+	return &code
+}
+
 // D3ValidationError represents a client-side validation error
 type D3ValidationError struct {
 	D3ClientError
@@ -49,6 +310,11 @@ func NewD3ValidationError(message string, details interface{}) *D3ValidationErro
 // D3UploadError represents an upload-specific error
 type D3UploadError struct {
 	D3ClientError
+
+	// PartsCompleted and TotalParts tell how far a multipart upload got
+	// before it was cancelled; both are zero for other upload errors
+	PartsCompleted int
+	TotalParts     int
 }
 
 func NewD3UploadError(message string, details interface{}) *D3UploadError {
@@ -60,9 +326,16 @@ func NewD3UploadError(message string, details interface{}) *D3UploadError {
 	}
 }
 
-// D3TimeoutError represents a timeout error (from polling)
+// D3TimeoutError represents a timeout error (from polling). It wraps
+// ErrPollTimeout when the operation was still running as the polling budget
+// ran out, or ErrRequestTimeout when a status request timed out.
 type D3TimeoutError struct {
 	D3ClientError
+
+	// TaskID is the main task ID that was polled, so polling can resume
+	TaskID MainTaskID
+	// LastStatus is the last status received, nil if none was
+	LastStatus *StatusResponse
 }
 
 func NewD3TimeoutError(message string) *D3TimeoutError {
@@ -76,34 +349,162 @@ func NewD3TimeoutError(message string) *D3TimeoutError {
 	}
 }
 
-// Helper function to check error types
+// newPollTimeoutError returns the D3TimeoutError for a poll of taskID whose
+// budget ran out after timeout
+func newPollTimeoutError(taskID MainTaskID, timeout time.Duration, last *StatusResponse) *D3TimeoutError {
+	message := fmt.Sprintf("%v after %v", ErrPollTimeout, timeout)
+	if last != nil {
+		message += fmt.Sprintf(": operation %s is still %s", taskID, last.OperationStatus)
+	}
+	e := NewD3TimeoutError(message)
+	e.Err = ErrPollTimeout
+	e.TaskID = taskID
+	e.LastStatus = last
+	return e
+}
+
+// newRequestTimeoutError returns the D3TimeoutError for a status request of
+// taskID that timed out with err
+func newRequestTimeoutError(taskID MainTaskID, err error, last *StatusResponse) *D3TimeoutError {
+	e := NewD3TimeoutError(fmt.Sprintf("status request for operation %s timed out: %v", taskID, err))
+	e.Err = fmt.Errorf("%w: %w", ErrRequestTimeout, err)
+	e.TaskID = taskID
+	e.LastStatus = last
+	return e
+}
+
+// D3DryRunError is returned in dry-run mode in place of sending an API
+// request. Request is the request that would have been sent; the call
+// stopped there.
+type D3DryRunError struct {
+	D3ClientError
+
+	Request DryRunRequest
+}
+
+// IsD3APIError reports whether err is or wraps a *D3APIError. Prefer
+// errors.As to also get at the error's fields.
 func IsD3APIError(err error) bool {
-	_, ok := err.(*D3APIError)
-	return ok
+	var target *D3APIError
+	return errors.As(err, &target)
 }
 
+// IsD3ValidationError reports whether err is or wraps a *D3ValidationError
 func IsD3ValidationError(err error) bool {
-	_, ok := err.(*D3ValidationError)
-	return ok
+	var target *D3ValidationError
+	return errors.As(err, &target)
 }
 
+// IsD3UploadError reports whether err is or wraps a *D3UploadError
 func IsD3UploadError(err error) bool {
-	_, ok := err.(*D3UploadError)
-	return ok
+	var target *D3UploadError
+	return errors.As(err, &target)
 }
 
+// IsD3TimeoutError reports whether err is or wraps a *D3TimeoutError
 func IsD3TimeoutError(err error) bool {
-	_, ok := err.(*D3TimeoutError)
-	return ok
+	var target *D3TimeoutError
+	return errors.As(err, &target)
+}
+
+// IsD3DryRunError reports whether err is or wraps a *D3DryRunError
+func IsD3DryRunError(err error) bool {
+	var target *D3DryRunError
+	return errors.As(err, &target)
 }
 
-// FormatError formats an error with additional context
+// FormatError formats an error with additional context: the status of an
+// API error and, for errors from an HTTP response, the endpoint, request ID
+// and truncated response body on following lines
 func FormatError(err error) string {
-	if apiErr, ok := err.(*D3APIError); ok {
-		if apiErr.StatusCode != nil {
-			return fmt.Sprintf("API Error (%d): %s", *apiErr.StatusCode, apiErr.Message)
+	var b strings.Builder
+	var apiErr *D3APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode != nil {
+		fmt.Fprintf(&b, "API Error (%d): %s", *apiErr.StatusCode, apiErr.Message)
+	} else {
+		b.WriteString(err.Error())
+	}
+
+	var clientErr *D3ClientError
+	if errors.As(err, &clientErr) {
+		if clientErr.Endpoint != "" {
+			fmt.Fprintf(&b, "\n  endpoint: %s", clientErr.Endpoint)
+		}
+		if clientErr.RequestID != "" {
+			fmt.Fprintf(&b, "\n  request ID: %s", clientErr.RequestID)
 		}
+		if clientErr.Body != "" {
+			fmt.Fprintf(&b, "\n  response: %s", clientErr.Body)
+		}
+	}
+	return b.String()
+}
+
+// stripQuery returns rawURL without its query, which for presigned URLs holds
+// the signature
+func stripQuery(rawURL string) string {
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		return rawURL[:i]
+	}
+	return rawURL
+}
+
+// storageError is the XML error body returned by S3-compatible storage for a
+// rejected presigned request
+type storageError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// newPartUploadError builds a D3UploadError from a failed presigned part
+// upload to url, including the storage service's error code and message if
+// present
+func newPartUploadError(partNumber int, url string, resp *TransportResponse) *D3UploadError {
+	message := fmt.Sprintf("failed to upload part %d: status %d", partNumber, resp.StatusCode)
+	details := map[string]interface{}{
+		"part_number": partNumber,
+		"status_code": resp.StatusCode,
+	}
+
+	body := resp.Body
+	if len(body) > 4096 {
+		body = body[:4096]
+	}
+	var storageErr storageError
+	if xml.Unmarshal(body, &storageErr) == nil && storageErr.Code != "" {
+		message += fmt.Sprintf(": %s: %s", storageErr.Code, storageErr.Message)
+		details["code"] = storageErr.Code
+		details["message"] = storageErr.Message
+	}
+
+	e := NewD3UploadError(message, details)
+	statusCode := resp.StatusCode
+	e.StatusCode = &statusCode
+	e.RequestID = requestIDFromHeader(resp.Header)
+	if len(body) > 0 {
+		e.Body = redactBody(body, errorBodyLimit)
 	}
-	return err.Error()
+	e.Endpoint = http.MethodPut + " " + stripQuery(url)
+	return e
 }
 
+// newDownloadError returns the error for a download of fileKey's output that
+// storage answered with resp
+func newDownloadError(fileKey FileKey, resp *http.Response) *D3APIError {
+	message := fmt.Sprintf("failed to download %s: status %d", fileKey, resp.StatusCode)
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var storageErr storageError
+	if xml.Unmarshal(body, &storageErr) == nil && storageErr.Code != "" {
+		message += fmt.Sprintf(": %s: %s", storageErr.Code, storageErr.Message)
+	}
+
+	e := NewD3APIError(message, resp.StatusCode, nil, map[string]interface{}{"file_key": string(fileKey)})
+	e.RequestID = requestIDFromHeader(resp.Header)
+	if len(body) > 0 {
+		e.Body = redactBody(body, errorBodyLimit)
+	}
+	if resp.Request != nil {
+		e.Endpoint = resp.Request.Method + " " + stripQuery(resp.Request.URL.String())
+	}
+	return e
+}
@@ -0,0 +1,333 @@
+package d3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_APIErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantMessage string
+		wantCode    int
+	}{
+		{"top level", http.StatusUnprocessableEntity, `{"message":"unsupported conversion","code":4221,"details":{"ext":"xyz"}}`, "unsupported conversion", 4221},
+		{"nested", http.StatusBadRequest, `{"success":false,"error":{"message":"invalid file key","code":"4001"}}`, "invalid file key", 4001},
+		{"error string", http.StatusForbidden, `{"error":"plan limit reached"}`, "plan limit reached", 0},
+		{"no body", http.StatusInternalServerError, ``, "API request failed with status 500 Internal Server Error", 0},
+		{"200 success false", http.StatusOK, `{"success":false,"error":{"message":"quota exceeded","code":4291}}`, "quota exceeded", 4291},
+		{"200 error object", http.StatusOK, `{"data":{},"error":{"message":"file locked","code":4222}}`, "file locked", 4222},
+		{"200 success false without message", http.StatusOK, `{"success":false,"data":null}`, "API request reported failure despite status 200", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			result, err := client.Convert([]FileKey{"file-key-123"}, "png", nil)
+			if result != nil {
+				t.Errorf("Expected no result, got %+v", result)
+			}
+			var apiErr *D3APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *D3APIError, got %T: %v", err, err)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, apiErr.Message)
+			}
+			if *apiErr.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, *apiErr.StatusCode)
+			}
+			if tt.wantCode != 0 && (apiErr.Code == nil || *apiErr.Code != tt.wantCode) {
+				t.Errorf("Expected code %d, got %v", tt.wantCode, apiErr.Code)
+			}
+		})
+	}
+}
+
+func TestClient_SuccessEnvelopeWithMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"message":"accepted","error":null,"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.Convert([]FileKey{"file-key-123"}, "png", nil)
+	if err != nil || result.MainTaskID != "task-123" {
+		t.Errorf("Expected a successful operation, got %+v, %v", result, err)
+	}
+}
+
+func TestClient_StatusCodeValidation(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(tmpFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload" && r.Header.Get("Authorization") == "Bearer bad-key":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"invalid API key"}`))
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			w.Write([]byte(`{"data":{"file_key":"file-key-123","upload_id":"upload-id-456","presigned_urls":["` + server.URL + `/part/1"]}}`))
+		case r.URL.Path == "/part/1":
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>SignatureDoesNotMatch</Code><Message>The request signature we calculated does not match</Message></Error>`))
+		case r.URL.Path == "/v1/biz/do":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"unsupported conversion"}`))
+		case r.URL.Path == "/v1/biz/status/task-500":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"status service unavailable"}`))
+		case r.URL.Path == "/v1/biz/status/task-empty":
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "bad-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	upload, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "test.txt"})
+	if upload != nil || err == nil || !strings.Contains(err.Error(), "invalid API key") {
+		t.Errorf("Expected upload to fail with the server message, got %v, %v", upload, err)
+	}
+
+	client.SetAPIKey("test-key")
+	upload, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "test.txt"})
+	var uploadErr *D3UploadError
+	if upload != nil || !errors.As(err, &uploadErr) || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Expected part upload to fail with the storage error, got %v, %v", upload, err)
+	}
+	if uploadErr != nil && !strings.Contains(FormatError(err), "endpoint: PUT "+server.URL+"/part/1") {
+		t.Errorf("Expected the part URL in FormatError output, got:\n%s", FormatError(err))
+	}
+
+	operation, err := client.Convert([]FileKey{"file-key-123"}, "xyz", nil)
+	if operation != nil || err == nil || !strings.Contains(err.Error(), "unsupported conversion") {
+		t.Errorf("Expected operation to fail with the server message, got %v, %v", operation, err)
+	}
+
+	for _, taskID := range []MainTaskID{"task-500", "task-empty"} {
+		status, err := client.GetStatus(StatusOptions{MainTaskID: taskID})
+		if status != nil || err == nil {
+			t.Errorf("Expected status of %s to fail, got %v, %v", taskID, status, err)
+		}
+	}
+}
+
+func TestErrors_Unwrap(t *testing.T) {
+	wrapped := fmt.Errorf("failed to get status: %w", NewD3APIError("not found", 404, nil, nil))
+	if !IsD3APIError(wrapped) {
+		t.Error("Expected IsD3APIError to see through wrapping")
+	}
+	var base *D3ClientError
+	if !errors.As(wrapped, &base) || *base.StatusCode != 404 {
+		t.Errorf("Expected errors.As to match the D3ClientError base, got %v", base)
+	}
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.UploadFile(UploadFileOptions{File: "does-not-exist.pdf", FileName: "x.pdf"})
+	if !IsD3ValidationError(err) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected validation error wrapping os.ErrNotExist, got %T: %v", err, err)
+	}
+
+	_, err = client.UploadFile(UploadFileOptions{File: "does-not-exist.pdf"})
+	var validationErr *D3ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Expected *D3ValidationError for missing file name, got %T: %v", err, err)
+	}
+
+	cause := errors.New("connection reset")
+	uploadErr := wrapUploadError(cause, "failed to upload chunk")
+	if !errors.Is(fmt.Errorf("upload: %w", uploadErr), cause) {
+		t.Error("Expected errors.Is to reach the upload error's cause")
+	}
+	if uploadErr.Error() != "failed to upload chunk: connection reset" {
+		t.Errorf("Unexpected message: %s", uploadErr.Error())
+	}
+}
+
+func TestErrors_Sentinels(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(tmpFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var initiate string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			w.Write([]byte(initiate))
+		case strings.HasPrefix(r.URL.Path, "/v1/biz/status/"):
+			w.Write([]byte(`{"data":{"operation_status":"processing"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.UploadFile(UploadFileOptions{File: "does-not-exist.pdf", FileName: "x.pdf"})
+	if !errors.Is(err, ErrFileNotFound) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+
+	initiate = `{"data":{"file_key":"file-key-123","upload_id":"upload-id-456","presigned_urls":[]}}`
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "test.txt"})
+	if !errors.Is(err, ErrPresignedURLMismatch) {
+		t.Errorf("Expected ErrPresignedURLMismatch, got %v", err)
+	}
+
+	initiate = `{"data":{"file_key":"file-key-123","presigned_urls":["` + server.URL + `/part/1"]}}`
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "test.txt"})
+	if !errors.Is(err, ErrMissingUploadID) {
+		t.Errorf("Expected ErrMissingUploadID, got %v", err)
+	}
+
+	initiate = `{"data":{"file_key":"file-key-123","upload_id":"upload-id-456","presigned_urls":["` + server.URL + `/part/1"]}}`
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "test.txt"})
+	if !errors.Is(err, ErrMissingETag) {
+		t.Errorf("Expected ErrMissingETag, got %v", err)
+	}
+
+	_, err = client.PollStatus(PollStatusOptions{
+		StatusOptions: StatusOptions{MainTaskID: "task-123"},
+		Interval:      5 * time.Millisecond,
+		Timeout:       20 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrPollTimeout) {
+		t.Errorf("Expected ErrPollTimeout, got %v", err)
+	}
+}
+
+func TestErrors_ResponseDiagnostics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-789")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"task not found","password":"hunter2"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+	var apiErr *D3APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *D3APIError, got %T: %v", err, err)
+	}
+	if apiErr.Endpoint != "GET /v1/biz/status/task-123" {
+		t.Errorf("Expected endpoint 'GET /v1/biz/status/task-123', got '%s'", apiErr.Endpoint)
+	}
+	if apiErr.RequestID != "req-789" {
+		t.Errorf("Expected request ID 'req-789', got '%s'", apiErr.RequestID)
+	}
+	if !strings.Contains(apiErr.Body, "task not found") || strings.Contains(apiErr.Body, "hunter2") {
+		t.Errorf("Expected redacted response body, got '%s'", apiErr.Body)
+	}
+
+	formatted := FormatError(err)
+	for _, want := range []string{"API Error (404): task not found", "endpoint: GET /v1/biz/status/task-123", "request ID: req-789", "response: {"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("Expected FormatError output to contain %q, got:\n%s", want, formatted)
+		}
+	}
+}
+
+func TestErrors_ResponseBodyTruncated(t *testing.T) {
+	resp := &apiResponse{
+		StatusCode: http.StatusBadGateway,
+		Header:     http.Header{},
+		Body:       []byte(strings.Repeat("x", 5000)),
+	}
+	apiErr := newAPIErrorFromResponse(resp)
+	if len(apiErr.Body) > errorBodyLimit+len("...(truncated)") || !strings.HasSuffix(apiErr.Body, "...(truncated)") {
+		t.Errorf("Expected body truncated to %d bytes, got %d", errorBodyLimit, len(apiErr.Body))
+	}
+}
+
+func TestClient_PollStatus_TimeoutClasses(t *testing.T) {
+	delay := time.Duration(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"running","files_data":[]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	options := PollStatusOptions{
+		StatusOptions: StatusOptions{MainTaskID: "task-123"},
+		Interval:      5 * time.Millisecond,
+		Timeout:       20 * time.Millisecond,
+	}
+
+	_, err = client.PollStatus(options)
+	var timeoutErr *D3TimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, ErrPollTimeout) || errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("Expected a D3TimeoutError wrapping ErrPollTimeout, got %T: %v", err, err)
+	}
+	if timeoutErr.TaskID != "task-123" || timeoutErr.LastStatus == nil || timeoutErr.LastStatus.OperationStatus != "running" {
+		t.Errorf("Expected the task ID and last status to be preserved, got %q, %+v", timeoutErr.TaskID, timeoutErr.LastStatus)
+	}
+	if IsRetryable(err) {
+		t.Error("Expected a poll timeout not to be retryable")
+	}
+
+	delay = 200 * time.Millisecond
+	options.Timeout = time.Second
+	_, err = client.PollStatus(options)
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, ErrRequestTimeout) || errors.Is(err, ErrPollTimeout) {
+		t.Fatalf("Expected a D3TimeoutError wrapping ErrRequestTimeout, got %T: %v", err, err)
+	}
+	if timeoutErr.TaskID != "task-123" {
+		t.Errorf("Expected task ID task-123, got %q", timeoutErr.TaskID)
+	}
+	if !IsRetryable(err) {
+		t.Error("Expected a request timeout to be retryable")
+	}
+}
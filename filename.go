@@ -0,0 +1,153 @@
+package d3
+
+import (
+	"mime"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxFileNameBytes is the longest file name sent to the API, the common
+// file system limit
+const maxFileNameBytes = 255
+
+// maxKeptExtBytes is the longest extension kept when a name is shortened
+const maxKeptExtBytes = 16
+
+// SanitizeFileName returns name as UploadFile sends it: NFC-normalized,
+// with invalid UTF-8, control and format characters removed, path
+// separators replaced by "_", surrounding spaces trimmed, and shortened to
+// 255 bytes at a character boundary, keeping the extension. Storage
+// backends reject some unsanitized names with opaque errors, and decomposed
+// names (as produced by macOS) would otherwise not match their composed
+// equivalents. Names are not normalized in the d3_stdlib build.
+func SanitizeFileName(name string) string {
+	name = normalizeNFC(strings.ToValidUTF8(name, ""))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\':
+			return '_'
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if len(name) <= maxFileNameBytes {
+		return name
+	}
+	ext := filepath.Ext(name)
+	if len(ext) > maxKeptExtBytes {
+		ext = ""
+	}
+	return truncateUTF8(strings.TrimSuffix(name, ext), maxFileNameBytes-len(ext)) + ext
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// ContentDispositionFileName returns the file name assigned by a
+// Content-Disposition header, without directories and sanitized like
+// SanitizeFileName, or "" if it assigns none. An RFC 5987 filename* in UTF-8
+// or ISO-8859-1 takes precedence over filename, and malformed headers, such
+// as unquoted names with spaces, are read leniently.
+func ContentDispositionFileName(header string) string {
+	var name string
+	if _, params, err := mime.ParseMediaType(header); err == nil {
+		name = params["filename"]
+	}
+	// ParseMediaType drops filename* in other charsets than UTF-8 and
+	// rejects malformed headers altogether
+	params := dispositionParams(header)
+	if extended, ok := decodeExtendedValue(params["filename*"]); ok {
+		name = extended
+	} else if name == "" {
+		name = params["filename"]
+	}
+
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = SanitizeFileName(name)
+	if name == "." || name == ".." {
+		return ""
+	}
+	return name
+}
+
+// dispositionParams returns the parameters of a Content-Disposition header
+// by lower-cased name, unquoted, keeping the first of repeated names
+func dispositionParams(header string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(header, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = unquoteDispositionValue(value[1 : len(value)-1])
+		}
+		if _, seen := params[key]; !seen {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+// unquoteDispositionValue removes the backslash escapes of a quoted string
+func unquoteDispositionValue(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// decodeExtendedValue decodes an RFC 5987 value, charset'language'encoded,
+// in UTF-8 or ISO-8859-1
+func decodeExtendedValue(value string) (string, bool) {
+	charset, rest, ok := strings.Cut(value, "'")
+	if !ok {
+		return "", false
+	}
+	_, encoded, ok := strings.Cut(rest, "'")
+	if !ok {
+		return "", false
+	}
+	decoded, err := url.PathUnescape(encoded)
+	if err != nil || decoded == "" {
+		return "", false
+	}
+
+	switch strings.ToLower(charset) {
+	case "utf-8":
+		return decoded, utf8.ValidString(decoded)
+	case "iso-8859-1":
+		// Each byte is the code point of the same value
+		runes := make([]rune, len(decoded))
+		for i := 0; i < len(decoded); i++ {
+			runes[i] = rune(decoded[i])
+		}
+		return string(runes), true
+	}
+	return "", false
+}
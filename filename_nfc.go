@@ -0,0 +1,10 @@
+//go:build !d3_stdlib
+
+package d3
+
+import "golang.org/x/text/unicode/norm"
+
+// normalizeNFC returns s in Unicode normalization form C
+func normalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
@@ -0,0 +1,9 @@
+//go:build d3_stdlib
+
+package d3
+
+// normalizeNFC returns s unchanged: Unicode normalization needs
+// golang.org/x/text, which the zero-dependency build leaves out
+func normalizeNFC(s string) string {
+	return s
+}
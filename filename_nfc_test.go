@@ -0,0 +1,11 @@
+//go:build !d3_stdlib
+
+package d3
+
+import "testing"
+
+func TestSanitizeFileName_NFC(t *testing.T) {
+	if got, want := SanitizeFileName("re\u0301sume\u0301.pdf"), "r\u00e9sum\u00e9.pdf"; got != want {
+		t.Errorf("Expected the decomposed name composed as %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,110 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"  tab\there\x00.txt \n", "tabhere.txt"},
+		{"zero\u200bwidth.txt", "zerowidth.txt"},
+		{"dir/sub\\name.txt", "dir_sub_name.txt"},
+		{"bad\xffutf8.txt", "badutf8.txt"},
+		{"日本語のファイル.docx", "日本語のファイル.docx"},
+		{"\x01\x02", ""},
+	}
+	for _, tt := range tests {
+		if got := SanitizeFileName(tt.name); got != tt.want {
+			t.Errorf("Expected SanitizeFileName(%q) = %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	long := SanitizeFileName(strings.Repeat("é", 200) + ".pdf")
+	if len(long) > maxFileNameBytes || !strings.HasSuffix(long, ".pdf") || !utf8.ValidString(long) {
+		t.Errorf("Expected a valid name of at most %d bytes keeping .pdf, got %d bytes: %q", maxFileNameBytes, len(long), long)
+	}
+}
+
+func TestContentDispositionFileName(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{`attachment; filename="report.pdf"`, "report.pdf"},
+		{`attachment; filename*=UTF-8''%E2%82%AC%20rates.pdf`, "€ rates.pdf"},
+		{`attachment; filename="rates.pdf"; filename*=utf-8''%E2%82%AC%20rates.pdf`, "€ rates.pdf"},
+		{`attachment; filename*=ISO-8859-1'en'r%E9sum%E9.pdf`, "résumé.pdf"},
+		{`attachment; filename="fallback.pdf"; filename*=KOI8-R''%C1.pdf`, "fallback.pdf"},
+		{`attachment; filename=my report.pdf`, "my report.pdf"},
+		{`attachment; filename="say \"hi\".txt"`, `say "hi".txt`},
+		{`attachment; filename="../../etc/passwd"`, "passwd"},
+		{`attachment; filename="C:\\temp\\out.pdf"`, "out.pdf"},
+		{`attachment; filename=".."`, ""},
+		{`inline`, ""},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		if got := ContentDispositionFileName(tt.header); got != tt.want {
+			t.Errorf("Expected ContentDispositionFileName(%q) = %q, got %q", tt.header, tt.want, got)
+		}
+	}
+}
+
+func TestClient_UploadFile_SanitizesFileName(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(tmpFile, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var sentName string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sentName, _ = body["file_name"].(string)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "Caf\u00e9\r\n menu.txt"}); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if sentName != "Caf\u00e9 menu.txt" {
+		t.Errorf("Expected sanitized name %q, got %q", "Caf\u00e9 menu.txt", sentName)
+	}
+
+	_, err = client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "\x00\x7f"})
+	if !IsD3ValidationError(err) {
+		t.Errorf("Expected a validation error for a name with no valid characters, got %v", err)
+	}
+}
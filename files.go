@@ -0,0 +1,376 @@
+package d3
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileInfo represents metadata of a file stored in D3
+type FileInfo struct {
+	FileKey   FileKey   `json:"file_key"`
+	FileName  string    `json:"file_name"`
+	MimeType  string    `json:"mime_type,omitempty"`
+	Size      int64     `json:"size"`
+	FolderID  string    `json:"folder_id,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// SearchOptions represents filters for searching stored files
+type SearchOptions struct {
+	Tags           []string
+	NameContains   string
+	MimeType       string
+	Ext            string
+	FolderID       string
+	UploadedAfter  time.Time
+	UploadedBefore time.Time
+	Page           int
+	PerPage        int
+}
+
+// SearchFilesResponse represents a page of file search results
+type SearchFilesResponse struct {
+	Files   []FileInfo `json:"files"`
+	Total   int        `json:"total"`
+	Page    int        `json:"page"`
+	PerPage int        `json:"per_page"`
+}
+
+// TagFile replaces the tags attached to a stored file
+func (c *Dragdropdo) TagFile(fileKey FileKey, tags []string) (*FileInfo, error) {
+	if fileKey == "" {
+		return nil, NewD3ValidationError("file_key is required", nil)
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	req := c.newRequest().
+		SetBody(map[string]interface{}{
+			"tags": tags,
+		}).
+		SetPathParam("file_key", string(fileKey))
+	resp, err := fetch[FileInfo](req, http.MethodPut, "/v1/biz/files/{file_key}/tags")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to tag file: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SetFileExpiry sets a stored file to expire ttl from now, overriding the
+// default retention. ttl is rounded up to whole seconds; a zero ttl
+// expires the file immediately.
+func (c *Dragdropdo) SetFileExpiry(fileKey FileKey, ttl time.Duration) (*FileInfo, error) {
+	var v validation
+	if fileKey == "" {
+		v.add("file_key", "file_key is required")
+	}
+	if ttl < 0 {
+		v.add("ttl", "ttl must not be negative")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	// Rounding down would turn a sub-second ttl into 0, deleting the file
+	seconds := int64(ttl / time.Second)
+	if ttl%time.Second != 0 {
+		seconds++
+	}
+	req := c.newRequest().
+		SetBody(map[string]interface{}{
+			"expires_in": seconds,
+		}).
+		SetPathParam("file_key", string(fileKey))
+	resp, err := fetch[FileInfo](req, http.MethodPut, "/v1/biz/files/{file_key}/expiry")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to set file expiry: %w", err)
+	}
+
+	return resp, nil
+}
+
+// DeleteResult represents the outcome of deleting a single file
+type DeleteResult struct {
+	FileKey FileKey
+	Err     error
+}
+
+// DeleteResults represents the outcomes of a bulk delete, in request order
+type DeleteResults []DeleteResult
+
+// Failed returns the file keys that could not be deleted
+func (r DeleteResults) Failed() []FileKey {
+	failed := []FileKey{}
+	for _, result := range r {
+		if result.Err != nil {
+			failed = append(failed, result.FileKey)
+		}
+	}
+	return failed
+}
+
+// deleteConcurrency bounds the number of concurrent requests in DeleteFiles
+const deleteConcurrency = 4
+
+// DeleteFile deletes a stored file
+func (c *Dragdropdo) DeleteFile(fileKey FileKey) error {
+	if fileKey == "" {
+		return NewD3ValidationError("file_key is required", nil)
+	}
+
+	_, err := c.newRequest().
+		SetPathParam("file_key", string(fileKey)).
+		Delete("/v1/biz/files/{file_key}")
+
+	if err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFiles deletes several stored files with bounded concurrency and
+// reports the outcome for each key, so callers can retry only the failures
+func (c *Dragdropdo) DeleteFiles(fileKeys []FileKey) DeleteResults {
+	results := make(DeleteResults, len(fileKeys))
+	sem := make(chan struct{}, deleteConcurrency)
+	var wg sync.WaitGroup
+
+	for i, fileKey := range fileKeys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, fileKey FileKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = DeleteResult{
+				FileKey: fileKey,
+				Err:     c.DeleteFile(fileKey),
+			}
+		}(i, fileKey)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// SearchFiles finds stored files matching all of the given filters
+func (c *Dragdropdo) SearchFiles(options SearchOptions) (*SearchFilesResponse, error) {
+	query := url.Values{}
+	if len(options.Tags) > 0 {
+		query.Set("tags", strings.Join(options.Tags, ","))
+	}
+	if options.NameContains != "" {
+		query.Set("name_contains", options.NameContains)
+	}
+	if options.MimeType != "" {
+		query.Set("mime_type", options.MimeType)
+	}
+	if options.Ext != "" {
+		query.Set("ext", strings.TrimPrefix(strings.ToLower(options.Ext), "."))
+	}
+	if options.FolderID != "" {
+		query.Set("folder_id", options.FolderID)
+	}
+	if !options.UploadedAfter.IsZero() {
+		query.Set("uploaded_after", options.UploadedAfter.UTC().Format(time.RFC3339))
+	}
+	if !options.UploadedBefore.IsZero() {
+		query.Set("uploaded_before", options.UploadedBefore.UTC().Format(time.RFC3339))
+	}
+	if options.Page > 0 {
+		query.Set("page", strconv.Itoa(options.Page))
+	}
+	if options.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(options.PerPage))
+	}
+
+	resp, err := fetch[SearchFilesResponse](c.newRequest().SetQueryParamsFromValues(query), http.MethodGet, "/v1/biz/files/search")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to search files: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FileIterator pages through file search results
+//
+//	it := client.IterateFiles(d3.SearchOptions{Ext: "pdf"})
+//	for it.Next() {
+//		file := it.File()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type FileIterator struct {
+	search  func(SearchOptions) (*SearchFilesResponse, error)
+	options SearchOptions
+	files   []FileInfo
+	index   int
+	seen    int
+	done    bool
+	err     error
+}
+
+// IterateFiles returns an iterator over all files matching the filters,
+// fetching PerPage results (default 100) at a time starting at Page
+func (c *Dragdropdo) IterateFiles(options SearchOptions) *FileIterator {
+	return NewFileIterator(c.SearchFiles, options)
+}
+
+// NewFileIterator returns an iterator over the pages search returns for
+// options, as IterateFiles does with SearchFiles. It lets fakes of D3API
+// implement IterateFiles.
+func NewFileIterator(search func(SearchOptions) (*SearchFilesResponse, error), options SearchOptions) *FileIterator {
+	if options.Page < 1 {
+		options.Page = 1
+	}
+	if options.PerPage < 1 {
+		options.PerPage = 100
+	}
+	return &FileIterator{
+		search:  search,
+		options: options,
+		index:   -1,
+	}
+}
+
+// Next advances to the next file, fetching the next page when needed. It
+// returns false when there are no more files or an error occurred.
+func (it *FileIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.files) {
+		if it.done {
+			return false
+		}
+		result, err := it.search(it.options)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.files = result.Files
+		it.index = 0
+		it.seen += len(result.Files)
+		it.options.Page++
+		// Prefer the reported total; fall back to a short page when the
+		// server does not report one
+		if len(result.Files) == 0 ||
+			(result.Total > 0 && it.seen >= result.Total) ||
+			(result.Total == 0 && len(result.Files) < it.options.PerPage) {
+			it.done = true
+		}
+	}
+	return true
+}
+
+// File returns the current file
+func (it *FileIterator) File() FileInfo {
+	return it.files[it.index]
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *FileIterator) Err() error {
+	return it.err
+}
+
+// StorageBucket represents the number and total size of a group of files
+type StorageBucket struct {
+	Files int
+	Bytes int64
+}
+
+// StorageAgeBucket groups files whose age is at least MinAge and less than
+// MaxAge. The last bucket has a zero MaxAge and holds everything older.
+type StorageAgeBucket struct {
+	MinAge time.Duration
+	MaxAge time.Duration
+	StorageBucket
+}
+
+// StorageStats represents aggregated storage usage
+type StorageStats struct {
+	Total  StorageBucket
+	ByType map[string]StorageBucket
+	ByAge  []StorageAgeBucket
+}
+
+// storageAgeBoundaries are the upper bounds of the StorageStats age buckets
+var storageAgeBoundaries = []time.Duration{
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	90 * 24 * time.Hour,
+}
+
+// OlderThan sums the age buckets whose files are all at least age old. The
+// result is exact when age is a bucket boundary (0, 1, 7, 30 or 90 days).
+func (s *StorageStats) OlderThan(age time.Duration) StorageBucket {
+	var sum StorageBucket
+	for _, bucket := range s.ByAge {
+		if bucket.MinAge >= age {
+			sum.Files += bucket.Files
+			sum.Bytes += bucket.Bytes
+		}
+	}
+	return sum
+}
+
+// StorageStats aggregates file counts and total bytes by MIME type and age
+// across all stored files
+func (c *Dragdropdo) StorageStats() (*StorageStats, error) {
+	stats := &StorageStats{
+		ByType: map[string]StorageBucket{},
+		ByAge:  make([]StorageAgeBucket, len(storageAgeBoundaries)+1),
+	}
+	var minAge time.Duration
+	for i, maxAge := range storageAgeBoundaries {
+		stats.ByAge[i].MinAge = minAge
+		stats.ByAge[i].MaxAge = maxAge
+		minAge = maxAge
+	}
+	stats.ByAge[len(storageAgeBoundaries)].MinAge = minAge
+
+	now := time.Now()
+	it := c.IterateFiles(SearchOptions{})
+	for it.Next() {
+		file := it.File()
+		stats.Total.Files++
+		stats.Total.Bytes += file.Size
+
+		mimeType := file.MimeType
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		byType := stats.ByType[mimeType]
+		byType.Files++
+		byType.Bytes += file.Size
+		stats.ByType[mimeType] = byType
+
+		age := now.Sub(file.CreatedAt)
+		i := 0
+		for i < len(storageAgeBoundaries) && age >= storageAgeBoundaries[i] {
+			i++
+		}
+		stats.ByAge[i].Files++
+		stats.ByAge[i].Bytes += file.Size
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,275 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_TagFile_AndSearchFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response map[string]interface{}
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/v1/biz/files/file-key-123/tags":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+				return
+			}
+			tags, _ := body["tags"].([]interface{})
+			if len(tags) != 1 || tags[0] != "invoice-batch-2024-11" {
+				t.Errorf("Unexpected tags: %v", body["tags"])
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key": "file-key-123",
+					"tags":     tags,
+				},
+			}
+		case r.Method == "GET" && r.URL.Path == "/v1/biz/files/search":
+			query := r.URL.Query()
+			if query.Get("tags") != "invoice-batch-2024-11" {
+				t.Errorf("Expected tags filter, got '%s'", query.Get("tags"))
+			}
+			if query.Get("uploaded_after") != "2024-11-01T00:00:00Z" {
+				t.Errorf("Expected uploaded_after filter, got '%s'", query.Get("uploaded_after"))
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"files": []map[string]interface{}{
+						{"file_key": "file-key-123", "file_name": "invoice.pdf", "tags": []string{"invoice-batch-2024-11"}},
+					},
+					"total": 1,
+				},
+			}
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tagged, err := client.TagFile("file-key-123", []string{"invoice-batch-2024-11"})
+	if err != nil {
+		t.Fatalf("Failed to tag file: %v", err)
+	}
+	if len(tagged.Tags) != 1 {
+		t.Errorf("Expected 1 tag, got %v", tagged.Tags)
+	}
+
+	result, err := client.SearchFiles(SearchOptions{
+		Tags:          []string{"invoice-batch-2024-11"},
+		UploadedAfter: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Failed to search files: %v", err)
+	}
+	if result.Total != 1 || result.Files[0].FileKey != "file-key-123" {
+		t.Errorf("Unexpected search result: %+v", result)
+	}
+}
+
+func TestClient_StorageStats(t *testing.T) {
+	now := time.Now()
+	pages := [][]map[string]interface{}{
+		{
+			{"file_key": "a", "mime_type": "application/pdf", "size": 100, "created_at": now.Add(-time.Hour)},
+			{"file_key": "b", "mime_type": "application/pdf", "size": 200, "created_at": now.Add(-40 * 24 * time.Hour)},
+		},
+		{
+			{"file_key": "c", "mime_type": "image/png", "size": 300, "created_at": now.Add(-100 * 24 * time.Hour)},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/biz/files/search" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		files := []map[string]interface{}{}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			files = pages[0]
+		case "2":
+			files = pages[1]
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"files": files,
+				"total": 3,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	stats, err := client.StorageStats()
+	if err != nil {
+		t.Fatalf("Failed to get storage stats: %v", err)
+	}
+
+	if stats.Total.Files != 3 || stats.Total.Bytes != 600 {
+		t.Errorf("Unexpected totals: %+v", stats.Total)
+	}
+	if stats.ByType["application/pdf"].Bytes != 300 {
+		t.Errorf("Expected 300 PDF bytes, got %d", stats.ByType["application/pdf"].Bytes)
+	}
+	old := stats.OlderThan(30 * 24 * time.Hour)
+	if old.Files != 2 || old.Bytes != 500 {
+		t.Errorf("Expected 2 files / 500 bytes older than 30 days, got %+v", old)
+	}
+}
+
+func TestClient_SetFileExpiry(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/v1/biz/files/file-key-123/expiry" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+			return
+		}
+		if body["expires_in"] != float64(7200) {
+			t.Errorf("Expected expires_in 7200, got %v", body["expires_in"])
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"file_key":   "file-key-123",
+				"expires_at": expiresAt,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	file, err := client.SetFileExpiry("file-key-123", 2*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to set file expiry: %v", err)
+	}
+	if !file.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected expires_at %v, got %v", expiresAt, file.ExpiresAt)
+	}
+
+	if _, err := client.SetFileExpiry("file-key-123", -time.Second); err == nil {
+		t.Error("Expected error for negative ttl")
+	}
+
+	// Partial seconds are rounded up, here to the 7200 the server expects,
+	// so a sub-second ttl never becomes 0 and expires the file at once
+	if _, err := client.SetFileExpiry("file-key-123", 7199*time.Second+time.Millisecond); err != nil {
+		t.Errorf("Failed to set a fractional file expiry: %v", err)
+	}
+}
+
+func TestClient_IterateFiles(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if query.Get("ext") != "pdf" || query.Get("per_page") != "2" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		files := []map[string]interface{}{}
+		switch query.Get("page") {
+		case "1":
+			files = append(files, map[string]interface{}{"file_key": "a"}, map[string]interface{}{"file_key": "b"})
+		case "2":
+			files = append(files, map[string]interface{}{"file_key": "c"})
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"files": files,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	keys := []FileKey{}
+	it := client.IterateFiles(SearchOptions{Ext: ".PDF", PerPage: 2})
+	for it.Next() {
+		keys = append(keys, it.File().FileKey)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iteration failed: %v", err)
+	}
+
+	if len(keys) != 3 || keys[2] != "c" {
+		t.Errorf("Expected keys [a b c], got %v", keys)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestClient_DeleteFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected DELETE, got %s", r.Method)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{}})
+	}))
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	results := client.DeleteFiles([]FileKey{"a", "", "c"})
+	server.Close()
+
+	if len(results) != 3 || results[0].FileKey != "a" || results[2].FileKey != "c" {
+		t.Fatalf("Expected results in request order, got %+v", results)
+	}
+	failed := results.Failed()
+	if len(failed) != 1 || failed[0] != "" {
+		t.Errorf("Expected only the empty key to fail, got %v", failed)
+	}
+}
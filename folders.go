@@ -0,0 +1,118 @@
+package d3
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Folder represents a folder in D3 storage
+type Folder struct {
+	FolderID  string    `json:"folder_id"`
+	Name      string    `json:"name"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// CreateFolderOptions represents options for creating a folder
+type CreateFolderOptions struct {
+	Name     string
+	ParentID string
+}
+
+// MoveFileOptions represents options for moving a file into a folder
+type MoveFileOptions struct {
+	FileKey  FileKey
+	FolderID string
+}
+
+// ListFolderOptions represents options for listing a folder
+type ListFolderOptions struct {
+	FolderID string
+	Page     int
+	PerPage  int
+}
+
+// ListFolderResponse represents the contents of a folder
+type ListFolderResponse struct {
+	Folder  Folder     `json:"folder"`
+	Folders []Folder   `json:"folders"`
+	Files   []FileInfo `json:"files"`
+	Total   int        `json:"total"`
+	Page    int        `json:"page"`
+	PerPage int        `json:"per_page"`
+}
+
+// CreateFolder creates a folder, optionally nested under ParentID
+func (c *Dragdropdo) CreateFolder(options CreateFolderOptions) (*Folder, error) {
+	if options.Name == "" {
+		return nil, NewD3ValidationError("folder name is required", nil)
+	}
+
+	body := map[string]interface{}{
+		"name": options.Name,
+	}
+	if options.ParentID != "" {
+		body["parent_id"] = options.ParentID
+	}
+
+	resp, err := fetch[Folder](c.newRequest().SetBody(body), http.MethodPost, "/v1/biz/folders")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	return resp, nil
+}
+
+// MoveFile moves a stored file into a folder. An empty FolderID moves the
+// file back to the root.
+func (c *Dragdropdo) MoveFile(options MoveFileOptions) (*FileInfo, error) {
+	if options.FileKey == "" {
+		return nil, NewD3ValidationError("file_key is required", nil)
+	}
+
+	req := c.newRequest().
+		SetBody(map[string]interface{}{
+			"file_key":  options.FileKey,
+			"folder_id": options.FolderID,
+		})
+	resp, err := fetch[FileInfo](req, http.MethodPost, "/v1/biz/files/move")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to move file: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListFolder lists the sub-folders and files of a folder. An empty FolderID
+// lists the root.
+func (c *Dragdropdo) ListFolder(options ListFolderOptions) (*ListFolderResponse, error) {
+	route := "/v1/biz/folders"
+	if options.FolderID != "" {
+		route += "/{folder_id}"
+	}
+
+	query := url.Values{}
+	if options.Page > 0 {
+		query.Set("page", strconv.Itoa(options.Page))
+	}
+	if options.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(options.PerPage))
+	}
+
+	req := c.newRequest().
+		SetPathParam("folder_id", options.FolderID).
+		SetQueryParamsFromValues(query)
+	resp, err := fetch[ListFolderResponse](req, http.MethodGet, route)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folder: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,101 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Folders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response map[string]interface{}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/biz/folders":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+				return
+			}
+			if body["name"] != "tenant-a" {
+				t.Errorf("Expected name 'tenant-a', got '%v'", body["name"])
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"folder_id": "folder-1",
+					"name":      "tenant-a",
+				},
+			}
+		case r.Method == "POST" && r.URL.Path == "/v1/biz/files/move":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+				return
+			}
+			if body["folder_id"] != "folder-1" {
+				t.Errorf("Expected folder_id 'folder-1', got '%v'", body["folder_id"])
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":  body["file_key"],
+					"folder_id": "folder-1",
+				},
+			}
+		case r.Method == "GET" && r.URL.Path == "/v1/biz/folders/folder-1":
+			if r.URL.Query().Get("page") != "2" {
+				t.Errorf("Expected page '2', got '%s'", r.URL.Query().Get("page"))
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"folder": map[string]interface{}{"folder_id": "folder-1", "name": "tenant-a"},
+					"files": []map[string]interface{}{
+						{"file_key": "file-key-123", "file_name": "a.pdf", "size": 42},
+					},
+					"total": 1,
+				},
+			}
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	folder, err := client.CreateFolder(CreateFolderOptions{Name: "tenant-a"})
+	if err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	if folder.FolderID != "folder-1" {
+		t.Errorf("Expected folder_id 'folder-1', got '%s'", folder.FolderID)
+	}
+
+	moved, err := client.MoveFile(MoveFileOptions{FileKey: "file-key-123", FolderID: folder.FolderID})
+	if err != nil {
+		t.Fatalf("Failed to move file: %v", err)
+	}
+	if moved.FolderID != "folder-1" {
+		t.Errorf("Expected folder_id 'folder-1', got '%s'", moved.FolderID)
+	}
+
+	listing, err := client.ListFolder(ListFolderOptions{FolderID: folder.FolderID, Page: 2})
+	if err != nil {
+		t.Fatalf("Failed to list folder: %v", err)
+	}
+	if len(listing.Files) != 1 || listing.Files[0].Size != 42 {
+		t.Errorf("Unexpected folder listing: %+v", listing.Files)
+	}
+
+	if _, err := client.CreateFolder(CreateFolderOptions{}); err == nil {
+		t.Error("Expected error for missing folder name")
+	}
+}
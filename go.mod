@@ -1,7 +1,34 @@
 module github.com/dragdropdo/dragdropdo-sdk-go
 
-go 1.19
+go 1.21
 
-require github.com/go-resty/resty/v2 v2.11.0
+require (
+	github.com/alicebob/miniredis/v2 v2.33.0
+	github.com/go-resty/resty/v2 v2.11.0
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/prometheus/client_golang v1.19.1
+	github.com/redis/go-redis/v9 v9.17.2
+	github.com/spf13/cobra v1.8.0
+	go.etcd.io/bbolt v1.3.10
+	go.uber.org/mock v0.4.0
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-require golang.org/x/net v0.17.0 // indirect
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/prometheus/client_model v0.5.0 // indirect
+	github.com/prometheus/common v0.48.0 // indirect
+	github.com/prometheus/procfs v0.12.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+)
@@ -0,0 +1,300 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxClockSkew is the largest difference between the local clock and the
+// API's for which HealthCheck passes the clock check. Storage rejects
+// presigned requests signed with a clock that is too far off.
+const MaxClockSkew = 5 * time.Minute
+
+// Checks run by HealthCheck, in order
+const (
+	// HealthCheckAPI checks that the base URL answers
+	HealthCheckAPI = "api"
+	// HealthCheckClock compares the local clock with the API's Date header
+	HealthCheckClock = "clock"
+	// HealthCheckAuth checks that the API key is accepted, see Ping
+	HealthCheckAuth = "auth"
+	// HealthCheckStorage checks that presigned storage URLs accept uploads
+	HealthCheckStorage = "storage"
+)
+
+// healthProbeName is the file name of the upload started by the storage
+// check
+const healthProbeName = "d3-health-check.txt"
+
+// HealthCheckResult is the outcome of one check of a HealthReport
+type HealthCheckResult struct {
+	Name string
+	OK   bool
+	// Skipped is set when the check could not run because an earlier
+	// check failed
+	Skipped bool
+	// Detail describes what was found, e.g. the status code and latency
+	Detail string
+	// Hint suggests how to fix a failed check
+	Hint    string
+	Latency time.Duration
+	Err     error
+}
+
+// HealthReport is the result of HealthCheck
+type HealthReport struct {
+	BaseURL string
+	// ClockSkew is the API's clock minus the local clock, 0 if unknown
+	ClockSkew time.Duration
+	Checks    []HealthCheckResult
+}
+
+// OK reports whether every check passed
+func (r *HealthReport) OK() bool {
+	for _, check := range r.Checks {
+		if !check.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns the errors of the failed checks, or nil if all passed
+func (r *HealthReport) Err() error {
+	var errs []error
+	for _, check := range r.Checks {
+		if !check.OK && !check.Skipped {
+			err := check.Err
+			if err == nil {
+				err = errors.New(check.Detail)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", check.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Ping makes a cheap authenticated call to check that the API is reachable
+// and accepts the API key, e.g. to gate a service's startup. Failures wrap
+// ErrUnauthorized for a rejected key (401 or 403), ErrUnreachable when no
+// response was received and ErrServiceUnavailable for an outage (429 or
+// 5xx), along with the underlying error. Errors of ctx are returned as is.
+func (c *Dragdropdo) Ping(ctx context.Context) error {
+	_, err := c.newRequest().
+		SetContext(ctx).
+		Get("/v1/biz/usage")
+
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return fmt.Errorf("ping failed: %w: %w", ErrUnreachable, err)
+	}
+	var apiErr *D3APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode != nil {
+		switch code := *apiErr.StatusCode; {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			return fmt.Errorf("ping failed: %w: %w", ErrUnauthorized, err)
+		case code == http.StatusTooManyRequests || code >= 500:
+			return fmt.Errorf("ping failed: %w: %w", ErrServiceUnavailable, err)
+		}
+	}
+	return fmt.Errorf("ping failed: %w", err)
+}
+
+// HealthCheck diagnoses the most common setup failures: an unreachable base
+// URL, a local clock too far from the API's, a rejected API key and
+// unreachable presigned storage. The storage check starts a one-byte upload,
+// uploads its part and aborts it. Checks that depend on a failed check are
+// skipped. The returned error is the report's Err.
+func (c *Dragdropdo) HealthCheck(ctx context.Context) (*HealthReport, error) {
+	_, baseURL := c.endpoints.pick()
+	report := &HealthReport{BaseURL: baseURL}
+
+	api, date := c.checkAPI(ctx, baseURL)
+	report.Checks = append(report.Checks, api)
+
+	clock := HealthCheckResult{Name: HealthCheckClock, Skipped: !api.OK}
+	if api.OK {
+		clock, report.ClockSkew = checkClock(date, api.Latency)
+	}
+	report.Checks = append(report.Checks, clock)
+
+	auth := HealthCheckResult{Name: HealthCheckAuth, Skipped: !api.OK}
+	if api.OK {
+		auth = c.checkAuth(ctx)
+	}
+	report.Checks = append(report.Checks, auth)
+
+	storage := HealthCheckResult{Name: HealthCheckStorage, Skipped: !auth.OK}
+	if auth.OK {
+		storage = c.checkStorage(ctx)
+	}
+	report.Checks = append(report.Checks, storage)
+
+	for i := range report.Checks {
+		if report.Checks[i].Skipped {
+			report.Checks[i].Detail = "skipped after an earlier failure"
+		}
+	}
+	return report, report.Err()
+}
+
+// checkAPI requests the base URL without credentials; any HTTP response
+// shows it is reachable. It returns the response's Date header.
+func (c *Dragdropdo) checkAPI(ctx context.Context, baseURL string) (HealthCheckResult, string) {
+	result := HealthCheckResult{Name: HealthCheckAPI}
+	_, timeout := c.settings()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.transport.DoJSON(ctx, http.MethodGet, baseURL, http.Header{"User-Agent": {c.userAgent}}, nil)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		result.Detail = fmt.Sprintf("%s is unreachable: %v", redactURL(baseURL), err)
+		result.Hint = "check the base URL (BaseURL or D3_BASE_URL), DNS, proxy settings (HTTPS_PROXY) and that outbound HTTPS is allowed"
+		return result, ""
+	}
+	result.OK = true
+	result.Detail = fmt.Sprintf("%s answered %d in %s", redactURL(baseURL), resp.StatusCode, result.Latency.Round(time.Millisecond))
+	return result, resp.Header.Get("Date")
+}
+
+// checkClock compares date, the API's Date header on a response that took
+// latency, with the local clock and returns the skew
+func checkClock(date string, latency time.Duration) (HealthCheckResult, time.Duration) {
+	result := HealthCheckResult{Name: HealthCheckClock, OK: true}
+	server, err := http.ParseTime(date)
+	if err != nil {
+		result.Detail = "the API sent no Date header, clock not checked"
+		return result, 0
+	}
+
+	// The Date header has one-second precision and was set mid-request
+	skew := server.Sub(time.Now().Add(-latency / 2)).Truncate(time.Second)
+	result.Detail = fmt.Sprintf("local clock is %s from the API's", formatSkew(skew))
+	if skew > MaxClockSkew || skew < -MaxClockSkew {
+		result.OK = false
+		result.Err = fmt.Errorf("clock skew of %s exceeds %s", formatSkew(skew), MaxClockSkew)
+		result.Hint = "synchronize the system clock (e.g. enable NTP); storage rejects presigned uploads signed with a skewed clock"
+	}
+	return result, skew
+}
+
+// formatSkew describes skew from the local clock's point of view
+func formatSkew(skew time.Duration) string {
+	switch {
+	case skew > 0:
+		return skew.String() + " behind"
+	case skew < 0:
+		return (-skew).String() + " ahead"
+	}
+	return "0s"
+}
+
+// checkAuth pings the API with the client's credentials
+func (c *Dragdropdo) checkAuth(ctx context.Context) HealthCheckResult {
+	result := HealthCheckResult{Name: HealthCheckAuth}
+	start := time.Now()
+	err := c.Ping(ctx)
+	result.Latency = time.Since(start)
+	if err == nil {
+		result.OK = true
+		result.Detail = "API key accepted"
+		return result
+	}
+
+	result.Err = err
+	result.Detail = err.Error()
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		result.Detail = "API key rejected"
+		result.Hint = "check the API key (APIKey or D3_API_KEY) and that it belongs to the environment of the base URL"
+	case errors.Is(err, ErrServiceUnavailable):
+		result.Hint = "the API is unavailable; retry later"
+	case errors.Is(err, ErrUnreachable):
+		result.Hint = "check the base URL, DNS, proxy settings (HTTPS_PROXY) and that outbound HTTPS is allowed"
+	default:
+		result.Hint = "see the error for details"
+	}
+	return result
+}
+
+// checkStorage starts a one-byte upload, uploads its part to the presigned
+// URL and aborts the upload
+func (c *Dragdropdo) checkStorage(ctx context.Context) HealthCheckResult {
+	result := HealthCheckResult{Name: HealthCheckStorage}
+
+	initiate := c.newRequest().
+		SetContext(ctx).
+		SetBody(map[string]interface{}{
+			"file_name": healthProbeName,
+			"size":      1,
+			"mime_type": "text/plain",
+			"parts":     1,
+		})
+	upload, err := fetch[UploadResponse](initiate, http.MethodPost, "/v1/biz/initiate-upload")
+	if err != nil {
+		result.Err = fmt.Errorf("failed to request presigned URLs: %w", err)
+		result.Detail = result.Err.Error()
+		result.Hint = "the API key may not be allowed to upload; see the error for details"
+		return result
+	}
+	if len(upload.PresignedURLs) == 0 {
+		result.Err = errors.New("no presigned URL received")
+		result.Detail = result.Err.Error()
+		return result
+	}
+	defer c.abortUpload(context.WithoutCancel(ctx), AbortUploadOptions{
+		FileKey:    upload.FileKey,
+		UploadID:   upload.UploadID,
+		ObjectName: upload.ObjectName,
+	})
+
+	storageHost := upload.PresignedURLs[0]
+	if u, err := url.Parse(storageHost); err == nil {
+		storageHost = u.Host
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain")
+	header.Set("User-Agent", c.userAgent)
+	for k, v := range c.sseHeaders {
+		header.Set(k, v)
+	}
+
+	start := time.Now()
+	partResp, err := c.transport.PutPart(ctx, upload.PresignedURLs[0], header, []byte{'\n'})
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		result.Detail = fmt.Sprintf("storage at %s is unreachable: %v", storageHost, err)
+		result.Hint = fmt.Sprintf("allow outbound HTTPS to %s, which presigned uploads are sent to directly", storageHost)
+		return result
+	}
+	if !partResp.IsSuccess() {
+		result.Err = newPartUploadError(1, upload.PresignedURLs[0], partResp)
+		result.Detail = result.Err.Error()
+		if strings.Contains(result.Detail, "RequestTimeTooSkewed") {
+			result.Hint = "synchronize the system clock (e.g. enable NTP)"
+		} else {
+			result.Hint = "a proxy or firewall may be rewriting requests to storage"
+		}
+		return result
+	}
+
+	result.OK = true
+	result.Detail = fmt.Sprintf("%s accepted an upload in %s", storageHost, result.Latency.Round(time.Millisecond))
+	return result
+}
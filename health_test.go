@@ -0,0 +1,178 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newHealthServer fakes the endpoints used by HealthCheck. It answers with
+// date as its Date header and accepts apiKey.
+func newHealthServer(t *testing.T, apiKey string, date time.Time) (*httptest.Server, *int32) {
+	t.Helper()
+	var aborts int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", date.UTC().Format(http.TimeFormat))
+		if r.URL.Path == "/" || r.URL.Path == "/part/1" {
+			w.Header().Set("ETag", `"etag"`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+apiKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success": false, "message": "invalid API key"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/biz/usage":
+			w.Write([]byte(`{"data": {}}`))
+		case "/v1/biz/initiate-upload":
+			w.Write([]byte(`{"data": {"file_key": "probe", "upload_id": "up-1", "presigned_urls": ["` + server.URL + `/part/1"]}}`))
+		case "/v1/biz/abort-upload":
+			atomic.AddInt32(&aborts, 1)
+			w.Write([]byte(`{"data": {}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &aborts
+}
+
+func TestClient_Ping(t *testing.T) {
+	server, _ := newHealthServer(t, "test-key", time.Now())
+
+	client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Expected ping to succeed, got %v", err)
+	}
+
+	client, _ = NewDragdropdo(Config{APIKey: "wrong-key", BaseURL: server.URL})
+	if err := client.Ping(context.Background()); !IsD3APIError(err) {
+		t.Errorf("Expected an API error for a rejected key, got %v", err)
+	}
+}
+
+func TestClient_HealthCheck(t *testing.T) {
+	server, aborts := newHealthServer(t, "test-key", time.Now())
+	client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+
+	report, err := client.HealthCheck(context.Background())
+	if err != nil || !report.OK() {
+		t.Fatalf("Expected all checks to pass, got %v: %+v", err, report.Checks)
+	}
+	var names []string
+	for _, check := range report.Checks {
+		names = append(names, check.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,clock,auth,storage" {
+		t.Errorf("Expected checks api,clock,auth,storage, got %s", got)
+	}
+	if *aborts != 1 {
+		t.Errorf("Expected the probe upload to be aborted, got %d aborts", *aborts)
+	}
+}
+
+func TestClient_HealthCheckFailures(t *testing.T) {
+	t.Run("rejected key", func(t *testing.T) {
+		server, _ := newHealthServer(t, "test-key", time.Now())
+		client, _ := NewDragdropdo(Config{APIKey: "wrong-key", BaseURL: server.URL})
+
+		report, err := client.HealthCheck(context.Background())
+		if err == nil || report.OK() {
+			t.Fatal("Expected the health check to fail")
+		}
+		auth, storage := report.Checks[2], report.Checks[3]
+		if auth.OK || auth.Detail != "API key rejected" || !strings.Contains(auth.Hint, "D3_API_KEY") {
+			t.Errorf("Expected an actionable auth failure, got %+v", auth)
+		}
+		if !storage.Skipped {
+			t.Errorf("Expected the storage check to be skipped, got %+v", storage)
+		}
+	})
+
+	t.Run("clock skew", func(t *testing.T) {
+		server, _ := newHealthServer(t, "test-key", time.Now().Add(-10*time.Minute))
+		client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+
+		report, _ := client.HealthCheck(context.Background())
+		clock := report.Checks[1]
+		if clock.OK || !strings.Contains(clock.Detail, "ahead") || !strings.Contains(clock.Hint, "NTP") {
+			t.Errorf("Expected a clock failure, got %+v", clock)
+		}
+		if report.ClockSkew > -9*time.Minute {
+			t.Errorf("Expected a skew of about -10m, got %s", report.ClockSkew)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server, _ := newHealthServer(t, "test-key", time.Now())
+		server.Close()
+		client, _ := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+
+		report, err := client.HealthCheck(context.Background())
+		if err == nil || !strings.HasPrefix(err.Error(), "api: ") {
+			t.Errorf("Expected only the api check to fail, got %v", err)
+		}
+		if api := report.Checks[0]; api.OK || !strings.Contains(api.Hint, "base URL") {
+			t.Errorf("Expected an actionable api failure, got %+v", api)
+		}
+		for _, check := range report.Checks[1:] {
+			if !check.Skipped {
+				t.Errorf("Expected %s to be skipped", check.Name)
+			}
+		}
+	})
+}
+
+func TestClient_PingErrors(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+	client, _ := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+		Retry:   RetryPolicy{MaxAttempts: 1},
+	})
+
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusServiceUnavailable, ErrServiceUnavailable},
+		{http.StatusTooManyRequests, ErrServiceUnavailable},
+	}
+	for _, tt := range tests {
+		status = tt.status
+		err := client.Ping(context.Background())
+		if !errors.Is(err, tt.want) || !IsD3APIError(err) {
+			t.Errorf("Expected %d to wrap %v and the API error, got %v", tt.status, tt.want, err)
+		}
+	}
+
+	status = http.StatusNotFound
+	if err := client.Ping(context.Background()); err == nil || errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrServiceUnavailable) || errors.Is(err, ErrUnreachable) {
+		t.Errorf("Expected an unclassified error for 404, got %v", err)
+	}
+
+	server.Close()
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("Expected ErrUnreachable for a closed server, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Ping(ctx); !errors.Is(err, context.Canceled) || errors.Is(err, ErrUnreachable) {
+		t.Errorf("Expected the context error as is, got %v", err)
+	}
+}
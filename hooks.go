@@ -0,0 +1,112 @@
+package d3
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes an API request about to be sent. Hooks may modify
+// Header, e.g. to add custom signatures.
+type RequestInfo struct {
+	Method string
+	// Route is the path template, e.g. /v1/biz/status/{main_task_id}, which
+	// is suitable as a low-cardinality metrics label
+	Route   string
+	Path    string
+	URL     string
+	Header  http.Header
+	Body    []byte
+	Attempt int
+}
+
+// ResponseInfo describes the outcome of an API request attempt
+type ResponseInfo struct {
+	Request    *RequestInfo
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Latency    time.Duration
+	// Err is set when the request failed before a response was received
+	Err error
+}
+
+// ErrorInfo describes a failed API call or presigned part upload, after
+// retries, just before its error is returned
+type ErrorInfo struct {
+	Method string
+	// Route is the path template of an API call; empty for part uploads
+	Route string
+	// Path is the API path, or the presigned URL without its query (and so
+	// without its signature) for part uploads
+	Path string
+	// Attempt is the number of attempts made
+	Attempt int
+	// Err is the error about to be returned, e.g. a *D3APIError
+	Err error
+}
+
+// BeforeRequestHook is called before each API request attempt. Returning an
+// error aborts the request with that error.
+type BeforeRequestHook func(*RequestInfo) error
+
+// AfterResponseHook is called after each API request attempt
+type AfterResponseHook func(*ResponseInfo)
+
+// ErrorHook is called for each failed API call or part upload
+type ErrorHook func(*ErrorInfo)
+
+// OnBeforeRequest registers a hook called before each API request attempt.
+// Hooks run in registration order and do not apply to presigned part uploads.
+func (c *Dragdropdo) OnBeforeRequest(hook BeforeRequestHook) {
+	if hook == nil {
+		return
+	}
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.beforeHooks = append(c.beforeHooks, hook)
+}
+
+// OnAfterResponse registers a hook called after each API request attempt.
+// Hooks run in registration order and do not apply to presigned part uploads.
+func (c *Dragdropdo) OnAfterResponse(hook AfterResponseHook) {
+	if hook == nil {
+		return
+	}
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.afterHooks = append(c.afterHooks, hook)
+}
+
+// OnError registers a hook called with every failed API call and presigned
+// part upload before the error is returned, for alerting or metrics in one
+// place. Validation errors raised before anything is sent and dry runs are
+// not reported.
+func (c *Dragdropdo) OnError(hook ErrorHook) {
+	if hook == nil {
+		return
+	}
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.errorHooks = append(c.errorHooks, hook)
+}
+
+// reportError runs the error hooks
+func (c *Dragdropdo) reportError(info *ErrorInfo) {
+	for _, hook := range c.getErrorHooks() {
+		hook(info)
+	}
+}
+
+// getErrorHooks returns the currently registered error hooks
+func (c *Dragdropdo) getErrorHooks() []ErrorHook {
+	c.hooksMu.RLock()
+	defer c.hooksMu.RUnlock()
+	return c.errorHooks
+}
+
+// hooks returns the currently registered hooks
+func (c *Dragdropdo) hooks() ([]BeforeRequestHook, []AfterResponseHook) {
+	c.hooksMu.RLock()
+	defer c.hooksMu.RUnlock()
+	return c.beforeHooks, c.afterHooks
+}
@@ -0,0 +1,98 @@
+package d3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Hooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature") != "signed:POST /v1/biz/supported-operation" {
+			t.Errorf("Expected signature header, got '%s'", r.Header.Get("X-Signature"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	client.OnBeforeRequest(func(req *RequestInfo) error {
+		req.Header.Set("X-Signature", "signed:"+req.Method+" "+req.Path)
+		return nil
+	})
+	var seen *ResponseInfo
+	client.OnAfterResponse(func(resp *ResponseInfo) {
+		seen = resp
+	})
+
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if seen == nil {
+		t.Fatal("Expected after-response hook to be called")
+	}
+	if seen.StatusCode != http.StatusOK || seen.Request.Path != "/v1/biz/supported-operation" || len(seen.Body) == 0 {
+		t.Errorf("Unexpected response info: %+v", seen)
+	}
+	if seen.Latency <= 0 {
+		t.Errorf("Expected positive latency, got %v", seen.Latency)
+	}
+
+	abort := errors.New("blocked by policy")
+	client.OnBeforeRequest(func(*RequestInfo) error { return abort })
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); !errors.Is(err, abort) {
+		t.Errorf("Expected hook error, got %v", err)
+	}
+}
+
+func TestClient_OnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/status/task-123":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"status service unavailable"}`))
+		default:
+			w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: func(int) time.Duration { return time.Millisecond }}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var reported []ErrorInfo
+	client.OnError(func(info *ErrorInfo) {
+		reported = append(reported, *info)
+	})
+
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Failed to check supported operation: %v", err)
+	}
+	_, statusErr := client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+
+	if len(reported) != 1 {
+		t.Fatalf("Expected one reported error, got %d", len(reported))
+	}
+	info := reported[0]
+	if info.Method != http.MethodGet || info.Route != "/v1/biz/status/{main_task_id}" || info.Path != "/v1/biz/status/task-123" || info.Attempt != 2 {
+		t.Errorf("Unexpected error info: %+v", info)
+	}
+	var apiErr *D3APIError
+	if !errors.As(info.Err, &apiErr) || !errors.Is(statusErr, info.Err) {
+		t.Errorf("Expected the returned D3APIError to be reported, got %v", info.Err)
+	}
+}
@@ -0,0 +1,27 @@
+package d3
+
+// The identifiers below are distinct types so that the compiler rejects a
+// task ID passed where a file key is expected, and vice versa. Untyped
+// string constants convert implicitly; string variables, e.g. keys read from
+// a database, need an explicit conversion such as d3.FileKey(key).
+
+// FileKey identifies a file uploaded to D3
+type FileKey string
+
+// UploadID identifies a multipart upload
+type UploadID string
+
+// MainTaskID identifies an operation
+type MainTaskID string
+
+// FileTaskID identifies the processing of one file of an operation
+type FileTaskID string
+
+// FileKeys converts keys to file keys, e.g. for OperationOptions.FileKeys
+func FileKeys(keys ...string) []FileKey {
+	fileKeys := make([]FileKey, len(keys))
+	for i, key := range keys {
+		fileKeys[i] = FileKey(key)
+	}
+	return fileKeys
+}
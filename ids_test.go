@@ -0,0 +1,21 @@
+package d3
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFileKeys(t *testing.T) {
+	keys := FileKeys("file-key-1", "file-key-2")
+	if len(keys) != 2 || keys[0] != "file-key-1" || keys[1] != "file-key-2" {
+		t.Errorf("Expected [file-key-1 file-key-2], got %v", keys)
+	}
+
+	encoded, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatalf("Failed to encode file keys: %v", err)
+	}
+	if string(encoded) != `["file-key-1","file-key-2"]` {
+		t.Errorf("Expected file keys to encode as strings, got %s", encoded)
+	}
+}
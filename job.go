@@ -0,0 +1,157 @@
+package d3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Job is a handle on a submitted operation for managing its lifecycle:
+// waiting for it, cancelling it and getting its results. It is safe for
+// concurrent use.
+type Job struct {
+	funcs JobFuncs
+	id    MainTaskID
+	// wait configures Wait; OnUpdate is called after the status is recorded
+	wait WaitOptions
+
+	mu      sync.Mutex
+	status  *StatusResponse
+	results []Result
+	err     error
+	done    bool
+}
+
+// JobFuncs are the calls a Job makes for its operation
+type JobFuncs struct {
+	// Status gets the operation's current status
+	Status func(ctx context.Context, mainTaskID MainTaskID) (*StatusResponse, error)
+	// Wait waits for the operation to finish, see WaitForOperation
+	Wait func(ctx context.Context, mainTaskID MainTaskID, opts WaitOptions) ([]Result, error)
+	// Cancel asks the API to cancel the operation
+	Cancel func(ctx context.Context, mainTaskID MainTaskID) error
+}
+
+// NewJob returns a Job for the operation mainTaskID making its calls
+// through funcs, for fakes of D3API such as d3mock.Client. Clients return
+// their own Jobs from Submit and Job.
+func NewJob(mainTaskID MainTaskID, funcs JobFuncs) *Job {
+	return &Job{funcs: funcs, id: mainTaskID}
+}
+
+// Submit creates an operation, see CreateOperation, and returns its Job
+func (c *Dragdropdo) Submit(ctx context.Context, options OperationOptions) (*Job, error) {
+	operation, err := c.createOperation(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return c.Job(operation.MainTaskID), nil
+}
+
+// Job returns the Job of an operation submitted earlier, e.g. by another
+// process
+func (c *Dragdropdo) Job(mainTaskID MainTaskID) *Job {
+	return NewJob(mainTaskID, JobFuncs{
+		Status: func(ctx context.Context, mainTaskID MainTaskID) (*StatusResponse, error) {
+			return c.getStatus(ctx, StatusOptions{MainTaskID: mainTaskID})
+		},
+		Wait:   c.WaitForOperation,
+		Cancel: c.cancelOperation,
+	})
+}
+
+// ID returns the operation's main task ID
+func (j *Job) ID() MainTaskID {
+	return j.id
+}
+
+// Status returns the last status received for the operation, nil if none
+// was yet. It is updated by Refresh and while Wait polls.
+func (j *Job) Status() *StatusResponse {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// Refresh gets the operation's current status
+func (j *Job) Refresh(ctx context.Context) (*StatusResponse, error) {
+	status, err := j.funcs.Status(ctx, j.id)
+	if err != nil {
+		return nil, err
+	}
+	j.setStatus(*status)
+	return status, nil
+}
+
+// Wait waits for the operation to finish, see WaitForOperation, with the
+// client's polling defaults unless the Job belongs to a Manager. Once it
+// has finished, Wait returns the same results at once.
+func (j *Job) Wait(ctx context.Context) ([]Result, error) {
+	if results, done, err := j.outcome(); done {
+		return results, err
+	}
+
+	options := j.wait
+	options.OnUpdate = func(status StatusResponse) {
+		j.setStatus(status)
+		if j.wait.OnUpdate != nil {
+			j.wait.OnUpdate(status)
+		}
+	}
+	results, err := j.funcs.Wait(ctx, j.id, options)
+
+	// A finished operation yields results, even with a PartialFailure;
+	// anything else, e.g. ctx ending, can be retried
+	if results == nil {
+		return nil, err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.results, j.err, j.done = results, err, true
+	return results, err
+}
+
+// Results returns what Wait returned once the operation finished, or
+// ErrJobPending before
+func (j *Job) Results() ([]Result, error) {
+	if results, done, err := j.outcome(); done {
+		return results, err
+	}
+	return nil, fmt.Errorf("%w: operation %s", ErrJobPending, j.id)
+}
+
+// Cancel asks the API to cancel the operation. Files already processed
+// keep their results; Wait then reports the operation as cancelled.
+func (j *Job) Cancel(ctx context.Context) error {
+	return j.funcs.Cancel(ctx, j.id)
+}
+
+func (j *Job) setStatus(status StatusResponse) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = &status
+}
+
+func (j *Job) outcome() ([]Result, bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.results, j.done, j.err
+}
+
+// cancelOperation cancels the operation mainTaskID using ctx
+func (c *Dragdropdo) cancelOperation(ctx context.Context, mainTaskID MainTaskID) error {
+	if mainTaskID == "" {
+		return NewD3ValidationError("main_task_id is required", nil)
+	}
+
+	_, err := c.newRequest().
+		SetContext(ctx).
+		SetPathParam("main_task_id", string(mainTaskID)).
+		Post("/v1/biz/cancel/{main_task_id}")
+
+	if err != nil {
+		return fmt.Errorf("failed to cancel operation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_Submit(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/do":
+			w.Write([]byte(`{"data": {"main_task_id": "task-123"}}`))
+		case "/v1/biz/status/task-123":
+			atomic.AddInt32(&polls, 1)
+			w.Write([]byte(`{"data": {"operation_status": "completed", "files_data": [{"file_key": "file-key-1", "status": "completed"}]}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	job, err := client.Submit(ctx, OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-1"}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if job.ID() != "task-123" {
+		t.Errorf("Expected job task-123, got %s", job.ID())
+	}
+	if job.Status() != nil {
+		t.Errorf("Expected no status before polling, got %+v", job.Status())
+	}
+	if _, err := job.Results(); !errors.Is(err, ErrJobPending) {
+		t.Errorf("Expected ErrJobPending before Wait, got %v", err)
+	}
+
+	results, err := job.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if len(results) != 1 || results[0].FileKey != "file-key-1" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+	if status := job.Status(); status == nil || status.OperationStatus != StatusCompleted {
+		t.Errorf("Expected completed status, got %+v", status)
+	}
+
+	// A finished job answers without polling again
+	if _, err := job.Wait(ctx); err != nil {
+		t.Errorf("Second Wait failed: %v", err)
+	}
+	if got, err := job.Results(); err != nil || len(got) != 1 {
+		t.Errorf("Expected the waited results, got %+v, %v", got, err)
+	}
+	if polls != 1 {
+		t.Errorf("Expected 1 poll, got %d", polls)
+	}
+}
+
+func TestJob_Cancel(t *testing.T) {
+	var cancelled bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/biz/cancel/task-123" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		cancelled = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"message": "Operation cancelled"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if err := client.Job("task-123").Cancel(context.Background()); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if !cancelled {
+		t.Error("Expected the operation to be cancelled")
+	}
+}
@@ -0,0 +1,129 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// operationContextKey marks the context of requests made on behalf of a
+// running operation such as UploadFile, which may continue while the client
+// is closing
+type operationContextKey struct{}
+
+// lifecycle tracks in-flight calls so Close can drain them
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+
+	// ctx is cancelled when Close gives up waiting for operations
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// uploads are the multipart uploads initiated but not yet completed,
+	// keyed by upload ID
+	uploads map[UploadID]AbortUploadOptions
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{
+		ctx:     ctx,
+		cancel:  cancel,
+		uploads: map[UploadID]AbortUploadOptions{},
+	}
+}
+
+// acquire registers an in-flight call, failing once the client is closed.
+// Requests made by a running operation are always allowed.
+func (l *lifecycle) acquire(ctx context.Context) (release func(), err error) {
+	if ctx.Value(operationContextKey{}) != nil {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrClientClosed
+	}
+	l.inflight.Add(1)
+	return l.inflight.Done, nil
+}
+
+// beginOperation registers a long-running operation and returns the context
+// its requests must use, which is cancelled with parent or when Close gives
+// up waiting
+func (l *lifecycle) beginOperation(parent context.Context) (context.Context, func(), error) {
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(context.WithValue(parent, operationContextKey{}, true))
+	stop := context.AfterFunc(l.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		release()
+	}, nil
+}
+
+// trackUpload records an initiated multipart upload
+func (l *lifecycle) trackUpload(upload AbortUploadOptions) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.uploads[upload.UploadID] = upload
+}
+
+// untrackUpload forgets a multipart upload that completed or failed on its own
+func (l *lifecycle) untrackUpload(uploadID UploadID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.uploads, uploadID)
+}
+
+// Close shuts the client down gracefully: new calls fail with
+// ErrClientClosed, in-flight calls, uploads and polls are waited for until ctx
+// is done, after which running uploads and polls are cancelled and the
+// multipart uploads they leave incomplete are aborted. Idle connections are
+// then closed. Close returns ctx's error if it had to cancel operations,
+// joined with any abort failures.
+func (c *Dragdropdo) Close(ctx context.Context) error {
+	l := c.life
+
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		l.inflight.Wait()
+		close(drained)
+	}()
+
+	var errs []error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+		l.cancel()
+		<-drained
+	}
+
+	l.mu.Lock()
+	uploads := l.uploads
+	l.uploads = map[UploadID]AbortUploadOptions{}
+	l.mu.Unlock()
+
+	abortCtx := context.WithValue(context.Background(), operationContextKey{}, true)
+	for _, upload := range uploads {
+		if err := c.abortUpload(abortCtx, upload); err != nil {
+			errs = append(errs, fmt.Errorf("upload %s: %w", upload.UploadID, err))
+		}
+	}
+
+	c.apiClient.CloseIdleConnections()
+	c.partClient.CloseIdleConnections()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Close_DrainsPolls(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "processing"
+		if atomic.AddInt32(&polls, 1) >= 3 {
+			status = "completed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"` + status + `"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.PollStatus(PollStatusOptions{
+			StatusOptions: StatusOptions{MainTaskID: "task-123"},
+			Interval:      20 * time.Millisecond,
+		})
+		result <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Expected graceful close, got: %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("Expected in-flight poll to finish, got: %v", err)
+	}
+
+	if _, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed after Close, got: %v", err)
+	}
+}
+
+func TestClient_Close_AbortsUploads(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "slow.bin")
+	if err := os.WriteFile(tmpFile, []byte(strings.Repeat("a", 100)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	aborted := make(chan string, 1)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case "/part/1":
+			// Hang until the client gives up
+			io.ReadAll(r.Body)
+			<-r.Context().Done()
+		case "/v1/biz/abort-upload":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			aborted <- body["upload_id"].(string)
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "slow.bin"})
+		result <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded from Close, got: %v", err)
+	}
+	if err := <-result; err == nil {
+		t.Error("Expected the cut-off upload to fail")
+	}
+
+	select {
+	case uploadID := <-aborted:
+		if uploadID != "upload-id-456" {
+			t.Errorf("Expected upload-id-456 to be aborted, got %s", uploadID)
+		}
+	default:
+		t.Error("Expected the incomplete upload to be aborted")
+	}
+}
@@ -0,0 +1,72 @@
+package d3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// sizeLimit holds the maximum upload size looked up from the plan
+type sizeLimit struct {
+	mu      sync.Mutex
+	fetched bool
+	bytes   int64
+}
+
+// MaxFileSize returns the maximum size of a file UploadFile accepts, in
+// bytes: Config.MaxFileSize if set, otherwise the plan's limit from the usage
+// endpoint when Config.CheckPlanFileSize is enabled. Zero means no limit is
+// known. The plan's limit is fetched once and then reused.
+func (c *Dragdropdo) MaxFileSize() (int64, error) {
+	return c.maxFileSize(context.Background())
+}
+
+func (c *Dragdropdo) maxFileSize(ctx context.Context) (int64, error) {
+	if c.fileSizeLimit > 0 || c.planLimit == nil {
+		return c.fileSizeLimit, nil
+	}
+
+	c.planLimit.mu.Lock()
+	defer c.planLimit.mu.Unlock()
+	// A dry run checks against the plan's limit only if it is known
+	if !c.planLimit.fetched && !c.isDryRun(ctx) {
+		usage, err := c.getUsage(ctx)
+		if err != nil {
+			return 0, err
+		}
+		c.planLimit.bytes = usage.MaxFileSize
+		c.planLimit.fetched = true
+	}
+	return c.planLimit.bytes, nil
+}
+
+// checkFileSize rejects a file larger than the maximum upload size. A failed
+// lookup of the plan's limit does not block the upload; the API still
+// enforces it.
+func (c *Dragdropdo) checkFileSize(ctx context.Context, fileName string, size int64) error {
+	limit, err := c.maxFileSize(ctx)
+	if err != nil || limit <= 0 || size <= limit {
+		return nil
+	}
+
+	e := newValidationError(ErrFileTooLarge, fmt.Sprintf("%s is %s, larger than the maximum upload size of %s", fileName, formatBytes(size), formatBytes(limit)))
+	e.Details = map[string]interface{}{
+		"size":     size,
+		"max_size": limit,
+	}
+	return e
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "4.0 GiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,75 @@
+package d3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_MaxFileSize(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(tmpFile, []byte(strings.Repeat("e", 2048)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var usageCalls, uploadCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/usage":
+			usageCalls++
+			w.Write([]byte(`{"data":{"max_file_size":1024}}`))
+		default:
+			uploadCalls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	configured, err := NewClient("test-key", WithBaseURL(server.URL), WithMaxFileSize(1000))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	_, err = configured.UploadFile(UploadFileOptions{File: tmpFile, FileName: "big.bin"})
+	var validationErr *D3ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Expected file too large validation error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "2.0 KiB") {
+		t.Errorf("Expected the size in the message, got %q", err.Error())
+	}
+
+	plan, err := NewClient("test-key", WithBaseURL(server.URL), WithPlanFileSizeCheck())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := plan.UploadFile(UploadFileOptions{File: tmpFile, FileName: "big.bin"}); !errors.Is(err, ErrFileTooLarge) {
+			t.Errorf("Expected the plan limit to reject the file, got %v", err)
+		}
+	}
+	if limit, err := plan.MaxFileSize(); err != nil || limit != 1024 {
+		t.Errorf("Expected plan limit 1024, got %d, %v", limit, err)
+	}
+	if usageCalls != 1 || uploadCalls != 0 {
+		t.Errorf("Expected one usage lookup and no upload calls, got %d and %d", usageCalls, uploadCalls)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:                    "512 B",
+		2048:                   "2.0 KiB",
+		5 * 1024 * 1024:        "5.0 MiB",
+		4 * 1024 * 1024 * 1024: "4.0 GiB",
+	}
+	for n, expected := range tests {
+		if got := formatBytes(n); got != expected {
+			t.Errorf("formatBytes(%d) = %q, expected %q", n, got, expected)
+		}
+	}
+}
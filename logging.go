@@ -0,0 +1,23 @@
+package d3
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logInfo emits a structured event at info level if a Logger is configured
+func (c *Dragdropdo) logInfo(event string, attrs ...slog.Attr) {
+	c.logEvent(slog.LevelInfo, event, attrs)
+}
+
+// logDebug emits a structured event at debug level if a Logger is configured
+func (c *Dragdropdo) logDebug(event string, attrs ...slog.Attr) {
+	c.logEvent(slog.LevelDebug, event, attrs)
+}
+
+func (c *Dragdropdo) logEvent(level slog.Level, event string, attrs []slog.Attr) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(context.Background(), level, event, attrs...)
+}
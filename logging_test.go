@@ -0,0 +1,52 @@
+package d3
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_StructuredLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/v1/biz/status/") {
+			w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	operation, err := client.Convert([]FileKey{"file-key-123"}, "png", nil)
+	if err != nil {
+		t.Fatalf("Failed to create operation: %v", err)
+	}
+	if _, err := client.PollStatus(PollStatusOptions{
+		StatusOptions: StatusOptions{MainTaskID: operation.MainTaskID},
+		Interval:      time.Millisecond,
+	}); err != nil {
+		t.Fatalf("Failed to poll status: %v", err)
+	}
+
+	output := buf.String()
+	for _, expected := range []string{
+		"msg=operation.submitted action=convert main_task_id=task-123",
+		"msg=poll.tick main_task_id=task-123 operation_status=completed",
+		"msg=poll.finished main_task_id=task-123",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log to contain %q, got: %s", expected, output)
+		}
+	}
+}
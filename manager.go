@@ -0,0 +1,410 @@
+package d3
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority orders the operations queued in a Manager: when slots are
+// contended, a higher priority runs first, and equal priorities in the order
+// submitted. Operations already running are not interrupted.
+type Priority int
+
+// Common priorities; any other value may be used as well
+const (
+	// PriorityBatch is for background work such as re-processing
+	PriorityBatch Priority = -1
+	// PriorityNormal is the priority of Submit
+	PriorityNormal Priority = 0
+	// PriorityInteractive is for operations a user is waiting on
+	PriorityInteractive Priority = 1
+)
+
+// ManagerOptions configures a Manager
+type ManagerOptions struct {
+	// MaxConcurrent is the number of operations run at once, from creation
+	// until they finish; the others wait in the queue. Defaults to
+	// DefaultManagerConcurrency.
+	MaxConcurrent int
+	// SubmissionsPerMinute caps the rate operations are created at, e.g.
+	// to Usage.RateLimit.RequestsPerMinute. Zero means no limit.
+	SubmissionsPerMinute int
+	// MaxAttempts is the number of times an operation that fails
+	// transiently, as classified by IsRetryable, is tried, including the
+	// first. Defaults to DefaultManagerAttempts.
+	MaxAttempts int
+	// Backoff returns the wait before the given retry (1 for the first
+	// retry). Defaults to ExponentialBackoff(1s, 30s).
+	Backoff func(attempt int) time.Duration
+	// Wait configures how operations are polled until they finish
+	Wait WaitOptions
+	// OnProgress is called with the aggregate progress whenever it changes
+	OnProgress func(ManagerProgress)
+	// Progress follows the operations as they finish, from the first
+	// submitted until all submitted have finished
+	Progress ProgressReporter
+}
+
+// ManagerProgress is the aggregate progress of a Manager's operations
+type ManagerProgress struct {
+	// Total is the number of operations submitted
+	Total int
+	// Queued operations wait for one of the MaxConcurrent slots
+	Queued int
+	// Running operations are being created, polled or retried
+	Running   int
+	Succeeded int
+	// Failed includes operations that finished with a PartialFailure
+	Failed int
+	// Retries is the number of retries made across all operations
+	Retries int
+}
+
+// Done reports whether every submitted operation has finished
+func (p ManagerProgress) Done() bool {
+	return p.Succeeded+p.Failed == p.Total
+}
+
+// Manager runs many operations on a client, at most MaxConcurrent at once
+// and no faster than SubmissionsPerMinute, retrying transient failures. It
+// is safe for concurrent use.
+//
+//	manager := d3.NewManager(client, d3.ManagerOptions{MaxConcurrent: 8})
+//	for _, key := range fileKeys {
+//		manager.Submit(ctx, d3.OperationOptions{...})
+//	}
+//	manager.Wait()
+type Manager struct {
+	client  *Dragdropdo
+	options ManagerOptions
+	wg      sync.WaitGroup
+	// reportMu orders OnProgress calls and guards tracker, which follows
+	// the operations for Progress
+	reportMu sync.Mutex
+	tracker  *progressTracker
+
+	mu       sync.Mutex
+	running  int
+	queue    []*ManagedOperation
+	progress ManagerProgress
+	// nextSubmit is the earliest time the next operation may be created
+	nextSubmit time.Time
+}
+
+// ManagedOperation is an operation submitted to a Manager
+type ManagedOperation struct {
+	// Options are the options the operation is created with
+	Options OperationOptions
+	// Priority orders the operation in the queue
+	Priority Priority
+
+	// ready is closed when the operation is given a slot, done when it has
+	// finished
+	ready chan struct{}
+	done  chan struct{}
+
+	mu       sync.Mutex
+	job      *Job
+	attempts int
+	results  []Result
+	err      error
+}
+
+// NewManager returns a Manager running operations on client
+func NewManager(client *Dragdropdo, options ManagerOptions) *Manager {
+	if options.MaxConcurrent < 1 {
+		options.MaxConcurrent = DefaultManagerConcurrency
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = DefaultManagerAttempts
+	}
+	if options.Backoff == nil {
+		options.Backoff = ExponentialBackoff(time.Second, 30*time.Second)
+	}
+	return &Manager{client: client, options: options}
+}
+
+// Submit queues an operation with PriorityNormal and returns at once. The
+// operation is created once a slot is free and then waited for, until it
+// finishes or ctx is done; see ManagedOperation.Wait.
+func (m *Manager) Submit(ctx context.Context, options OperationOptions) *ManagedOperation {
+	return m.SubmitPriority(ctx, options, PriorityNormal)
+}
+
+// SubmitPriority is Submit with a priority, e.g. PriorityInteractive for an
+// operation a user is waiting on
+func (m *Manager) SubmitPriority(ctx context.Context, options OperationOptions, priority Priority) *ManagedOperation {
+	op := newManagedOperation(options, priority)
+	m.enqueue(ctx, op)
+	return op
+}
+
+// Resume queues the operations recorded in the client's StateStore as
+// submitted but not yet seen to finish, e.g. by a process that crashed, with
+// PriorityNormal. They are waited for like submitted operations but not
+// created again. Without a StateStore there are none.
+func (m *Manager) Resume(ctx context.Context) ([]*ManagedOperation, error) {
+	if m.client.stateStore == nil {
+		return nil, nil
+	}
+	states, err := m.client.stateStore.LoadJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]*ManagedOperation, len(states))
+	for i, state := range states {
+		op := newManagedOperation(OperationOptions{Action: state.Action, FileKeys: state.FileKeys}, PriorityNormal)
+		op.job = m.client.Job(state.MainTaskID)
+		op.job.wait = m.options.Wait
+		m.enqueue(ctx, op)
+		ops[i] = op
+	}
+	return ops, nil
+}
+
+func newManagedOperation(options OperationOptions, priority Priority) *ManagedOperation {
+	return &ManagedOperation{
+		Options:  options,
+		Priority: priority,
+		ready:    make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// enqueue queues op and starts its goroutine
+func (m *Manager) enqueue(ctx context.Context, op *ManagedOperation) {
+	// Queue in the order submitted, before the operation's goroutine runs
+	m.mu.Lock()
+	m.progress.Total++
+	m.progress.Queued++
+	if m.running < m.options.MaxConcurrent {
+		m.running++
+		m.start(op)
+	} else {
+		m.queue = append(m.queue, op)
+	}
+	m.mu.Unlock()
+	m.reportProgress()
+
+	m.wg.Add(1)
+	go m.run(ctx, op)
+}
+
+// Wait waits for every operation submitted so far to finish
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// Progress returns the aggregate progress of the operations submitted
+func (m *Manager) Progress() ManagerProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.progress
+}
+
+func (m *Manager) run(ctx context.Context, op *ManagedOperation) {
+	defer m.wg.Done()
+
+	if err := m.acquire(ctx, op); err != nil {
+		m.mu.Lock()
+		m.progress.Queued--
+		m.progress.Failed++
+		m.mu.Unlock()
+		op.finish(nil, err)
+		m.reportProgress()
+		return
+	}
+	m.reportProgress()
+
+	results, err := m.attempt(ctx, op)
+	m.release()
+
+	m.mu.Lock()
+	m.progress.Running--
+	if err != nil {
+		m.progress.Failed++
+	} else {
+		m.progress.Succeeded++
+	}
+	m.mu.Unlock()
+	op.finish(results, err)
+	m.reportProgress()
+}
+
+// acquire waits until op is given a slot, failing if ctx is done first
+func (m *Manager) acquire(ctx context.Context, op *ManagedOperation) error {
+	select {
+	case <-op.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, queued := range m.queue {
+		if queued == op {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// The slot was handed over meanwhile; the operation runs and fails
+	// on ctx at once
+	return nil
+}
+
+// release hands the slot of a finished operation to the next queued one
+func (m *Manager) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.queue) == 0 {
+		m.running--
+		return
+	}
+	m.start(m.dequeue())
+}
+
+// dequeue removes and returns the next operation to run: the first queued
+// of the highest priority
+func (m *Manager) dequeue() *ManagedOperation {
+	next := 0
+	for i, op := range m.queue {
+		if op.Priority > m.queue[next].Priority {
+			next = i
+		}
+	}
+	op := m.queue[next]
+	m.queue = append(m.queue[:next], m.queue[next+1:]...)
+	return op
+}
+
+// start moves op from queued to running; m.mu must be held
+func (m *Manager) start(op *ManagedOperation) {
+	m.progress.Queued--
+	m.progress.Running++
+	close(op.ready)
+}
+
+// attempt runs op, retrying transient failures
+func (m *Manager) attempt(ctx context.Context, op *ManagedOperation) ([]Result, error) {
+	for attempt := 1; ; attempt++ {
+		results, err := m.try(ctx, op)
+		if err == nil || attempt >= m.options.MaxAttempts || !IsRetryable(err) || ctx.Err() != nil {
+			return results, err
+		}
+
+		m.mu.Lock()
+		m.progress.Retries++
+		m.mu.Unlock()
+		m.reportProgress()
+
+		if err := sleepContext(ctx, m.options.Backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// try creates op, unless an earlier attempt did, and waits for it
+func (m *Manager) try(ctx context.Context, op *ManagedOperation) ([]Result, error) {
+	op.mu.Lock()
+	op.attempts++
+	job := op.job
+	op.mu.Unlock()
+
+	if job == nil {
+		if err := m.throttle(ctx); err != nil {
+			return nil, err
+		}
+		var err error
+		if job, err = m.client.Submit(ctx, op.Options); err != nil {
+			return nil, err
+		}
+		job.wait = m.options.Wait
+
+		op.mu.Lock()
+		op.job = job
+		op.mu.Unlock()
+	}
+	return job.Wait(ctx)
+}
+
+// throttle waits until the next operation may be created under
+// SubmissionsPerMinute
+func (m *Manager) throttle(ctx context.Context) error {
+	if m.options.SubmissionsPerMinute <= 0 {
+		return nil
+	}
+	interval := time.Minute / time.Duration(m.options.SubmissionsPerMinute)
+
+	m.mu.Lock()
+	now := time.Now()
+	if m.nextSubmit.Before(now) {
+		m.nextSubmit = now
+	}
+	wait := m.nextSubmit.Sub(now)
+	m.nextSubmit = m.nextSubmit.Add(interval)
+	m.mu.Unlock()
+
+	return sleepContext(ctx, wait)
+}
+
+func (m *Manager) reportProgress() {
+	if m.options.OnProgress == nil && m.options.Progress == nil {
+		return
+	}
+	m.reportMu.Lock()
+	defer m.reportMu.Unlock()
+	progress := m.Progress()
+	if m.options.OnProgress != nil {
+		m.options.OnProgress(progress)
+	}
+	if m.options.Progress != nil {
+		if m.tracker == nil {
+			m.tracker = startProgress(m.options.Progress, Progress{Stage: ProgressOperations})
+		}
+		m.tracker.update(int64(progress.Succeeded+progress.Failed), int64(progress.Total), "")
+		if progress.Done() {
+			m.tracker.finish(nil)
+			m.tracker = nil
+		}
+	}
+}
+
+// Done returns a channel closed once the operation has finished
+func (op *ManagedOperation) Done() <-chan struct{} {
+	return op.done
+}
+
+// Wait waits for the operation to finish and returns its results, as
+// Job.Wait does, or ctx's error if ctx is done first
+func (op *ManagedOperation) Wait(ctx context.Context) ([]Result, error) {
+	select {
+	case <-op.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.results, op.err
+}
+
+// Job returns the operation's Job, nil until it has been created
+func (op *ManagedOperation) Job() *Job {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.job
+}
+
+// Attempts returns the number of times the operation has been tried
+func (op *ManagedOperation) Attempts() int {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.attempts
+}
+
+func (op *ManagedOperation) finish(results []Result, err error) {
+	op.mu.Lock()
+	op.results, op.err = results, err
+	op.mu.Unlock()
+	close(op.done)
+}
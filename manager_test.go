@@ -0,0 +1,258 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newOperationServer serves operations that complete on their second status
+// check. failSubmits /v1/biz/do requests fail with 503 first.
+func newOperationServer(t *testing.T, failSubmits int32) (*httptest.Server, *int32) {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	var inFlight, maxInFlight, submits, tasks int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/do":
+			if atomic.AddInt32(&submits, 1) <= failSubmits {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"message": "busy"}`))
+				return
+			}
+			if n := atomic.AddInt32(&inFlight, 1); n > atomic.LoadInt32(&maxInFlight) {
+				atomic.StoreInt32(&maxInFlight, n)
+			}
+			fmt.Fprintf(w, `{"data": {"main_task_id": "task-%d"}}`, atomic.AddInt32(&tasks, 1))
+		case strings.HasPrefix(r.URL.Path, "/v1/biz/status/"):
+			mu.Lock()
+			polls[r.URL.Path]++
+			done := polls[r.URL.Path] > 1
+			mu.Unlock()
+			if !done {
+				w.Write([]byte(`{"data": {"operation_status": "running"}}`))
+				return
+			}
+			atomic.AddInt32(&inFlight, -1)
+			w.Write([]byte(`{"data": {"operation_status": "completed", "files_data": [{"file_key": "file-key-1", "status": "completed"}]}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &maxInFlight
+}
+
+func TestManager(t *testing.T) {
+	server, maxInFlight := newOperationServer(t, 0)
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var mu sync.Mutex
+	var last ManagerProgress
+	reporter := &recordingReporter{}
+	manager := NewManager(client, ManagerOptions{
+		MaxConcurrent: 2,
+		Wait:          WaitOptions{Interval: 5 * time.Millisecond},
+		Progress:      reporter,
+		OnProgress: func(p ManagerProgress) {
+			mu.Lock()
+			last = p
+			mu.Unlock()
+		},
+	})
+
+	ctx := context.Background()
+	var ops []*ManagedOperation
+	for i := 0; i < 6; i++ {
+		ops = append(ops, manager.Submit(ctx, OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-1"}}))
+	}
+	manager.Wait()
+
+	for i, op := range ops {
+		results, err := op.Wait(ctx)
+		if err != nil || len(results) != 1 {
+			t.Errorf("Operation %d: expected 1 result, got %+v, %v", i, results, err)
+		}
+		if op.Job() == nil {
+			t.Errorf("Operation %d: expected a job", i)
+		}
+	}
+	if got := atomic.LoadInt32(maxInFlight); got > 2 {
+		t.Errorf("Expected at most 2 operations in flight, got %d", got)
+	}
+
+	want := ManagerProgress{Total: 6, Succeeded: 6}
+	if p := manager.Progress(); p != want || !p.Done() {
+		t.Errorf("Expected progress %+v, got %+v", want, p)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if last != want {
+		t.Errorf("Expected last reported progress %+v, got %+v", want, last)
+	}
+	if got := reporter.String(); !strings.HasPrefix(got, "start operations 0/0, update operations 0/1") || !strings.HasSuffix(got, "update operations 6/6, done operations 6/6") {
+		t.Errorf("Expected the operations to be reported from start to done, got %q", got)
+	}
+}
+
+func TestManager_RetriesTransientFailures(t *testing.T) {
+	server, _ := newOperationServer(t, 2)
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	manager := NewManager(client, ManagerOptions{
+		Backoff: func(int) time.Duration { return time.Millisecond },
+		Wait:    WaitOptions{Interval: 5 * time.Millisecond},
+	})
+	op := manager.Submit(context.Background(), OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-1"}})
+	if _, err := op.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	if op.Attempts() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", op.Attempts())
+	}
+	if p := manager.Progress(); p.Retries != 2 {
+		t.Errorf("Expected 2 retries, got %d", p.Retries)
+	}
+}
+
+func TestManager_GivesUp(t *testing.T) {
+	server, _ := newOperationServer(t, 10)
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	manager := NewManager(client, ManagerOptions{
+		MaxAttempts: 2,
+		Backoff:     func(int) time.Duration { return time.Millisecond },
+	})
+	op := manager.Submit(context.Background(), OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-1"}})
+	<-op.Done()
+	_, err = op.Wait(context.Background())
+	var apiErr *D3APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected D3APIError, got %v", err)
+	}
+	if op.Attempts() != 2 || op.Job() != nil {
+		t.Errorf("Expected 2 attempts and no job, got %d and %v", op.Attempts(), op.Job())
+	}
+	if p := manager.Progress(); p.Failed != 1 || !p.Done() {
+		t.Errorf("Expected 1 failed operation, got %+v", p)
+	}
+}
+
+func TestManager_CancelQueued(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"main_task_id": "task-1"}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	manager := NewManager(client, ManagerOptions{MaxConcurrent: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager.Submit(ctx, OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-1"}})
+	for manager.Progress().Running == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	queuedCtx, cancelQueued := context.WithCancel(context.Background())
+	queued := manager.Submit(queuedCtx, OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-2"}})
+	cancelQueued()
+	if _, err := queued.Wait(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled for a cancelled queued operation, got %v", err)
+	}
+	if queued.Attempts() != 0 {
+		t.Errorf("Expected a cancelled queued operation not to run, got %d attempts", queued.Attempts())
+	}
+	if p := manager.Progress(); p.Queued != 0 || p.Running != 1 || p.Failed != 1 {
+		t.Errorf("Unexpected progress: %+v", p)
+	}
+}
+
+func TestManager_Throttle(t *testing.T) {
+	manager := NewManager(nil, ManagerOptions{SubmissionsPerMinute: 600})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := manager.throttle(context.Background()); err != nil {
+			t.Fatalf("throttle failed: %v", err)
+		}
+	}
+	// 600 per minute is one every 100ms; the first goes at once
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected 3 submissions to take at least 200ms, took %v", elapsed)
+	}
+}
+
+func TestManager_Priority(t *testing.T) {
+	gate := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/biz/do" {
+			w.Write([]byte(`{"data": {"operation_status": "completed"}}`))
+			return
+		}
+		var body struct {
+			FileKeys []string `json:"file_keys"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		order = append(order, body.FileKeys[0])
+		first := len(order) == 1
+		mu.Unlock()
+		if first {
+			<-gate
+		}
+		fmt.Fprintf(w, `{"data": {"main_task_id": "task-%s"}}`, body.FileKeys[0])
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	manager := NewManager(client, ManagerOptions{MaxConcurrent: 1})
+	ctx := context.Background()
+	submit := func(key FileKey, priority Priority) {
+		manager.SubmitPriority(ctx, OperationOptions{Action: ActionZip, FileKeys: []FileKey{key}}, priority)
+	}
+	submit("running", PriorityBatch)
+	submit("batch-1", PriorityBatch)
+	submit("batch-2", PriorityBatch)
+	submit("normal", PriorityNormal)
+	submit("interactive", PriorityInteractive)
+	close(gate)
+	manager.Wait()
+
+	want := []string{"running", "interactive", "normal", "batch-1", "batch-2"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected %v, got %v", want, order)
+	}
+}
@@ -0,0 +1,24 @@
+package d3
+
+import "time"
+
+// MetricsRecorder receives client metrics. Implementations must be safe for
+// concurrent use. See the d3prom package for a Prometheus implementation.
+type MetricsRecorder interface {
+	// RecordRequest is called after each API request attempt. route is the
+	// path template, e.g. /v1/biz/status/{main_task_id}, and statusCode is 0
+	// when err is set.
+	RecordRequest(method, route string, statusCode int, latency time.Duration, err error)
+	// RecordUploadPart is called after each presigned part upload
+	RecordUploadPart(bytes int64, latency time.Duration, err error)
+	// RecordPoll is called when PollStatus finishes with the operation's
+	// final status
+	RecordPoll(status string, duration time.Duration)
+}
+
+// metricsHook records each API request attempt
+func metricsHook(metrics MetricsRecorder) AfterResponseHook {
+	return func(resp *ResponseInfo) {
+		metrics.RecordRequest(resp.Request.Method, resp.Request.Route, resp.StatusCode, resp.Latency, resp.Err)
+	}
+}
@@ -0,0 +1,62 @@
+package d3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeMetrics struct {
+	mu       sync.Mutex
+	requests []string
+	polls    []string
+}
+
+func (m *fakeMetrics) RecordRequest(method, route string, statusCode int, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, method+" "+route)
+}
+
+func (m *fakeMetrics) RecordUploadPart(bytes int64, latency time.Duration, err error) {}
+
+func (m *fakeMetrics) RecordPoll(status string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.polls = append(m.polls, status)
+}
+
+func TestClient_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/v1/biz/status/") {
+			w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	metrics := &fakeMetrics{}
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.PollStatus(PollStatusOptions{
+		StatusOptions: StatusOptions{MainTaskID: "task-123"},
+		Interval:      time.Millisecond,
+	}); err != nil {
+		t.Fatalf("Failed to poll status: %v", err)
+	}
+
+	if len(metrics.requests) != 1 || metrics.requests[0] != "GET /v1/biz/status/{main_task_id}" {
+		t.Errorf("Expected request recorded by route, got %v", metrics.requests)
+	}
+	if len(metrics.polls) != 1 || metrics.polls[0] != "completed" {
+		t.Errorf("Expected completed poll recorded, got %v", metrics.polls)
+	}
+}
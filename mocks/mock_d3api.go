@@ -0,0 +1,648 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api.go
+//
+// Generated by this command:
+//
+//	mockgen -source=api.go -destination=mocks/mock_d3api.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dragdropdo_sdk_go "github.com/dragdropdo/dragdropdo-sdk-go"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockD3API is a mock of D3API interface.
+type MockD3API struct {
+	ctrl     *gomock.Controller
+	recorder *MockD3APIMockRecorder
+}
+
+// MockD3APIMockRecorder is the mock recorder for MockD3API.
+type MockD3APIMockRecorder struct {
+	mock *MockD3API
+}
+
+// NewMockD3API creates a new mock instance.
+func NewMockD3API(ctrl *gomock.Controller) *MockD3API {
+	mock := &MockD3API{ctrl: ctrl}
+	mock.recorder = &MockD3APIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockD3API) EXPECT() *MockD3APIMockRecorder {
+	return m.recorder
+}
+
+// AbortStaleUploads mocks base method.
+func (m *MockD3API) AbortStaleUploads(olderThan time.Duration) ([]dragdropdo_sdk_go.PendingUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortStaleUploads", olderThan)
+	ret0, _ := ret[0].([]dragdropdo_sdk_go.PendingUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AbortStaleUploads indicates an expected call of AbortStaleUploads.
+func (mr *MockD3APIMockRecorder) AbortStaleUploads(olderThan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortStaleUploads", reflect.TypeOf((*MockD3API)(nil).AbortStaleUploads), olderThan)
+}
+
+// AbortUpload mocks base method.
+func (m *MockD3API) AbortUpload(options dragdropdo_sdk_go.AbortUploadOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortUpload", options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortUpload indicates an expected call of AbortUpload.
+func (mr *MockD3APIMockRecorder) AbortUpload(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortUpload", reflect.TypeOf((*MockD3API)(nil).AbortUpload), options)
+}
+
+// Capabilities mocks base method.
+func (m *MockD3API) Capabilities() *dragdropdo_sdk_go.OperationCatalog {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities")
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationCatalog)
+	return ret0
+}
+
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockD3APIMockRecorder) Capabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockD3API)(nil).Capabilities))
+}
+
+// CheckSupportedOperation mocks base method.
+func (m *MockD3API) CheckSupportedOperation(options dragdropdo_sdk_go.SupportedOperationOptions) (*dragdropdo_sdk_go.SupportedOperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSupportedOperation", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.SupportedOperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckSupportedOperation indicates an expected call of CheckSupportedOperation.
+func (mr *MockD3APIMockRecorder) CheckSupportedOperation(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSupportedOperation", reflect.TypeOf((*MockD3API)(nil).CheckSupportedOperation), options)
+}
+
+// Close mocks base method.
+func (m *MockD3API) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockD3APIMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockD3API)(nil).Close), ctx)
+}
+
+// Compress mocks base method.
+func (m *MockD3API) Compress(fileKeys []dragdropdo_sdk_go.FileKey, compressionValue string, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compress", fileKeys, compressionValue, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compress indicates an expected call of Compress.
+func (mr *MockD3APIMockRecorder) Compress(fileKeys, compressionValue, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compress", reflect.TypeOf((*MockD3API)(nil).Compress), fileKeys, compressionValue, notes)
+}
+
+// Convert mocks base method.
+func (m *MockD3API) Convert(fileKeys []dragdropdo_sdk_go.FileKey, convertTo string, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Convert", fileKeys, convertTo, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Convert indicates an expected call of Convert.
+func (mr *MockD3APIMockRecorder) Convert(fileKeys, convertTo, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Convert", reflect.TypeOf((*MockD3API)(nil).Convert), fileKeys, convertTo, notes)
+}
+
+// CreateFolder mocks base method.
+func (m *MockD3API) CreateFolder(options dragdropdo_sdk_go.CreateFolderOptions) (*dragdropdo_sdk_go.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockD3APIMockRecorder) CreateFolder(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockD3API)(nil).CreateFolder), options)
+}
+
+// CreateOperation mocks base method.
+func (m *MockD3API) CreateOperation(options dragdropdo_sdk_go.OperationOptions) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOperation", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOperation indicates an expected call of CreateOperation.
+func (mr *MockD3APIMockRecorder) CreateOperation(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOperation", reflect.TypeOf((*MockD3API)(nil).CreateOperation), options)
+}
+
+// CreateShareLink mocks base method.
+func (m *MockD3API) CreateShareLink(fileKey dragdropdo_sdk_go.FileKey, options dragdropdo_sdk_go.ShareOptions) (*dragdropdo_sdk_go.ShareLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShareLink", fileKey, options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.ShareLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShareLink indicates an expected call of CreateShareLink.
+func (mr *MockD3APIMockRecorder) CreateShareLink(fileKey, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShareLink", reflect.TypeOf((*MockD3API)(nil).CreateShareLink), fileKey, options)
+}
+
+// DeleteFile mocks base method.
+func (m *MockD3API) DeleteFile(fileKey dragdropdo_sdk_go.FileKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", fileKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockD3APIMockRecorder) DeleteFile(fileKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockD3API)(nil).DeleteFile), fileKey)
+}
+
+// DeleteFiles mocks base method.
+func (m *MockD3API) DeleteFiles(fileKeys []dragdropdo_sdk_go.FileKey) dragdropdo_sdk_go.DeleteResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFiles", fileKeys)
+	ret0, _ := ret[0].(dragdropdo_sdk_go.DeleteResults)
+	return ret0
+}
+
+// DeleteFiles indicates an expected call of DeleteFiles.
+func (mr *MockD3APIMockRecorder) DeleteFiles(fileKeys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFiles", reflect.TypeOf((*MockD3API)(nil).DeleteFiles), fileKeys)
+}
+
+// GetStatus mocks base method.
+func (m *MockD3API) GetStatus(options dragdropdo_sdk_go.StatusOptions) (*dragdropdo_sdk_go.StatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.StatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockD3APIMockRecorder) GetStatus(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockD3API)(nil).GetStatus), options)
+}
+
+// GetSupportedOperations mocks base method.
+func (m *MockD3API) GetSupportedOperations() (*dragdropdo_sdk_go.OperationCatalog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupportedOperations")
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationCatalog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupportedOperations indicates an expected call of GetSupportedOperations.
+func (mr *MockD3APIMockRecorder) GetSupportedOperations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupportedOperations", reflect.TypeOf((*MockD3API)(nil).GetSupportedOperations))
+}
+
+// GetUsage mocks base method.
+func (m *MockD3API) GetUsage() (*dragdropdo_sdk_go.Usage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsage")
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.Usage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsage indicates an expected call of GetUsage.
+func (mr *MockD3APIMockRecorder) GetUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsage", reflect.TypeOf((*MockD3API)(nil).GetUsage))
+}
+
+// HealthCheck mocks base method.
+func (m *MockD3API) HealthCheck(ctx context.Context) (*dragdropdo_sdk_go.HealthReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", ctx)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.HealthReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockD3APIMockRecorder) HealthCheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockD3API)(nil).HealthCheck), ctx)
+}
+
+// IterateFiles mocks base method.
+func (m *MockD3API) IterateFiles(options dragdropdo_sdk_go.SearchOptions) *dragdropdo_sdk_go.FileIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateFiles", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.FileIterator)
+	return ret0
+}
+
+// IterateFiles indicates an expected call of IterateFiles.
+func (mr *MockD3APIMockRecorder) IterateFiles(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateFiles", reflect.TypeOf((*MockD3API)(nil).IterateFiles), options)
+}
+
+// Job mocks base method.
+func (m *MockD3API) Job(mainTaskID dragdropdo_sdk_go.MainTaskID) *dragdropdo_sdk_go.Job {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Job", mainTaskID)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.Job)
+	return ret0
+}
+
+// Job indicates an expected call of Job.
+func (mr *MockD3APIMockRecorder) Job(mainTaskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Job", reflect.TypeOf((*MockD3API)(nil).Job), mainTaskID)
+}
+
+// ListFolder mocks base method.
+func (m *MockD3API) ListFolder(options dragdropdo_sdk_go.ListFolderOptions) (*dragdropdo_sdk_go.ListFolderResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFolder", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.ListFolderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFolder indicates an expected call of ListFolder.
+func (mr *MockD3APIMockRecorder) ListFolder(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFolder", reflect.TypeOf((*MockD3API)(nil).ListFolder), options)
+}
+
+// ListPendingUploads mocks base method.
+func (m *MockD3API) ListPendingUploads() ([]dragdropdo_sdk_go.PendingUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingUploads")
+	ret0, _ := ret[0].([]dragdropdo_sdk_go.PendingUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingUploads indicates an expected call of ListPendingUploads.
+func (mr *MockD3APIMockRecorder) ListPendingUploads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingUploads", reflect.TypeOf((*MockD3API)(nil).ListPendingUploads))
+}
+
+// LockPdf mocks base method.
+func (m *MockD3API) LockPdf(fileKeys []dragdropdo_sdk_go.FileKey, password string, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPdf", fileKeys, password, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockPdf indicates an expected call of LockPdf.
+func (mr *MockD3APIMockRecorder) LockPdf(fileKeys, password, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPdf", reflect.TypeOf((*MockD3API)(nil).LockPdf), fileKeys, password, notes)
+}
+
+// MaxFileSize mocks base method.
+func (m *MockD3API) MaxFileSize() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxFileSize")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaxFileSize indicates an expected call of MaxFileSize.
+func (mr *MockD3APIMockRecorder) MaxFileSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxFileSize", reflect.TypeOf((*MockD3API)(nil).MaxFileSize))
+}
+
+// Merge mocks base method.
+func (m *MockD3API) Merge(fileKeys []dragdropdo_sdk_go.FileKey, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", fileKeys, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockD3APIMockRecorder) Merge(fileKeys, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockD3API)(nil).Merge), fileKeys, notes)
+}
+
+// MoveFile mocks base method.
+func (m *MockD3API) MoveFile(options dragdropdo_sdk_go.MoveFileOptions) (*dragdropdo_sdk_go.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFile", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveFile indicates an expected call of MoveFile.
+func (mr *MockD3APIMockRecorder) MoveFile(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFile", reflect.TypeOf((*MockD3API)(nil).MoveFile), options)
+}
+
+// PendingJobs mocks base method.
+func (m *MockD3API) PendingJobs(ctx context.Context) ([]*dragdropdo_sdk_go.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingJobs", ctx)
+	ret0, _ := ret[0].([]*dragdropdo_sdk_go.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingJobs indicates an expected call of PendingJobs.
+func (mr *MockD3APIMockRecorder) PendingJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingJobs", reflect.TypeOf((*MockD3API)(nil).PendingJobs), ctx)
+}
+
+// Ping mocks base method.
+func (m *MockD3API) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockD3APIMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockD3API)(nil).Ping), ctx)
+}
+
+// PollStatus mocks base method.
+func (m *MockD3API) PollStatus(options dragdropdo_sdk_go.PollStatusOptions) (*dragdropdo_sdk_go.StatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollStatus", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.StatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PollStatus indicates an expected call of PollStatus.
+func (mr *MockD3APIMockRecorder) PollStatus(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollStatus", reflect.TypeOf((*MockD3API)(nil).PollStatus), options)
+}
+
+// RefreshCapabilities mocks base method.
+func (m *MockD3API) RefreshCapabilities(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCapabilities", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshCapabilities indicates an expected call of RefreshCapabilities.
+func (mr *MockD3APIMockRecorder) RefreshCapabilities(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCapabilities", reflect.TypeOf((*MockD3API)(nil).RefreshCapabilities), ctx)
+}
+
+// ResetPdfPassword mocks base method.
+func (m *MockD3API) ResetPdfPassword(fileKeys []dragdropdo_sdk_go.FileKey, oldPassword, newPassword string, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPdfPassword", fileKeys, oldPassword, newPassword, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPdfPassword indicates an expected call of ResetPdfPassword.
+func (mr *MockD3APIMockRecorder) ResetPdfPassword(fileKeys, oldPassword, newPassword, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPdfPassword", reflect.TypeOf((*MockD3API)(nil).ResetPdfPassword), fileKeys, oldPassword, newPassword, notes)
+}
+
+// Results mocks base method.
+func (m *MockD3API) Results(ctx context.Context, status *dragdropdo_sdk_go.StatusResponse) ([]dragdropdo_sdk_go.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Results", ctx, status)
+	ret0, _ := ret[0].([]dragdropdo_sdk_go.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Results indicates an expected call of Results.
+func (mr *MockD3APIMockRecorder) Results(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Results", reflect.TypeOf((*MockD3API)(nil).Results), ctx, status)
+}
+
+// SearchFiles mocks base method.
+func (m *MockD3API) SearchFiles(options dragdropdo_sdk_go.SearchOptions) (*dragdropdo_sdk_go.SearchFilesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFiles", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.SearchFilesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchFiles indicates an expected call of SearchFiles.
+func (mr *MockD3APIMockRecorder) SearchFiles(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFiles", reflect.TypeOf((*MockD3API)(nil).SearchFiles), options)
+}
+
+// SetFileExpiry mocks base method.
+func (m *MockD3API) SetFileExpiry(fileKey dragdropdo_sdk_go.FileKey, ttl time.Duration) (*dragdropdo_sdk_go.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFileExpiry", fileKey, ttl)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFileExpiry indicates an expected call of SetFileExpiry.
+func (mr *MockD3APIMockRecorder) SetFileExpiry(fileKey, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFileExpiry", reflect.TypeOf((*MockD3API)(nil).SetFileExpiry), fileKey, ttl)
+}
+
+// Share mocks base method.
+func (m *MockD3API) Share(fileKeys []dragdropdo_sdk_go.FileKey, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Share", fileKeys, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Share indicates an expected call of Share.
+func (mr *MockD3APIMockRecorder) Share(fileKeys, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Share", reflect.TypeOf((*MockD3API)(nil).Share), fileKeys, notes)
+}
+
+// StorageStats mocks base method.
+func (m *MockD3API) StorageStats() (*dragdropdo_sdk_go.StorageStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageStats")
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.StorageStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageStats indicates an expected call of StorageStats.
+func (mr *MockD3APIMockRecorder) StorageStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageStats", reflect.TypeOf((*MockD3API)(nil).StorageStats))
+}
+
+// Submit mocks base method.
+func (m *MockD3API) Submit(ctx context.Context, options dragdropdo_sdk_go.OperationOptions) (*dragdropdo_sdk_go.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockD3APIMockRecorder) Submit(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockD3API)(nil).Submit), ctx, options)
+}
+
+// TagFile mocks base method.
+func (m *MockD3API) TagFile(fileKey dragdropdo_sdk_go.FileKey, tags []string) (*dragdropdo_sdk_go.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagFile", fileKey, tags)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TagFile indicates an expected call of TagFile.
+func (mr *MockD3APIMockRecorder) TagFile(fileKey, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagFile", reflect.TypeOf((*MockD3API)(nil).TagFile), fileKey, tags)
+}
+
+// UnlockPdf mocks base method.
+func (m *MockD3API) UnlockPdf(fileKeys []dragdropdo_sdk_go.FileKey, password string, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockPdf", fileKeys, password, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockPdf indicates an expected call of UnlockPdf.
+func (mr *MockD3APIMockRecorder) UnlockPdf(fileKeys, password, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockPdf", reflect.TypeOf((*MockD3API)(nil).UnlockPdf), fileKeys, password, notes)
+}
+
+// UploadFile mocks base method.
+func (m *MockD3API) UploadFile(options dragdropdo_sdk_go.UploadFileOptions) (*dragdropdo_sdk_go.UploadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.UploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockD3APIMockRecorder) UploadFile(options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockD3API)(nil).UploadFile), options)
+}
+
+// UploadFileContext mocks base method.
+func (m *MockD3API) UploadFileContext(ctx context.Context, options dragdropdo_sdk_go.UploadFileOptions) (*dragdropdo_sdk_go.UploadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFileContext", ctx, options)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.UploadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadFileContext indicates an expected call of UploadFileContext.
+func (mr *MockD3APIMockRecorder) UploadFileContext(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFileContext", reflect.TypeOf((*MockD3API)(nil).UploadFileContext), ctx, options)
+}
+
+// WaitForOperation mocks base method.
+func (m *MockD3API) WaitForOperation(ctx context.Context, mainTaskID dragdropdo_sdk_go.MainTaskID, opts dragdropdo_sdk_go.WaitOptions) ([]dragdropdo_sdk_go.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForOperation", ctx, mainTaskID, opts)
+	ret0, _ := ret[0].([]dragdropdo_sdk_go.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForOperation indicates an expected call of WaitForOperation.
+func (mr *MockD3APIMockRecorder) WaitForOperation(ctx, mainTaskID, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForOperation", reflect.TypeOf((*MockD3API)(nil).WaitForOperation), ctx, mainTaskID, opts)
+}
+
+// Zip mocks base method.
+func (m *MockD3API) Zip(fileKeys []dragdropdo_sdk_go.FileKey, notes map[string]string) (*dragdropdo_sdk_go.OperationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Zip", fileKeys, notes)
+	ret0, _ := ret[0].(*dragdropdo_sdk_go.OperationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Zip indicates an expected call of Zip.
+func (mr *MockD3APIMockRecorder) Zip(fileKeys, notes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Zip", reflect.TypeOf((*MockD3API)(nil).Zip), fileKeys, notes)
+}
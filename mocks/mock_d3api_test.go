@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"errors"
+	"testing"
+
+	d3 "github.com/dragdropdo/dragdropdo-sdk-go"
+	"go.uber.org/mock/gomock"
+)
+
+var _ d3.D3API = (*MockD3API)(nil)
+
+func TestMockD3API(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	api := NewMockD3API(ctrl)
+
+	api.EXPECT().
+		Convert([]d3.FileKey{"file-key-123"}, "pdf", gomock.Nil()).
+		Return(&d3.OperationResponse{MainTaskID: "task-123"}, nil)
+	api.EXPECT().
+		GetStatus(gomock.Any()).
+		Return(nil, errors.New("unavailable"))
+
+	var client d3.D3API = api
+	op, err := client.Convert([]d3.FileKey{"file-key-123"}, "pdf", nil)
+	if err != nil || op.MainTaskID != "task-123" {
+		t.Errorf("Expected the stubbed operation, got %+v, %v", op, err)
+	}
+	if _, err := client.GetStatus(d3.StatusOptions{MainTaskID: "task-123"}); err == nil {
+		t.Error("Expected the stubbed error")
+	}
+}
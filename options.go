@@ -0,0 +1,267 @@
+package d3
+
+import (
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Option configures a client created with NewClient
+type Option func(*Config)
+
+// NewClient creates a new Dragdropdo Client instance from an API key and
+// functional options. It is equivalent to NewDragdropdo with the Config
+// built by applying opts in order.
+func NewClient(apiKey string, opts ...Option) (*Dragdropdo, error) {
+	config := Config{APIKey: apiKey}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewDragdropdo(config)
+}
+
+// WithConfig replaces the whole configuration, keeping the API key passed to
+// NewClient when config.APIKey is empty
+func WithConfig(config Config) Option {
+	return func(c *Config) {
+		apiKey := c.APIKey
+		*c = config
+		if c.APIKey == "" {
+			c.APIKey = apiKey
+		}
+	}
+}
+
+// WithBaseURL sets the base URL of the D3 API
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithEnvironment selects the D3 deployment to use when no base URL is set
+func WithEnvironment(env Environment) Option {
+	return func(c *Config) {
+		c.Environment = env
+	}
+}
+
+// WithTimeout sets the timeout of each API request attempt
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithDialTimeout bounds establishing a TCP connection
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.DialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout bounds the TLS handshake
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout bounds the wait for response headers
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.ResponseHeaderTimeout = timeout
+	}
+}
+
+// WithUploadPartTimeout bounds each presigned part upload
+func WithUploadPartTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.UploadPartTimeout = timeout
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key, value string) Option {
+	return func(c *Config) {
+		if c.Headers == nil {
+			c.Headers = map[string]string{}
+		}
+		c.Headers[key] = value
+	}
+}
+
+// WithHeaders adds headers sent with every request
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Config) {
+		if c.Headers == nil {
+			c.Headers = map[string]string{}
+		}
+		for k, v := range headers {
+			c.Headers[k] = v
+		}
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API calls and part uploads
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used for API calls and part uploads
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithCustomTransport carries API requests and part uploads over transport
+// instead of HTTP
+func WithCustomTransport(transport Transport) Option {
+	return func(c *Config) {
+		c.CustomTransport = transport
+	}
+}
+
+// WithProxy routes API calls and part uploads through an http, https or
+// socks5 proxy
+func WithProxy(proxyURL string) Option {
+	return func(c *Config) {
+		c.ProxyURL = proxyURL
+	}
+}
+
+// WithTLSConfig sets the TLS configuration for API calls and part uploads,
+// e.g. custom root CAs or client certificates for mutual TLS
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Config) {
+		c.TLSConfig = tlsConfig
+	}
+}
+
+// WithRetry sets the retry policy for failed API calls
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Config) {
+		c.Retry = policy
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier to the User-Agent
+func WithUserAgentSuffix(suffix string) Option {
+	return func(c *Config) {
+		c.UserAgentSuffix = suffix
+	}
+}
+
+// WithCredentials sets a provider evaluated per request for the API key
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(c *Config) {
+		c.Credentials = provider
+	}
+}
+
+// WithAuthScheme selects how the API key is sent
+func WithAuthScheme(scheme AuthScheme) Option {
+	return func(c *Config) {
+		c.AuthScheme = scheme
+	}
+}
+
+// WithSigner signs every API request attempt with signer
+func WithSigner(signer RequestSigner) Option {
+	return func(c *Config) {
+		c.Signer = signer
+	}
+}
+
+// WithLogger sets the structured logger for client events
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithMetrics sets the recorder for request, upload and polling metrics
+func WithMetrics(metrics MetricsRecorder) Option {
+	return func(c *Config) {
+		c.Metrics = metrics
+	}
+}
+
+// WithCurlWriter writes a sanitized curl command for each API request to w
+func WithCurlWriter(w io.Writer) Option {
+	return func(c *Config) {
+		c.CurlWriter = w
+	}
+}
+
+// WithMaxFileSize rejects larger files in UploadFile before uploading
+func WithMaxFileSize(bytes int64) Option {
+	return func(c *Config) {
+		c.MaxFileSize = bytes
+	}
+}
+
+// WithPlanFileSizeCheck rejects files larger than the plan allows, looked up
+// from the usage endpoint
+func WithPlanFileSizeCheck() Option {
+	return func(c *Config) {
+		c.CheckPlanFileSize = true
+	}
+}
+
+// WithPasswordPolicy sets the policy for passwords set on PDFs
+func WithPasswordPolicy(policy PasswordPolicy) Option {
+	return func(c *Config) {
+		c.PasswordPolicy = &policy
+	}
+}
+
+// WithoutETagVerification skips checking the ETag of completed uploads
+// against the parts sent
+func WithoutETagVerification() Option {
+	return func(c *Config) {
+		c.DisableETagVerification = true
+	}
+}
+
+// WithPreflightValidation checks that the files of an operation support its
+// action before submitting it
+func WithPreflightValidation() Option {
+	return func(c *Config) {
+		c.PreflightValidation = true
+	}
+}
+
+// WithDryRun builds requests without sending them, see Config.DryRun
+func WithDryRun() Option {
+	return func(c *Config) {
+		c.DryRun = true
+	}
+}
+
+// WithSchemaCheck calls fn when an API response's fields differ from what
+// the client decodes, see Config.OnSchemaDrift
+func WithSchemaCheck(fn func(SchemaDrift)) Option {
+	return func(c *Config) {
+		c.OnSchemaDrift = fn
+	}
+}
+
+// WithAPIConnections tunes the connections used for API calls
+func WithAPIConnections(options ConnectionOptions) Option {
+	return func(c *Config) {
+		c.APIConnections = options
+	}
+}
+
+// WithUploadConnections tunes the connections used for part uploads
+func WithUploadConnections(options ConnectionOptions) Option {
+	return func(c *Config) {
+		c.UploadConnections = options
+	}
+}
@@ -0,0 +1,121 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected bearer auth, got '%s'", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Tenant") != "tenant-a" {
+			t.Errorf("Expected X-Tenant header, got '%s'", r.Header.Get("X-Tenant"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL+"/"),
+		WithTimeout(5*time.Second),
+		WithHeader("X-Tenant", "tenant-a"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if client.baseURL != server.URL {
+		t.Errorf("Expected base URL '%s', got '%s'", server.URL, client.baseURL)
+	}
+	if client.timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.timeout)
+	}
+
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if _, err := NewClient(""); err == nil {
+		t.Error("Expected error for missing API key")
+	}
+}
+
+type countingTransport struct {
+	methods []string
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.methods = append(t.methods, req.Method)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClient_WithTransport(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "transport.txt")
+	if err := os.WriteFile(tmpFile, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part1"},
+				},
+			})
+		case "/part1":
+			w.Header().Set("ETag", `"etag-1"`)
+		case "/v1/biz/complete-upload":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "transport.txt"}); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	if strings.Join(transport.methods, ",") != "POST,PUT,POST" {
+		t.Errorf("Expected API and part requests through the transport, got %v", transport.methods)
+	}
+}
+
+func TestNewClient_UserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua := r.Header.Get("User-Agent")
+		if !strings.HasPrefix(ua, "d3-go-client/v"+Version+" (") || !strings.HasSuffix(ua, ") my-app/2.3") {
+			t.Errorf("Unexpected User-Agent '%s'", ua)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithUserAgentSuffix("my-app/2.3"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+}
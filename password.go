@@ -0,0 +1,89 @@
+package d3
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy constrains the passwords LockPdf and ResetPdfPassword set on
+// PDFs. The API accepts some passwords that PDF readers cannot reproduce,
+// producing files that can't be opened, so they are rejected client-side.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters (default: 1)
+	MinLength int
+	// MaxLength is the maximum length in bytes once UTF-8 encoded. PDF's
+	// AES-256 security handler uses at most 127 bytes. Zero means no limit.
+	MaxLength int
+	// AllowNonASCII permits characters outside printable ASCII. Older
+	// readers encode such passwords inconsistently. Control characters are
+	// never allowed.
+	AllowNonASCII bool
+}
+
+// DefaultPasswordPolicy is used when Config.PasswordPolicy is nil: printable
+// ASCII, 1 to 127 bytes
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 1, MaxLength: 127}
+
+// Validate returns a *D3ValidationError if password violates the policy
+func (p PasswordPolicy) Validate(password string) error {
+	var v validation
+	p.validate(&v, "password", password)
+	return v.err()
+}
+
+// validate checks the password parameter called name
+func (p PasswordPolicy) validate(v *validation, name, password string) {
+	minLength := p.MinLength
+	if minLength < 1 {
+		minLength = 1
+	}
+
+	switch {
+	case password == "":
+		v.add(name, fmt.Sprintf("%s is required", name))
+		return
+	case utf8.RuneCountInString(password) < minLength:
+		v.add(name, fmt.Sprintf("%s must be at least %d characters", name, minLength))
+		return
+	case p.MaxLength > 0 && len(password) > p.MaxLength:
+		v.add(name, fmt.Sprintf("%s must be at most %d bytes", name, p.MaxLength))
+		return
+	case !utf8.ValidString(password):
+		v.add(name, fmt.Sprintf("%s is not valid UTF-8", name))
+		return
+	}
+
+	for i, r := range []rune(password) {
+		if unicode.IsControl(r) || (!p.AllowNonASCII && (r < ' ' || r > '~')) {
+			v.add(name, fmt.Sprintf("%s contains unsupported character %q at position %d", name, r, i+1))
+			return
+		}
+	}
+}
+
+// validateOperationPasswords applies the client's password policy to the
+// passwords a lock or reset_password operation sets. The current password of
+// unlock and reset_password only needs to be present.
+func (c *Dragdropdo) validateOperationPasswords(v *validation, options OperationOptions) {
+	policy := c.passwordPolicy
+	switch options.Action {
+	case ActionLock:
+		policy.validate(v, "password", stringParam(options.Parameters, "password"))
+	case ActionUnlock:
+		if stringParam(options.Parameters, "password") == "" {
+			v.add("password", "password is required")
+		}
+	case ActionResetPassword:
+		if stringParam(options.Parameters, "old_password") == "" {
+			v.add("old_password", "old_password is required")
+		}
+		policy.validate(v, "new_password", stringParam(options.Parameters, "new_password"))
+	}
+}
+
+// stringParam returns the string parameter called name, or ""
+func stringParam(params map[string]interface{}, name string) string {
+	s, _ := params[name].(string)
+	return s
+}
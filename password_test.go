@@ -0,0 +1,69 @@
+package d3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		policy   PasswordPolicy
+		password string
+		valid    bool
+	}{
+		{DefaultPasswordPolicy, "s3cret!", true},
+		{DefaultPasswordPolicy, "", false},
+		{DefaultPasswordPolicy, strings.Repeat("a", 128), false},
+		{DefaultPasswordPolicy, "pässword", false},
+		{DefaultPasswordPolicy, "tab\there", false},
+		{PasswordPolicy{AllowNonASCII: true}, "pässword", true},
+		{PasswordPolicy{AllowNonASCII: true}, "new\nline", false},
+		{PasswordPolicy{MinLength: 8}, "short", false},
+	}
+
+	for _, tt := range tests {
+		err := tt.policy.Validate(tt.password)
+		if tt.valid && err != nil {
+			t.Errorf("Expected %q to be valid, got %v", tt.password, err)
+		}
+		var validationErr *D3ValidationError
+		if !tt.valid && !errors.As(err, &validationErr) {
+			t.Errorf("Expected %q to be rejected with a D3ValidationError, got %v", tt.password, err)
+		}
+	}
+}
+
+func TestClient_PasswordValidation(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithPasswordPolicy(PasswordPolicy{MinLength: 8, MaxLength: 32}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.LockPdf([]FileKey{"file-key-123"}, "short", nil); !IsD3ValidationError(err) {
+		t.Errorf("Expected short password to be rejected, got %v", err)
+	}
+	if _, err := client.ResetPdfPassword([]FileKey{"file-key-123"}, "", "long enough", nil); !IsD3ValidationError(err) || !strings.Contains(err.Error(), "old_password") {
+		t.Errorf("Expected missing old password to be rejected, got %v", err)
+	}
+	if _, err := client.ResetPdfPassword([]FileKey{"file-key-123"}, "old", "naïve password", nil); err == nil || !strings.Contains(err.Error(), "new_password") {
+		t.Errorf("Expected non-ASCII new password to be rejected, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no requests for invalid passwords, got %d", calls)
+	}
+
+	if _, err := client.LockPdf([]FileKey{"file-key-123"}, "long enough", nil); err != nil {
+		t.Errorf("Expected valid password to be accepted, got %v", err)
+	}
+}
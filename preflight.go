@@ -0,0 +1,147 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// preflightCacheTTL is how long a CheckSupportedOperation answer is reused
+const preflightCacheTTL = time.Hour
+
+// preflightMaxFiles bounds the remembered file extensions; the oldest half
+// is dropped when it is reached
+const preflightMaxFiles = 10000
+
+// UnsupportedFile is a file rejected by pre-flight validation, listed in
+// the Details of the returned D3ValidationError
+type UnsupportedFile struct {
+	FileKey FileKey `json:"file_key"`
+	Ext     string  `json:"ext"`
+}
+
+// preflight validates operations against the extensions of the files they
+// target before they are submitted. Extensions are known for files uploaded
+// through the client; other file keys are not checked.
+type preflight struct {
+	mu       sync.Mutex
+	exts     map[FileKey]string
+	order    []FileKey
+	supports map[string]preflightEntry
+}
+
+type preflightEntry struct {
+	supported bool
+	expires   time.Time
+}
+
+func newPreflight() *preflight {
+	return &preflight{
+		exts:     map[FileKey]string{},
+		supports: map[string]preflightEntry{},
+	}
+}
+
+// rememberFile records the extension of an uploaded file
+func (p *preflight) rememberFile(fileKey FileKey, fileName string) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	if ext == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.exts[fileKey]; !ok {
+		if len(p.order) >= preflightMaxFiles {
+			for _, key := range p.order[:len(p.order)/2] {
+				delete(p.exts, key)
+			}
+			p.order = append([]FileKey(nil), p.order[len(p.order)/2:]...)
+		}
+		p.order = append(p.order, fileKey)
+	}
+	p.exts[fileKey] = ext
+}
+
+func (p *preflight) ext(fileKey FileKey) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exts[fileKey]
+}
+
+func (p *preflight) cached(key string) (supported, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.supports[key]
+	if !ok || time.Now().After(entry.expires) {
+		return false, false
+	}
+	return entry.supported, true
+}
+
+func (p *preflight) store(key string, supported bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.supports[key] = preflightEntry{supported: supported, expires: time.Now().Add(preflightCacheTTL)}
+}
+
+// preflightOperation returns a D3ValidationError listing the files whose
+// extension does not support options' action. Files of unknown extension
+// are let through, as are all files when the check itself fails, so the
+// API stays the final judge. Dry runs only use cached answers.
+func (c *Dragdropdo) preflightOperation(ctx context.Context, options OperationOptions) error {
+	if c.preflight == nil {
+		return nil
+	}
+
+	// Nil and empty parameters share cache entries
+	var params []byte
+	if len(options.Parameters) > 0 {
+		params, _ = json.Marshal(options.Parameters)
+	}
+	var unsupported []UnsupportedFile
+	for _, fileKey := range options.FileKeys {
+		ext := c.preflight.ext(fileKey)
+		if ext == "" {
+			continue
+		}
+
+		key := ext + "\x00" + string(options.Action) + "\x00" + string(params)
+		supported, ok := c.preflight.cached(key)
+		if !ok && c.isDryRun(ctx) {
+			continue
+		}
+		if !ok {
+			resp, err := c.CheckSupportedOperation(SupportedOperationOptions{
+				Ext:            ext,
+				Action:         options.Action,
+				Parameters:     options.Parameters,
+				Headers:        options.Headers,
+				RequestTimeout: options.RequestTimeout,
+			})
+			if err != nil {
+				continue
+			}
+			supported = resp.Supported
+			c.preflight.store(key, supported)
+		}
+		if !supported {
+			unsupported = append(unsupported, UnsupportedFile{FileKey: fileKey, Ext: ext})
+		}
+	}
+
+	if len(unsupported) == 0 {
+		return nil
+	}
+	names := make([]string, len(unsupported))
+	for i, file := range unsupported {
+		names[i] = fmt.Sprintf("%s (.%s)", file.FileKey, file.Ext)
+	}
+	e := NewD3ValidationError(fmt.Sprintf("action %q is not supported for %s", options.Action, strings.Join(names, ", ")), unsupported)
+	e.Err = ErrUnsupportedOperation
+	return e
+}
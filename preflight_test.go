@@ -0,0 +1,85 @@
+package d3
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_PreflightValidation(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"report.pdf", "photo.heic"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("test content"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	var checks, operations int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			fileKey := "key-" + strings.TrimSuffix(body["file_name"].(string), filepath.Ext(body["file_name"].(string)))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       fileKey,
+					"upload_id":      "upload-" + fileKey,
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case "/part/1":
+			w.Header().Set("ETag", `"etag-1"`)
+		case "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		case "/v1/biz/supported-operation":
+			atomic.AddInt32(&checks, 1)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"supported": body["ext"] == "pdf", "ext": body["ext"]},
+			})
+		case "/v1/biz/do":
+			atomic.AddInt32(&operations, 1)
+			w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithPreflightValidation())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	for _, name := range []string{"report.pdf", "photo.heic"} {
+		if _, err := client.UploadFile(UploadFileOptions{File: filepath.Join(dir, name), FileName: name}); err != nil {
+			t.Fatalf("Failed to upload %s: %v", name, err)
+		}
+	}
+
+	_, err = client.Compress([]FileKey{"key-report", "key-photo", "key-elsewhere"}, "recommended", nil)
+	var validationErr *D3ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrUnsupportedOperation) {
+		t.Fatalf("Expected unsupported operation validation error, got %v", err)
+	}
+	unsupported, ok := validationErr.Details.([]UnsupportedFile)
+	if !ok || len(unsupported) != 1 || unsupported[0] != (UnsupportedFile{FileKey: "key-photo", Ext: "heic"}) {
+		t.Errorf("Expected key-photo to be listed, got %+v", validationErr.Details)
+	}
+	if operations != 0 {
+		t.Errorf("Expected the operation not to be submitted, got %d calls", operations)
+	}
+
+	if _, err := client.Compress([]FileKey{"key-report", "key-elsewhere"}, "recommended", nil); err != nil {
+		t.Fatalf("Expected supported files to pass, got %v", err)
+	}
+	if checks != 2 || operations != 1 {
+		t.Errorf("Expected cached checks (2) and one operation, got %d checks and %d operations", checks, operations)
+	}
+}
@@ -0,0 +1,104 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// isExpiredURLError reports whether err is storage rejecting a part upload
+// because its presigned URL expired
+func isExpiredURLError(err error) bool {
+	var uploadErr *D3UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.StatusCode == nil || *uploadErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	details, _ := uploadErr.Details.(map[string]interface{})
+	code, _ := details["code"].(string)
+	message, _ := details["message"].(string)
+	switch code {
+	case "SignatureExpired", "ExpiredToken", "RequestExpired":
+		return true
+	case "AccessDenied":
+		// S3 reports an expired URL as "AccessDenied: Request has expired"
+		return strings.Contains(strings.ToLower(message), "expired")
+	}
+	return false
+}
+
+// partURLs holds the presigned URLs of an upload's parts and renews them
+// when storage reports them expired, so slow uploads can continue
+type partURLs struct {
+	mu   sync.Mutex
+	urls []string
+	// pending returns the indexes of the parts not yet uploaded
+	pending func() []int
+	// refresh returns new URLs for the given part numbers, in order
+	refresh func(partNumbers []int) ([]string, error)
+}
+
+// get returns the current URL of part i
+func (p *partURLs) get(i int) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.urls[i]
+}
+
+// renew returns a fresh URL for part i, whose URL expired was rejected. The
+// URLs of all parts not yet uploaded are renewed together; if another part
+// already renewed them, part i's new URL is returned without a request.
+func (p *partURLs) renew(i int, expired string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.urls[i] != expired {
+		return p.urls[i], nil
+	}
+
+	indexes := p.pending()
+	partNumbers := make([]int, len(indexes))
+	for j, index := range indexes {
+		partNumbers[j] = index + 1
+	}
+	urls, err := p.refresh(partNumbers)
+	if err != nil {
+		return "", err
+	}
+	if len(urls) != len(indexes) {
+		return "", newUploadError(ErrPresignedURLMismatch, fmt.Sprintf("requested %d renewed presigned URLs but received %d", len(indexes), len(urls)))
+	}
+	for j, index := range indexes {
+		p.urls[index] = urls[j]
+	}
+	return p.urls[i], nil
+}
+
+// refreshURLsResponse is the data of a refresh upload URLs response
+type refreshURLsResponse struct {
+	PresignedURLs []string `json:"presigned_urls"`
+}
+
+// refreshPresignedURLs requests new presigned URLs for parts of an upload
+// whose URLs expired, returned in the order of partNumbers
+func (c *Dragdropdo) refreshPresignedURLs(ctx context.Context, upload AbortUploadOptions, partNumbers []int, headers map[string]string, timeout time.Duration) ([]string, error) {
+	req := c.newRequest().
+		SetContext(ctx).
+		SetHeaders(headers).
+		SetTimeout(timeout).
+		SetBody(map[string]interface{}{
+			"file_key":     upload.FileKey,
+			"upload_id":    upload.UploadID,
+			"object_name":  upload.ObjectName,
+			"part_numbers": partNumbers,
+		})
+	resp, err := fetch[refreshURLsResponse](req, http.MethodPost, "/v1/biz/refresh-upload-urls")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh presigned URLs: %w", err)
+	}
+
+	return resp.PresignedURLs, nil
+}
@@ -0,0 +1,162 @@
+package d3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClient_UploadFile_RefreshesExpiredURLs(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "slow.bin")
+	if err := os.WriteFile(tmpFile, []byte(strings.Repeat("s", 3000)), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var (
+		mu            sync.Mutex
+		refreshed     []int
+		uploadedParts []string
+		completed     int
+	)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			urls := []string{}
+			for i := 1; i <= 3; i++ {
+				urls = append(urls, fmt.Sprintf("%s/part/%d?sig=old", server.URL, i))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": urls,
+				},
+			})
+		case r.URL.Path == "/v1/biz/refresh-upload-urls":
+			var body struct {
+				UploadID    string `json:"upload_id"`
+				PartNumbers []int  `json:"part_numbers"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.UploadID != "upload-id-456" {
+				t.Errorf("Expected upload_id upload-id-456, got %q", body.UploadID)
+			}
+			refreshed = append(refreshed, body.PartNumbers...)
+			urls := []string{}
+			for _, n := range body.PartNumbers {
+				urls = append(urls, fmt.Sprintf("%s/part/%d?sig=new", server.URL, n))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"presigned_urls": urls}})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			// The first part succeeds; the others find their URL expired
+			if r.URL.Path != "/part/1" && r.URL.Query().Get("sig") == "old" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Request has expired</Message></Error>`))
+				return
+			}
+			uploadedParts = append(uploadedParts, r.URL.Path)
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			var body struct {
+				Parts []interface{} `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			completed = len(body.Parts)
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, ChunkSize: 1000})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var (
+		hooksMu   sync.Mutex
+		starts    int
+		retries   []int
+		completes []UploadPartInfo
+		done      *UploadCompleteInfo
+	)
+	options := UploadFileOptions{
+		File:     tmpFile,
+		FileName: "slow.bin",
+		OnPartStart: func(UploadPartInfo) {
+			hooksMu.Lock()
+			defer hooksMu.Unlock()
+			starts++
+		},
+		OnPartRetry: func(info UploadPartInfo) {
+			hooksMu.Lock()
+			defer hooksMu.Unlock()
+			if info.Attempt != 1 || !isExpiredURLError(info.Err) {
+				t.Errorf("Expected the expired first attempt, got attempt %d: %v", info.Attempt, info.Err)
+			}
+			retries = append(retries, info.PartNumber)
+		},
+		OnPartComplete: func(info UploadPartInfo) {
+			hooksMu.Lock()
+			defer hooksMu.Unlock()
+			completes = append(completes, info)
+		},
+		OnComplete: func(info UploadCompleteInfo) { done = &info },
+	}
+	if _, err := client.UploadFile(options); err != nil {
+		t.Fatalf("Expected the upload to continue with renewed URLs, got %v", err)
+	}
+	// Parts 2 and 3 are renewed together, so part 3 may only ever see
+	// the renewed URL
+	retried := map[int]bool{}
+	for _, n := range retries {
+		retried[n] = true
+	}
+	if starts != 3+len(retries) || !retried[2] || retried[1] {
+		t.Errorf("Expected part 2 retried and one start per attempt, got %d starts and retries %v", starts, retries)
+	}
+	for _, info := range completes {
+		want := 1
+		if retried[info.PartNumber] {
+			want = 2
+		}
+		if info.Attempt != want || info.ETag != "etag" || info.Err != nil || info.TotalParts != 3 || info.Size != 1000 {
+			t.Errorf("Unexpected completion of part %d: %+v", info.PartNumber, info)
+		}
+	}
+	if len(completes) != 3 {
+		t.Errorf("Expected 3 part completions, got %d", len(completes))
+	}
+	if done == nil || done.FileKey != "file-key-123" || done.Parts != 3 || done.Size != 3000 {
+		t.Errorf("Expected OnComplete for the whole upload, got %+v", done)
+	}
+	if fmt.Sprint(refreshed) != "[2 3]" {
+		t.Errorf("Expected one refresh of parts [2 3], got %v", refreshed)
+	}
+	if len(uploadedParts) != 3 || completed != 3 {
+		t.Errorf("Expected 3 parts uploaded and completed, got %v and %d", uploadedParts, completed)
+	}
+}
+
+func TestIsExpiredURLError(t *testing.T) {
+	expired := NewD3UploadError("expired", map[string]interface{}{"code": "SignatureExpired"})
+	forbidden := http.StatusForbidden
+	expired.StatusCode = &forbidden
+	if !isExpiredURLError(fmt.Errorf("upload: %w", expired)) {
+		t.Error("Expected SignatureExpired to be detected")
+	}
+
+	denied := NewD3UploadError("denied", map[string]interface{}{"code": "AccessDenied", "message": "Access Denied"})
+	denied.StatusCode = &forbidden
+	if isExpiredURLError(denied) {
+		t.Error("Expected a plain AccessDenied not to count as expired")
+	}
+}
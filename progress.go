@@ -0,0 +1,193 @@
+package d3
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// progressWidth is the number of cells in a terminal progress bar
+const progressWidth = 30
+
+// progressLineStep is the percentage between the lines a ProgressBar writes
+// when it is not on a terminal
+const progressLineStep = 10
+
+// DownloadProgress represents download progress information
+type DownloadProgress struct {
+	BytesDownloaded int64
+	// TotalBytes is the size of the download, or 0 when it is unknown
+	TotalBytes int64
+	// Percentage is 0 when TotalBytes is unknown
+	Percentage int
+}
+
+// ProgressWriter is an io.Writer that counts the bytes written to it and
+// reports them to OnProgress, e.g. as part of an io.MultiWriter copying a
+// download link's body into a file:
+//
+//	progress := &d3.ProgressWriter{TotalBytes: resp.ContentLength, OnProgress: bar.Download}
+//	_, err := io.Copy(io.MultiWriter(out, progress), resp.Body)
+type ProgressWriter struct {
+	// TotalBytes is the expected size, 0 or less when unknown
+	TotalBytes int64
+	OnProgress func(DownloadProgress)
+	// Progress follows the download named Name alongside OnProgress; it is
+	// started by the first Write and ended by Finish
+	Progress ProgressReporter
+	Name     string
+
+	written int64
+	tracker *progressTracker
+}
+
+// Write counts b and reports the progress; it never fails
+func (w *ProgressWriter) Write(b []byte) (int, error) {
+	w.written += int64(len(b))
+	if w.Progress != nil {
+		w.track().update(w.written, max(w.TotalBytes, 0), "")
+	}
+	if w.OnProgress != nil {
+		progress := DownloadProgress{BytesDownloaded: w.written}
+		if w.TotalBytes > 0 {
+			progress.TotalBytes = w.TotalBytes
+			progress.Percentage = percentage(w.written, w.TotalBytes)
+		}
+		w.OnProgress(progress)
+	}
+	return len(b), nil
+}
+
+// Finish reports the end of the download to Progress: Done, or Error if err
+// is set
+func (w *ProgressWriter) Finish(err error) {
+	if w.Progress != nil {
+		w.track().finish(err)
+	}
+}
+
+// track returns the tracker of Progress, starting it on first use
+func (w *ProgressWriter) track() *progressTracker {
+	if w.tracker == nil {
+		w.tracker = startProgress(w.Progress, Progress{Stage: ProgressDownload, Name: w.Name, Total: max(w.TotalBytes, 0)})
+	}
+	return w.tracker
+}
+
+// ProgressBar renders upload and download progress as text on w. On a
+// terminal it redraws a single line:
+//
+//	report.docx [===============               ]  50%  1.2 MiB/2.4 MiB
+//
+// Elsewhere, e.g. in CI output or a log file, it writes a line every 10%:
+//
+//	report.docx  50%  1.2 MiB/2.4 MiB
+//
+// Its Upload and Download methods fit UploadFileOptions.OnProgress and
+// ProgressWriter.OnProgress, and it is a ProgressReporter for one stage. A
+// nil *ProgressBar draws nothing, so callers need not check whether
+// progress is shown.
+type ProgressBar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	label    string
+	terminal bool
+	last     int
+	done     int64
+}
+
+// NewProgressBar returns a bar labelled label drawn on w, redrawing in
+// place if w is a terminal
+func NewProgressBar(w io.Writer, label string) *ProgressBar {
+	return &ProgressBar{w: w, label: label, terminal: IsTerminal(w), last: -1}
+}
+
+// Upload draws upload progress
+func (p *ProgressBar) Upload(progress UploadProgress) {
+	p.update(progress.BytesUploaded, progress.TotalBytes)
+}
+
+// Download draws download progress
+func (p *ProgressBar) Download(progress DownloadProgress) {
+	p.update(progress.BytesDownloaded, progress.TotalBytes)
+}
+
+// update draws the bar when the percentage changed, or on a terminal when
+// the size is unknown (total <= 0) and only the bytes done are shown
+func (p *ProgressBar) update(done, total int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = done
+
+	if total <= 0 {
+		if p.terminal {
+			fmt.Fprintf(p.w, "\r%s %s", p.label, formatBytes(done))
+		}
+		return
+	}
+	percent := percentage(done, total)
+	if percent == p.last || !p.terminal && p.last >= 0 && percent/progressLineStep == p.last/progressLineStep {
+		return
+	}
+	p.last = percent
+
+	if !p.terminal {
+		fmt.Fprintf(p.w, "%s %3d%%  %s/%s\n", p.label, percent, formatBytes(done), formatBytes(total))
+		return
+	}
+	filled := percent * progressWidth / 100
+	fmt.Fprintf(p.w, "\r%s [%s%s] %3d%%  %s/%s", p.label,
+		strings.Repeat("=", filled), strings.Repeat(" ", progressWidth-filled),
+		percent, formatBytes(done), formatBytes(total))
+}
+
+// Start implements ProgressReporter; the bar is drawn from the first Update
+func (p *ProgressBar) Start(Progress) {}
+
+// Update implements ProgressReporter
+func (p *ProgressBar) Update(progress Progress) {
+	p.update(progress.Done, progress.Total)
+}
+
+// Done implements ProgressReporter by drawing the final progress and
+// calling Finish
+func (p *ProgressBar) Done(progress Progress) {
+	p.update(progress.Done, progress.Total)
+	p.Finish()
+}
+
+// Error implements ProgressReporter by calling Finish
+func (p *ProgressBar) Error(Progress, error) {
+	p.Finish()
+}
+
+// Finish ends the bar's line. When the size was unknown and w is not a
+// terminal, it writes the bytes done.
+func (p *ProgressBar) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case p.terminal:
+		fmt.Fprintln(p.w)
+	case p.last < 0:
+		fmt.Fprintf(p.w, "%s %s\n", p.label, formatBytes(p.done))
+	}
+}
+
+// IsTerminal reports whether w is a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
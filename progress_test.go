@@ -0,0 +1,76 @@
+package d3
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestProgressBar_Terminal(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewProgressBar(&out, "report.pdf")
+	bar.terminal = true
+	bar.Upload(UploadProgress{BytesUploaded: 512, TotalBytes: 2048})
+	bar.Upload(UploadProgress{BytesUploaded: 520, TotalBytes: 2048}) // same percentage, not redrawn
+	bar.Upload(UploadProgress{BytesUploaded: 2048, TotalBytes: 2048})
+	bar.Finish()
+
+	want := "\rreport.pdf [=======                       ]  25%  512 B/2.0 KiB" +
+		"\rreport.pdf [==============================] 100%  2.0 KiB/2.0 KiB\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
+
+func TestProgressBar_Lines(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewProgressBar(&out, "report.pdf")
+	for done := int64(0); done <= 1000; done += 50 {
+		bar.Download(DownloadProgress{BytesDownloaded: done, TotalBytes: 1000})
+	}
+	bar.Finish()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("Expected a line every 10%%, got %q", out.String())
+	}
+	if lines[5] != "report.pdf  50%  500 B/1000 B" {
+		t.Errorf("Unexpected line %q", lines[5])
+	}
+}
+
+func TestProgressBar_UnknownSizeAndNil(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewProgressBar(&out, "archive.zip")
+	bar.Download(DownloadProgress{BytesDownloaded: 1536})
+	bar.Finish()
+	if out.String() != "archive.zip 1.5 KiB\n" {
+		t.Errorf("Expected the bytes done on Finish, got %q", out.String())
+	}
+
+	var none *ProgressBar
+	none.Upload(UploadProgress{BytesUploaded: 1, TotalBytes: 2})
+	none.Finish()
+}
+
+func TestProgressWriter(t *testing.T) {
+	var reports []DownloadProgress
+	progress := &ProgressWriter{TotalBytes: 8, OnProgress: func(p DownloadProgress) {
+		reports = append(reports, p)
+	}}
+	var out bytes.Buffer
+	if _, err := io.Copy(io.MultiWriter(&out, progress), io.LimitReader(strings.NewReader("abcdefgh"), 8)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	last := reports[len(reports)-1]
+	if last.BytesDownloaded != 8 || last.Percentage != 100 || out.String() != "abcdefgh" {
+		t.Errorf("Expected 8 bytes at 100%%, got %+v", last)
+	}
+
+	unknown := &ProgressWriter{OnProgress: func(p DownloadProgress) { last = p }}
+	unknown.Write([]byte("abc"))
+	if last.Percentage != 0 || last.TotalBytes != 0 {
+		t.Errorf("Expected no percentage for an unknown size, got %+v", last)
+	}
+}
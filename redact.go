@@ -0,0 +1,114 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are replaced entirely when logged
+var sensitiveHeaders = []string{"Authorization", "X-Api-Key", "Cookie", "Set-Cookie", sseCustomerKeyHeader, signatureHeader}
+
+// sensitiveFields are JSON body fields whose values are never logged
+var sensitiveFields = map[string]bool{
+	"password":     true,
+	"old_password": true,
+	"new_password": true,
+	"api_key":      true,
+	"token":        true,
+	"secret":       true,
+}
+
+// signatureParams are URL query parameters that grant access: presigned URL
+// signatures and the API key when sent with AuthQueryParam
+var signatureParams = map[string]bool{
+	"x-amz-signature":      true,
+	"x-amz-credential":     true,
+	"x-amz-security-token": true,
+	"x-goog-signature":     true,
+	"x-goog-credential":    true,
+	"signature":            true,
+	"sig":                  true,
+	"token":                true,
+	"api_key":              true,
+}
+
+var signatureParamPattern = regexp.MustCompile(`(?i)((?:x-amz-signature|x-amz-credential|x-amz-security-token|x-goog-signature|x-goog-credential|signature|sig|api_key)=)[^&"\s]+`)
+
+// redactHeader returns a copy of header with credentials removed
+func redactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clone.Get(name) != "" {
+			clone.Set(name, redacted)
+		}
+	}
+	return clone
+}
+
+// redactURL removes signature query parameters from a presigned URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	query := u.Query()
+	changed := false
+	for key := range query {
+		if signatureParams[strings.ToLower(key)] {
+			query.Set(key, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// redactBody removes passwords, tokens and URL signatures from a request or
+// response body and truncates it to max bytes (no limit if max <= 0)
+func redactBody(body []byte, max int) string {
+	var value interface{}
+	var out string
+	if err := json.Unmarshal(body, &value); err == nil {
+		encoded, _ := json.Marshal(redactValue(value))
+		out = string(encoded)
+	} else {
+		out = signatureParamPattern.ReplaceAllString(string(body), "${1}"+redacted)
+	}
+	if max > 0 && len(out) > max {
+		out = out[:max] + "...(truncated)"
+	}
+	return out
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	case string:
+		if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+			return redactURL(v)
+		}
+		return v
+	}
+	return value
+}
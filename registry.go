@@ -0,0 +1,227 @@
+package d3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileRecord is a local file and the key it was uploaded as
+type FileRecord struct {
+	// Path is the file's absolute path
+	Path    string  `json:"path"`
+	FileKey FileKey `json:"file_key"`
+	// SHA256 is the hex SHA-256 of the content uploaded
+	SHA256 string `json:"sha256"`
+	// Size and ModTime describe the file when it was hashed, to tell
+	// whether it changed since
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// Registry records which local files were uploaded as which file keys, by
+// path and by content hash, so multi-step scripts can reuse uploads instead
+// of repeating them. Save and LoadRegistry keep it between runs. It is safe
+// for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	byPath map[string]FileRecord
+	byHash map[string]FileRecord
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byPath: map[string]FileRecord{},
+		byHash: map[string]FileRecord{},
+	}
+}
+
+// LoadRegistry reads a Registry saved with Save. A missing file yields an
+// empty Registry.
+func LoadRegistry(path string) (*Registry, error) {
+	r := NewRegistry()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	var records []FileRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse registry %s: %w", path, err)
+	}
+	for _, record := range records {
+		r.Add(record)
+	}
+	return r, nil
+}
+
+// Save writes the records to path as JSON, atomically
+func (r *Registry) Save(path string) error {
+	data, err := json.MarshalIndent(r.Records(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save registry: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save registry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save registry: %w", err)
+	}
+	return nil
+}
+
+// Add records record, replacing any record of the same path or hash
+func (r *Registry) Add(record FileRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.byPath[record.Path]; ok && r.byHash[old.SHA256].Path == old.Path {
+		delete(r.byHash, old.SHA256)
+	}
+	r.byPath[record.Path] = record
+	if record.SHA256 != "" {
+		r.byHash[record.SHA256] = record
+	}
+}
+
+// Record hashes the file at path and records it as uploaded as fileKey
+func (r *Registry) Record(path string, fileKey FileKey) (FileRecord, error) {
+	record, err := hashFile(path)
+	if err != nil {
+		return FileRecord{}, err
+	}
+	record.FileKey = fileKey
+	record.UploadedAt = time.Now()
+	r.Add(record)
+	return record, nil
+}
+
+// ByPath returns the record of the file at path, which may have changed
+// since it was uploaded
+func (r *Registry) ByPath(path string) (FileRecord, bool) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	record, ok := r.byPath[path]
+	return record, ok
+}
+
+// ByHash returns the record of a file whose content has the hex SHA-256
+// sum
+func (r *Registry) ByHash(sum string) (FileRecord, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	record, ok := r.byHash[sum]
+	return record, ok
+}
+
+// Remove forgets the file at path
+func (r *Registry) Remove(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.byPath[path]; ok {
+		if r.byHash[old.SHA256].Path == path {
+			delete(r.byHash, old.SHA256)
+		}
+		delete(r.byPath, path)
+	}
+}
+
+// Records returns all records, sorted by path
+func (r *Registry) Records() []FileRecord {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	records := make([]FileRecord, 0, len(r.byPath))
+	for _, record := range r.byPath {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	return records
+}
+
+// UploadFile uploads options.File with client unless the same content was
+// uploaded before, and records it. The returned bool reports whether an
+// earlier upload was reused. A file whose size and modification time are
+// unchanged is not hashed again.
+func (r *Registry) UploadFile(ctx context.Context, client D3API, options UploadFileOptions) (FileRecord, bool, error) {
+	record, err := r.current(options.File)
+	if err != nil {
+		return FileRecord{}, false, err
+	}
+	if known, ok := r.ByHash(record.SHA256); ok {
+		record.FileKey = known.FileKey
+		record.UploadedAt = known.UploadedAt
+		r.Add(record)
+		return record, true, nil
+	}
+
+	resp, err := client.UploadFileContext(ctx, options)
+	if err != nil {
+		return FileRecord{}, false, err
+	}
+	record.FileKey = resp.FileKey
+	record.UploadedAt = time.Now()
+	r.Add(record)
+	return record, false, nil
+}
+
+// current returns the file's record with its current hash, reusing the
+// recorded hash if the file looks unchanged
+func (r *Registry) current(path string) (FileRecord, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileRecord{}, fmt.Errorf("%w: %w", ErrFileNotFound, err)
+	}
+	if known, ok := r.ByPath(path); ok && known.Size == info.Size() && known.ModTime.Equal(info.ModTime()) {
+		return known, nil
+	}
+	return hashFile(path)
+}
+
+// hashFile returns a record of the file at path with its hash, size and
+// modification time
+func hashFile(path string) (FileRecord, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return FileRecord{}, fmt.Errorf("%w: %w", ErrFileNotFound, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return FileRecord{}, err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return FileRecord{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return FileRecord{
+		Path:    path,
+		SHA256:  hex.EncodeToString(hash.Sum(nil)),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}, nil
+}
@@ -0,0 +1,104 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistry_UploadFile(t *testing.T) {
+	var uploads int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			uploads++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       fmt.Sprintf("file-key-%d", uploads),
+					"upload_id":      "upload-id",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case "/part/1":
+			w.Header().Set("ETag", `"etag"`)
+		case "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	dir := t.TempDir()
+	original := filepath.Join(dir, "report.docx")
+	copied := filepath.Join(dir, "copy.docx")
+	os.WriteFile(original, []byte("report"), 0o644)
+	os.WriteFile(copied, []byte("report"), 0o644)
+
+	registry := NewRegistry()
+	ctx := context.Background()
+	record, reused, err := registry.UploadFile(ctx, client, UploadFileOptions{File: original, FileName: "report.docx"})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if reused || record.FileKey != "file-key-1" || record.Path != original {
+		t.Errorf("Expected a new upload of %s as file-key-1, got %+v (reused %v)", original, record, reused)
+	}
+
+	// The same content under another path is not uploaded again
+	record, reused, err = registry.UploadFile(ctx, client, UploadFileOptions{File: copied, FileName: "copy.docx"})
+	if err != nil || !reused || record.FileKey != "file-key-1" {
+		t.Errorf("Expected the copy to reuse file-key-1, got %+v, %v (reused %v)", record, err, reused)
+	}
+
+	// Changed content is uploaded again
+	os.WriteFile(original, []byte("report v2"), 0o644)
+	record, reused, err = registry.UploadFile(ctx, client, UploadFileOptions{File: original, FileName: "report.docx"})
+	if err != nil || reused || record.FileKey != "file-key-2" {
+		t.Errorf("Expected the changed file to be uploaded as file-key-2, got %+v, %v (reused %v)", record, err, reused)
+	}
+	if uploads != 2 {
+		t.Errorf("Expected 2 uploads, got %d", uploads)
+	}
+
+	if byHash, ok := registry.ByHash(record.SHA256); !ok || byHash.Path != original {
+		t.Errorf("Expected lookup by hash to find %s, got %+v", original, byHash)
+	}
+
+	saved := filepath.Join(dir, "registry.json")
+	if err := registry.Save(saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadRegistry(saved)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got, ok := loaded.ByPath(copied); !ok || got.FileKey != "file-key-1" {
+		t.Errorf("Expected the loaded registry to know %s, got %+v", copied, got)
+	}
+	if len(loaded.Records()) != 2 {
+		t.Errorf("Expected 2 records, got %d", len(loaded.Records()))
+	}
+
+	loaded.Remove(copied)
+	if _, ok := loaded.ByPath(copied); ok {
+		t.Error("Expected the removed record to be gone")
+	}
+}
+
+func TestLoadRegistry_Missing(t *testing.T) {
+	registry, err := LoadRegistry(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(registry.Records()) != 0 {
+		t.Errorf("Expected an empty registry, got %v, %v", registry.Records(), err)
+	}
+}
@@ -0,0 +1,221 @@
+package d3
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// ProgressStage is the kind of work a ProgressReporter follows
+type ProgressStage string
+
+const (
+	// ProgressUpload counts the bytes of a file uploaded
+	ProgressUpload ProgressStage = "upload"
+	// ProgressPoll counts the files of an operation that have finished
+	ProgressPoll ProgressStage = "poll"
+	// ProgressDownload counts the bytes of an output downloaded
+	ProgressDownload ProgressStage = "download"
+	// ProgressOperations counts the operations of a Manager that have
+	// finished
+	ProgressOperations ProgressStage = "operations"
+)
+
+// Progress is the progress of one stage, as reported to a ProgressReporter
+type Progress struct {
+	Stage ProgressStage
+	// Name is the file name of an upload or download, or the main task ID
+	// of a poll
+	Name string
+	// Done and Total count bytes or, for polls and Managers, files or
+	// operations; Total is 0 when unknown
+	Done  int64
+	Total int64
+	// Percentage is 0 when Total is unknown
+	Percentage int
+	// Status is the operation status of a poll
+	Status string
+}
+
+// ProgressReporter follows work from Start, through any number of Updates,
+// to Done or Error. It is accepted next to the OnProgress callbacks of
+// UploadFileOptions, PollStatusOptions, WaitOptions, ProgressWriter and
+// ManagerOptions, so one implementation can follow a file through upload,
+// polling and download. Calls for one stage are not concurrent, but a
+// reporter shared between stages must be safe for concurrent use.
+type ProgressReporter interface {
+	Start(Progress)
+	Update(Progress)
+	Done(Progress)
+	Error(Progress, error)
+}
+
+// ProgressEventKind names the ProgressReporter method an event stands for
+type ProgressEventKind string
+
+// Kinds of ProgressEvent
+const (
+	ProgressStarted  ProgressEventKind = "start"
+	ProgressUpdated  ProgressEventKind = "update"
+	ProgressFinished ProgressEventKind = "done"
+	ProgressFailed   ProgressEventKind = "error"
+)
+
+// ProgressEvent is a ProgressReporter call sent on a channel
+type ProgressEvent struct {
+	Kind ProgressEventKind
+	Progress
+	// Err is set for ProgressFailed
+	Err error
+}
+
+// ProgressChannel returns a ProgressReporter sending its calls on ch.
+// Updates are dropped while ch is full, so a slow reader never stalls the
+// work; the other events are always delivered.
+func ProgressChannel(ch chan<- ProgressEvent) ProgressReporter {
+	return progressChannel(ch)
+}
+
+type progressChannel chan<- ProgressEvent
+
+func (c progressChannel) Start(p Progress) { c <- ProgressEvent{Kind: ProgressStarted, Progress: p} }
+
+func (c progressChannel) Update(p Progress) {
+	select {
+	case c <- ProgressEvent{Kind: ProgressUpdated, Progress: p}:
+	default:
+	}
+}
+
+func (c progressChannel) Done(p Progress) { c <- ProgressEvent{Kind: ProgressFinished, Progress: p} }
+
+func (c progressChannel) Error(p Progress, err error) {
+	c <- ProgressEvent{Kind: ProgressFailed, Progress: p, Err: err}
+}
+
+// ProgressLogger returns a ProgressReporter logging to logger: starts and
+// ends at info level, failures at warn level and updates at debug level
+func ProgressLogger(logger *slog.Logger) ProgressReporter {
+	return &progressLogger{logger: logger}
+}
+
+type progressLogger struct {
+	logger *slog.Logger
+}
+
+func (l *progressLogger) Start(p Progress) {
+	l.logger.Info("progress.started", progressAttrs(p)...)
+}
+
+func (l *progressLogger) Update(p Progress) {
+	l.logger.Debug("progress.updated", progressAttrs(p)...)
+}
+
+func (l *progressLogger) Done(p Progress) {
+	l.logger.Info("progress.finished", progressAttrs(p)...)
+}
+
+func (l *progressLogger) Error(p Progress, err error) {
+	l.logger.Warn("progress.failed", append(progressAttrs(p), slog.String("error", err.Error()))...)
+}
+
+func progressAttrs(p Progress) []any {
+	attrs := []any{
+		slog.String("stage", string(p.Stage)),
+		slog.String("name", p.Name),
+		slog.Int64("done", p.Done),
+		slog.Int64("total", p.Total),
+		slog.Int("percentage", p.Percentage),
+	}
+	if p.Status != "" {
+		attrs = append(attrs, slog.String("status", p.Status))
+	}
+	return attrs
+}
+
+// MultiProgress returns a ProgressReporter passing every call to each of
+// reporters in order; nil reporters are skipped
+func MultiProgress(reporters ...ProgressReporter) ProgressReporter {
+	var multi multiProgress
+	for _, r := range reporters {
+		if r != nil {
+			multi = append(multi, r)
+		}
+	}
+	return multi
+}
+
+type multiProgress []ProgressReporter
+
+func (m multiProgress) Start(p Progress) {
+	for _, r := range m {
+		r.Start(p)
+	}
+}
+
+func (m multiProgress) Update(p Progress) {
+	for _, r := range m {
+		r.Update(p)
+	}
+}
+
+func (m multiProgress) Done(p Progress) {
+	for _, r := range m {
+		r.Done(p)
+	}
+}
+
+func (m multiProgress) Error(p Progress, err error) {
+	for _, r := range m {
+		r.Error(p, err)
+	}
+}
+
+// progressTracker reports one stage to a ProgressReporter, remembering the
+// last progress so Done and Error can repeat it
+type progressTracker struct {
+	reporter ProgressReporter
+	mu       sync.Mutex
+	last     Progress
+}
+
+// startProgress reports the start of a stage; without a reporter it
+// returns nil, which reports nothing
+func startProgress(reporter ProgressReporter, p Progress) *progressTracker {
+	if reporter == nil {
+		return nil
+	}
+	if p.Total > 0 {
+		p.Percentage = percentage(p.Done, p.Total)
+	}
+	reporter.Start(p)
+	return &progressTracker{reporter: reporter, last: p}
+}
+
+// update reports done of total; a nil tracker reports nothing
+func (t *progressTracker) update(done, total int64, status string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last.Done, t.last.Total, t.last.Status = done, total, status
+	t.last.Percentage = 0
+	if total > 0 {
+		t.last.Percentage = percentage(done, total)
+	}
+	t.reporter.Update(t.last)
+}
+
+// finish reports Done, or Error if err is set
+func (t *progressTracker) finish(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.reporter.Error(t.last, err)
+		return
+	}
+	t.reporter.Done(t.last)
+}
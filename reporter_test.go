@@ -0,0 +1,131 @@
+package d3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingReporter records each call as "kind stage done/total"
+type recordingReporter struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingReporter) record(kind string, p Progress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, fmt.Sprintf("%s %s %d/%d", kind, p.Stage, p.Done, p.Total))
+}
+
+func (r *recordingReporter) Start(p Progress)            { r.record("start", p) }
+func (r *recordingReporter) Update(p Progress)           { r.record("update", p) }
+func (r *recordingReporter) Done(p Progress)             { r.record("done", p) }
+func (r *recordingReporter) Error(p Progress, err error) { r.record("error", p) }
+
+func (r *recordingReporter) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, ", ")
+}
+
+func TestProgressReporter_UploadAndPoll(t *testing.T) {
+	var polls int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			fmt.Fprintf(w, `{"data":{"file_key":"file-key-1","upload_id":"upload-id","presigned_urls":["%s/part/1"]}}`, server.URL)
+		case r.URL.Path == "/part/1":
+			w.Header().Set("ETag", `"etag"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		case strings.HasPrefix(r.URL.Path, "/v1/biz/status/"):
+			polls++
+			if polls == 1 {
+				w.Write([]byte(`{"data":{"operation_status":"running","files_data":[{"file_key":"a","status":"completed"},{"file_key":"b","status":"running"}]}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"operation_status":"completed","files_data":[{"file_key":"a","status":"completed"},{"file_key":"b","status":"completed"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "doc.pdf")
+	os.WriteFile(file, []byte("0123456789"), 0644)
+
+	reporter := &recordingReporter{}
+	var logs bytes.Buffer
+	progress := MultiProgress(reporter, nil, ProgressLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if _, err := client.UploadFile(UploadFileOptions{File: file, FileName: "doc.pdf", Progress: progress}); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if _, err := client.PollStatus(PollStatusOptions{StatusOptions: StatusOptions{MainTaskID: "task-1"}, Interval: time.Millisecond, Progress: progress}); err != nil {
+		t.Fatalf("PollStatus failed: %v", err)
+	}
+	want := "start upload 0/10, update upload 10/10, done upload 10/10, " +
+		"start poll 0/0, update poll 1/2, update poll 2/2, done poll 2/2"
+	if got := reporter.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if !strings.Contains(logs.String(), "msg=progress.finished stage=poll name=task-1 done=2 total=2 percentage=100 status=completed") {
+		t.Errorf("Expected the poll to be logged as finished, got:\n%s", logs.String())
+	}
+
+	failing := &recordingReporter{}
+	os.Remove(file)
+	client.UploadFile(UploadFileOptions{File: file, FileName: "doc.pdf", Progress: failing})
+	if got := failing.String(); got != "" {
+		t.Errorf("Expected no progress for an upload failing validation, got %q", got)
+	}
+}
+
+func TestProgressWriter_Reporter(t *testing.T) {
+	reporter := &recordingReporter{}
+	w := &ProgressWriter{TotalBytes: 8, Progress: reporter, Name: "out.pdf"}
+	w.Write([]byte("1234"))
+	w.Write([]byte("5678"))
+	w.Finish(nil)
+	if got, want := reporter.String(), "start download 0/8, update download 4/8, update download 8/8, done download 8/8"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	failed := &recordingReporter{}
+	(&ProgressWriter{Progress: failed}).Finish(errors.New("reset"))
+	if got, want := failed.String(), "start download 0/0, error download 0/0"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestProgressChannel_DropsUpdatesWhenFull(t *testing.T) {
+	ch := make(chan ProgressEvent, 2)
+	reporter := ProgressChannel(ch)
+	reporter.Start(Progress{Stage: ProgressUpload})
+	reporter.Update(Progress{Done: 1})
+	reporter.Update(Progress{Done: 2})
+
+	if e := <-ch; e.Kind != ProgressStarted {
+		t.Errorf("Expected a start event, got %v", e.Kind)
+	}
+	if e := <-ch; e.Kind != ProgressUpdated || e.Done != 1 {
+		t.Errorf("Expected the first update, got %v %d", e.Kind, e.Done)
+	}
+	reporter.Error(Progress{}, errors.New("boom"))
+	if e := <-ch; e.Kind != ProgressFailed || e.Err == nil {
+		t.Errorf("Expected an error event, got %v", e.Kind)
+	}
+}
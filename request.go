@@ -0,0 +1,351 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiBackend performs a prepared API request. The default backend is built on
+// resty; building with the d3_stdlib tag swaps in a net/http-only backend so
+// the package has no third-party dependencies.
+type apiBackend interface {
+	do(ctx context.Context, method, url string, header http.Header, body []byte) (*apiResponse, error)
+}
+
+// apiRequest represents a single API call
+type apiRequest struct {
+	client  *Dragdropdo
+	ctx     context.Context
+	query   url.Values
+	params  map[string]string
+	header  map[string]string
+	timeout time.Duration
+	body    interface{}
+	// decode checks and decodes a response, see fetch; by default only the
+	// errors it reports are checked
+	decode func(*apiResponse) error
+
+	// Set by Execute
+	method   string
+	route    string
+	path     string
+	target   string
+	attempts int
+}
+
+// newRequest starts building an API call
+func (c *Dragdropdo) newRequest() *apiRequest {
+	return &apiRequest{
+		client: c,
+		ctx:    context.Background(),
+	}
+}
+
+// SetContext sets the context bounding all attempts of the request
+func (r *apiRequest) SetContext(ctx context.Context) *apiRequest {
+	r.ctx = ctx
+	return r
+}
+
+// SetHeaders sets headers for this request only, over the client defaults
+func (r *apiRequest) SetHeaders(header map[string]string) *apiRequest {
+	r.header = header
+	return r
+}
+
+// SetTimeout overrides the client timeout for each attempt of this request.
+// Zero keeps the client timeout.
+func (r *apiRequest) SetTimeout(timeout time.Duration) *apiRequest {
+	r.timeout = timeout
+	return r
+}
+
+// SetBody sets the value sent as the JSON request body
+func (r *apiRequest) SetBody(body interface{}) *apiRequest {
+	r.body = body
+	return r
+}
+
+// SetPathParam sets a value substituted, escaped, for {name} in the path
+func (r *apiRequest) SetPathParam(name, value string) *apiRequest {
+	if r.params == nil {
+		r.params = map[string]string{}
+	}
+	r.params[name] = value
+	return r
+}
+
+// SetQueryParamsFromValues sets the URL query parameters
+func (r *apiRequest) SetQueryParamsFromValues(query url.Values) *apiRequest {
+	r.query = query
+	return r
+}
+
+// Get sends the request as GET
+func (r *apiRequest) Get(route string) (*apiResponse, error) {
+	return r.Execute(http.MethodGet, route)
+}
+
+// Post sends the request as POST
+func (r *apiRequest) Post(route string) (*apiResponse, error) {
+	return r.Execute(http.MethodPost, route)
+}
+
+// Put sends the request as PUT
+func (r *apiRequest) Put(route string) (*apiResponse, error) {
+	return r.Execute(http.MethodPut, route)
+}
+
+// Delete sends the request as DELETE
+func (r *apiRequest) Delete(route string) (*apiResponse, error) {
+	return r.Execute(http.MethodDelete, route)
+}
+
+// Execute sends the request with the client's headers and timeout. Non-2xx
+// responses and failures reported in a 2xx envelope are returned as a
+// *D3APIError naming the endpoint; use fetch to also decode the data. Path
+// parameters in route are replaced by the values set with SetPathParam.
+// Errors are reported to the client's error hooks.
+func (r *apiRequest) Execute(method, route string) (*apiResponse, error) {
+	resp, err := r.execute(method, route)
+	if err != nil && !errors.Is(err, ErrDryRun) {
+		r.client.reportError(&ErrorInfo{
+			Method:  method,
+			Route:   route,
+			Path:    r.path,
+			Attempt: r.attempts,
+			Err:     err,
+		})
+	}
+	return resp, err
+}
+
+func (r *apiRequest) execute(method, route string) (*apiResponse, error) {
+	c := r.client
+	r.method = method
+	r.route = route
+	r.path = route
+
+	release, err := c.life.acquire(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	for name, value := range r.params {
+		r.path = strings.ReplaceAll(r.path, "{"+name+"}", url.PathEscape(value))
+	}
+
+	header, err := r.authorize()
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if r.body != nil {
+		body, err = json.Marshal(r.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+
+	if c.isDryRun(r.ctx) {
+		return nil, r.dryRunError(header, body)
+	}
+
+	resp, err := r.sendWithRetry(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// A short-lived token can expire between being fetched and being used;
+	// refresh it and try once more
+	if resp.StatusCode == http.StatusUnauthorized {
+		if refresher, ok := c.getCredentials().(credentialsInvalidator); ok {
+			refresher.Invalidate()
+			if header, err = r.authorize(); err != nil {
+				return nil, err
+			}
+			if resp, err = r.sendWithRetry(header, body); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	decode := r.decode
+	if decode == nil {
+		decode = checkResponse
+	}
+	if err := decode(resp); err != nil {
+		var apiErr *D3APIError
+		if errors.As(err, &apiErr) {
+			apiErr.Endpoint = r.method + " " + r.path
+		}
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// authorize fetches the API key and applies it according to the client's
+// auth scheme. It sets the request target and returns the authorization,
+// client default and per-request headers, in increasing precedence.
+func (r *apiRequest) authorize() (http.Header, error) {
+	c := r.client
+
+	apiKey, err := c.getCredentials().APIKey(r.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	headers, _ := c.settings()
+	header := http.Header{}
+	query := url.Values{}
+	for k, v := range r.query {
+		query[k] = v
+	}
+
+	switch c.authScheme {
+	case AuthAPIKeyHeader:
+		header.Set("X-API-Key", apiKey)
+	case AuthQueryParam:
+		query.Set("api_key", apiKey)
+	default:
+		header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	}
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+	for k, v := range r.header {
+		header.Set(k, v)
+	}
+
+	r.target = r.path
+	if len(query) > 0 {
+		r.target += "?" + query.Encode()
+	}
+
+	return header, nil
+}
+
+// sendWithRetry sends the request, retrying failed attempts according to the
+// client's retry policy
+func (r *apiRequest) sendWithRetry(header http.Header, body []byte) (*apiResponse, error) {
+	c := r.client
+
+	for attempt := 1; ; attempt++ {
+		r.attempts++
+		resp, err := r.sendFailover(header, body, attempt)
+
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+		if (err == nil && resp.IsSuccess()) || r.ctx.Err() != nil ||
+			!c.retry.shouldRetry(attempt, resp, err) {
+			return resp, err
+		}
+
+		wait, retryAfter := c.retry.wait(attempt, resp)
+		if c.retry.OnRetry != nil {
+			c.retry.OnRetry(RetryEvent{
+				Method:     r.method,
+				Path:       r.path,
+				Attempt:    attempt,
+				StatusCode: statusCode,
+				Err:        err,
+				Wait:       wait,
+				RetryAfter: retryAfter,
+			})
+		}
+		if err := sleepContext(r.ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendFailover performs a single attempt against the current endpoint,
+// failing over to the next healthy endpoint when it appears to be down
+func (r *apiRequest) sendFailover(header http.Header, body []byte, attempt int) (*apiResponse, error) {
+	endpoints := r.client.endpoints
+
+	for tries := 1; ; tries++ {
+		i, baseURL := endpoints.pick()
+		resp, sent, err := r.send(baseURL, header, body, attempt)
+		if !sent {
+			// A hook or the signer rejected the request; no endpoint was
+			// tried, so none is at fault
+			return nil, err
+		}
+		if r.ctx.Err() != nil || !shouldFailover(resp, err) {
+			endpoints.markHealthy(i)
+			return resp, err
+		}
+		if !endpoints.markFailed(i) || tries >= len(endpoints.urls) {
+			return resp, err
+		}
+	}
+}
+
+// send performs a single attempt against baseURL bounded by the request
+// timeout, running the registered hooks around it. sent reports whether the
+// request reached the transport, so err is a transport error rather than a
+// hook's or the signer's.
+func (r *apiRequest) send(baseURL string, header http.Header, body []byte, attempt int) (_ *apiResponse, sent bool, _ error) {
+	c := r.client
+	beforeHooks, afterHooks := c.hooks()
+
+	info := &RequestInfo{
+		Method:  r.method,
+		Route:   r.route,
+		Path:    r.path,
+		URL:     baseURL + r.target,
+		Header:  header.Clone(),
+		Body:    body,
+		Attempt: attempt,
+	}
+	for _, hook := range beforeHooks {
+		if err := hook(info); err != nil {
+			return nil, false, err
+		}
+	}
+	if c.signer != nil {
+		if err := c.signer.Sign(r.ctx, info); err != nil {
+			return nil, false, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	_, timeout := c.settings()
+	if r.timeout > 0 {
+		timeout = r.timeout
+	}
+	ctx, cancel := context.WithTimeout(r.ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.transport.DoJSON(ctx, info.Method, info.URL, info.Header, body)
+
+	if len(afterHooks) > 0 {
+		result := &ResponseInfo{
+			Request: info,
+			Latency: time.Since(start),
+			Err:     err,
+		}
+		if resp != nil {
+			result.StatusCode = resp.StatusCode
+			result.Header = resp.Header
+			result.Body = resp.Body
+		}
+		for _, hook := range afterHooks {
+			hook(result)
+		}
+	}
+
+	return resp, true, err
+}
@@ -0,0 +1,137 @@
+package d3
+
+import "encoding/json"
+
+// The response types below accept the API's snake_case keys and their
+// camelCase equivalents when decoded, and emit both when encoded, so the
+// deprecated *Alias fields no longer need to be kept in sync by hand.
+// Decoding still fills the aliases for code that reads them.
+
+// uploadResponseJSON is the wire form of UploadResponse
+type uploadResponseJSON struct {
+	FileKey            FileKey  `json:"file_key"`
+	UploadID           UploadID `json:"upload_id"`
+	PresignedURLs      []string `json:"presigned_urls"`
+	ObjectName         string   `json:"object_name,omitempty"`
+	FileKeyCamel       FileKey  `json:"fileKey,omitempty"`
+	UploadIDCamel      UploadID `json:"uploadId,omitempty"`
+	PresignedURLsCamel []string `json:"presignedUrls,omitempty"`
+	ObjectNameCamel    string   `json:"objectName,omitempty"`
+}
+
+// MarshalJSON encodes the response with both snake_case and camelCase keys
+func (r UploadResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uploadResponseJSON{
+		FileKey:            r.FileKey,
+		UploadID:           r.UploadID,
+		PresignedURLs:      r.PresignedURLs,
+		ObjectName:         r.ObjectName,
+		FileKeyCamel:       r.FileKey,
+		UploadIDCamel:      r.UploadID,
+		PresignedURLsCamel: r.PresignedURLs,
+		ObjectNameCamel:    r.ObjectName,
+	})
+}
+
+// UnmarshalJSON decodes snake_case or camelCase keys, preferring snake_case
+func (r *UploadResponse) UnmarshalJSON(data []byte) error {
+	var v uploadResponseJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = UploadResponse{
+		FileKey:       firstNonEmpty(v.FileKey, v.FileKeyCamel),
+		UploadID:      firstNonEmpty(v.UploadID, v.UploadIDCamel),
+		PresignedURLs: v.PresignedURLs,
+		ObjectName:    firstNonEmpty(v.ObjectName, v.ObjectNameCamel),
+	}
+	if r.PresignedURLs == nil {
+		r.PresignedURLs = v.PresignedURLsCamel
+	}
+	r.syncAliases()
+	return nil
+}
+
+// syncAliases copies the fields to their deprecated aliases
+func (r *UploadResponse) syncAliases() {
+	r.FileKeyAlias = r.FileKey
+	r.UploadIDAlias = r.UploadID
+	r.PresignedURLsAlias = r.PresignedURLs
+	r.ObjectNameAlias = r.ObjectName
+}
+
+// operationResponseJSON is the wire form of OperationResponse
+type operationResponseJSON struct {
+	MainTaskID      MainTaskID `json:"main_task_id"`
+	MainTaskIDCamel MainTaskID `json:"mainTaskId,omitempty"`
+}
+
+// MarshalJSON encodes the response with both snake_case and camelCase keys
+func (r OperationResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(operationResponseJSON{
+		MainTaskID:      r.MainTaskID,
+		MainTaskIDCamel: r.MainTaskID,
+	})
+}
+
+// UnmarshalJSON decodes snake_case or camelCase keys, preferring snake_case
+func (r *OperationResponse) UnmarshalJSON(data []byte) error {
+	var v operationResponseJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.MainTaskID = firstNonEmpty(v.MainTaskID, v.MainTaskIDCamel)
+	r.MainTaskIDAlias = r.MainTaskID
+	return nil
+}
+
+// statusResponseJSON is the wire form of StatusResponse
+type statusResponseJSON struct {
+	OperationStatus      string           `json:"operation_status"`
+	FilesData            []FileTaskStatus `json:"files_data"`
+	OperationStatusCamel string           `json:"operationStatus,omitempty"`
+	FilesDataCamel       []FileTaskStatus `json:"filesData,omitempty"`
+}
+
+// MarshalJSON encodes the response with both snake_case and camelCase keys
+func (r StatusResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(statusResponseJSON{
+		OperationStatus:      r.OperationStatus,
+		FilesData:            r.FilesData,
+		OperationStatusCamel: r.OperationStatus,
+		FilesDataCamel:       r.FilesData,
+	})
+}
+
+// UnmarshalJSON decodes snake_case or camelCase keys, preferring snake_case
+func (r *StatusResponse) UnmarshalJSON(data []byte) error {
+	var v statusResponseJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = StatusResponse{
+		OperationStatus: firstNonEmpty(v.OperationStatus, v.OperationStatusCamel),
+		FilesData:       v.FilesData,
+	}
+	if r.FilesData == nil {
+		r.FilesData = v.FilesDataCamel
+	}
+	r.syncAliases()
+	return nil
+}
+
+// syncAliases copies the fields to their deprecated aliases
+func (r *StatusResponse) syncAliases() {
+	r.OperationStatusAlias = r.OperationStatus
+	r.FilesDataAlias = r.FilesData
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty[T ~string](values ...T) T {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package d3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestResponses_JSON(t *testing.T) {
+	var snake, camel StatusResponse
+	if err := json.Unmarshal([]byte(`{"operation_status":"completed","files_data":[{"file_key":"file-key-123","status":"completed"}]}`), &snake); err != nil {
+		t.Fatalf("Failed to decode snake_case: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"operationStatus":"completed","filesData":[{"file_key":"file-key-123","status":"completed"}]}`), &camel); err != nil {
+		t.Fatalf("Failed to decode camelCase: %v", err)
+	}
+	for _, status := range []StatusResponse{snake, camel} {
+		if status.OperationStatus != "completed" || len(status.FilesData) != 1 {
+			t.Errorf("Expected completed status with one file, got %+v", status)
+		}
+		if status.OperationStatusAlias != status.OperationStatus || len(status.FilesDataAlias) != 1 {
+			t.Errorf("Expected aliases to be filled, got %+v", status)
+		}
+	}
+
+	var upload UploadResponse
+	if err := json.Unmarshal([]byte(`{"fileKey":"file-key-123","uploadId":"upload-id-456","presignedUrls":["https://example.com/1"]}`), &upload); err != nil {
+		t.Fatalf("Failed to decode upload: %v", err)
+	}
+	if upload.FileKey != "file-key-123" || upload.UploadID != "upload-id-456" || len(upload.PresignedURLs) != 1 || upload.FileKeyAlias != "file-key-123" {
+		t.Errorf("Expected camelCase keys to be accepted, got %+v", upload)
+	}
+
+	encoded, err := json.Marshal(OperationResponse{MainTaskID: "task-123"})
+	if err != nil {
+		t.Fatalf("Failed to encode operation: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"main_task_id":"task-123"`) || !strings.Contains(string(encoded), `"mainTaskId":"task-123"`) {
+		t.Errorf("Expected both key styles, got %s", encoded)
+	}
+
+	var roundTrip OperationResponse
+	if err := json.Unmarshal(encoded, &roundTrip); err != nil || roundTrip.MainTaskID != "task-123" {
+		t.Errorf("Expected round trip to keep the task ID, got %+v, %v", roundTrip, err)
+	}
+}
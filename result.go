@@ -0,0 +1,195 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Result is the outcome of one file of an operation: its FileTaskStatus
+// and, for a completed file, the metadata of its output. Download and Open
+// fetch the output, so callers need not handle the download link.
+type Result struct {
+	FileTaskStatus
+	// Name is the output's file name as assigned by the server in the
+	// Content-Disposition header, including RFC 5987 encoded names, or else
+	// taken from the download link, sanitized for the local file system
+	Name string
+	// Size is the output's size in bytes, -1 if unknown, as it is for
+	// outputs decrypted with Config.Encryption
+	Size int64
+	// ContentType is the output's MIME type, if storage reports one
+	ContentType string
+
+	httpClient *http.Client
+	userAgent  string
+	encryption EncryptionProvider
+}
+
+// Results resolves the outcome of each file of status, in order. The output
+// of each completed file is probed with a one-byte ranged request for its
+// metadata; files without a download link have none. An output that cannot
+// be probed keeps Size -1 and is named after its download link; the errors
+// are returned joined, together with every result.
+func (c *Dragdropdo) Results(ctx context.Context, status *StatusResponse) ([]Result, error) {
+	// Downloads are not bound by the API or part timeouts
+	httpClient := *c.partClient
+	httpClient.Timeout = 0
+
+	results := make([]Result, len(status.FilesData))
+	var errs []error
+	for i, file := range status.FilesData {
+		results[i] = Result{
+			FileTaskStatus: file,
+			Size:           -1,
+			httpClient:     &httpClient,
+			userAgent:      c.userAgent,
+			encryption:     c.encryption,
+		}
+		if file.DownloadLink == "" {
+			continue
+		}
+		if err := results[i].resolve(ctx); err != nil {
+			results[i].Name = results[i].outputName(nil)
+			errs = append(errs, fmt.Errorf("failed to resolve output of %s: %w", file.FileKey, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// resolve fills the output's metadata from the headers of its first byte
+func (r *Result) resolve(ctx context.Context) error {
+	resp, err := r.get(ctx, "bytes=0-0")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable:
+		// Content-Range is "bytes 0-0/SIZE", or "bytes */0" for an empty output
+		contentRange := resp.Header.Get("Content-Range")
+		if i := strings.LastIndexByte(contentRange, '/'); i >= 0 {
+			if size, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				r.Size = size
+			}
+		}
+	case http.StatusOK:
+		r.Size = resp.ContentLength
+	default:
+		return newDownloadError(r.FileKey, resp)
+	}
+	r.ContentType = resp.Header.Get("Content-Type")
+	r.Name = r.outputName(resp.Header)
+	if r.encryption != nil {
+		r.Size = -1
+	}
+	return nil
+}
+
+// outputName names the output after its Content-Disposition, download link
+// or file key, in that order of preference
+func (r *Result) outputName(header http.Header) string {
+	if name := ContentDispositionFileName(header.Get("Content-Disposition")); name != "" {
+		return name
+	}
+	if u, err := url.Parse(r.DownloadLink); err == nil {
+		if name := SanitizeFileName(path.Base(u.Path)); name != "" && name != "." && name != "/" {
+			return name
+		}
+	}
+	return SanitizeFileName(string(r.FileKey))
+}
+
+// Open returns the output's content, decrypted with Config.Encryption if
+// set; the caller must close it
+func (r *Result) Open() (io.ReadCloser, error) {
+	return r.OpenContext(context.Background())
+}
+
+// OpenContext is Open with a context
+func (r *Result) OpenContext(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := r.get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newDownloadError(r.FileKey, resp)
+	}
+	if r.encryption != nil {
+		return decryptingBody(r.encryption, resp.Body), nil
+	}
+	return resp.Body, nil
+}
+
+// Download writes the output to dest, or into dest under Name if dest is a
+// directory, and returns the path written. The file appears only once it is
+// complete.
+func (r *Result) Download(dest string) (string, error) {
+	return r.DownloadContext(context.Background(), dest)
+}
+
+// DownloadContext is Download with a context
+func (r *Result) DownloadContext(ctx context.Context, dest string) (string, error) {
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = filepath.Join(dest, r.Name)
+	}
+	body, err := r.OpenContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", r.FileKey, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to download %s: %w", r.FileKey, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", r.FileKey, err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", r.FileKey, err)
+	}
+	return dest, nil
+}
+
+// get requests the download link, with a Range header unless byteRange is
+// empty
+func (r *Result) get(ctx context.Context, byteRange string) (*http.Response, error) {
+	if r.DownloadLink == "" {
+		return nil, fmt.Errorf("%w for %s (status %s)", ErrNoDownloadLink, r.FileKey, r.Status)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.DownloadLink, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download link for %s: %w", r.FileKey, err)
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+	httpClient := r.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", r.FileKey, err)
+	}
+	return resp, nil
+}
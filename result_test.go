@@ -0,0 +1,95 @@
+package d3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClient_Results(t *testing.T) {
+	content := []byte("%PDF-1.7 converted")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/out-1":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+		case "/files/empty.txt":
+			http.ServeContent(w, r, "empty.txt", time.Time{}, bytes.NewReader(nil))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Request has expired</Message></Error>`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	status := &StatusResponse{
+		OperationStatus: StatusPartiallyCompleted,
+		FilesData: []FileTaskStatus{
+			{FileKey: "file-key-1", Status: StatusCompleted, DownloadLink: server.URL + "/files/out-1"},
+			{FileKey: "file-key-2", Status: StatusCompleted, DownloadLink: server.URL + "/files/empty.txt"},
+			{FileKey: "file-key-3", Status: StatusFailed, ErrorMessage: "corrupt input"},
+		},
+	}
+	results, err := client.Results(context.Background(), status)
+	if err != nil {
+		t.Fatalf("Failed to resolve results: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if r := results[0]; r.Name != "report.pdf" || r.Size != int64(len(content)) || r.ContentType != "application/pdf" {
+		t.Errorf("Expected report.pdf of %d bytes, got %q of %d bytes (%s)", len(content), r.Name, r.Size, r.ContentType)
+	}
+	if r := results[1]; r.Name != "empty.txt" || r.Size != 0 {
+		t.Errorf("Expected empty.txt of 0 bytes, got %q of %d bytes", r.Name, r.Size)
+	}
+	if r := results[2]; r.Size != -1 || r.ErrorMessage != "corrupt input" {
+		t.Errorf("Expected failed file without metadata, got %+v", r)
+	}
+
+	dir := t.TempDir()
+	dest, err := results[0].Download(dir)
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(dest); dest != filepath.Join(dir, "report.pdf") || !bytes.Equal(got, content) {
+		t.Errorf("Expected content in %s, got %q in %s", filepath.Join(dir, "report.pdf"), got, dest)
+	}
+
+	body, err := results[0].Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("Expected %q, got %q", content, got)
+	}
+
+	if _, err := results[2].Download(dir); !errors.Is(err, ErrNoDownloadLink) {
+		t.Errorf("Expected ErrNoDownloadLink for a failed file, got %v", err)
+	}
+
+	status.FilesData[0].DownloadLink = server.URL + "/files/expired"
+	results, err = client.Results(context.Background(), status)
+	var apiErr *D3APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode == nil || *apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a 403 D3APIError for an expired link, got %v", err)
+	}
+	if len(results) != 3 || results[0].Size != -1 || results[0].Name != "expired" || results[1].Name != "empty.txt" {
+		t.Errorf("Expected every result despite the failed probe, got %+v", results)
+	}
+}
@@ -0,0 +1,204 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures how failed API calls are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Zero or one disables retries.
+	MaxAttempts int
+	// Backoff returns the wait before the given retry (1 for the first
+	// retry). Defaults to ExponentialBackoff(500ms, 30s).
+	Backoff func(attempt int) time.Duration
+	// RetryOn decides whether a response status or transport error is worth
+	// retrying. statusCode is 0 when err is set. Defaults to DefaultRetryOn.
+	RetryOn func(statusCode int, err error) bool
+	// MaxRetryAfter caps the wait requested by a Retry-After header on 429
+	// and 503 responses. Defaults to one minute.
+	MaxRetryAfter time.Duration
+	// OnRetry is called before waiting for each retry
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes an upcoming retry
+type RetryEvent struct {
+	Method     string
+	Path       string
+	Attempt    int
+	StatusCode int
+	Err        error
+	Wait       time.Duration
+	// RetryAfter is true when Wait was requested by the server
+	RetryAfter bool
+}
+
+// DefaultRetryOn retries network errors (including timeouts) and the 408,
+// 429, 500, 502, 503 and 504 status codes, as classified by IsRetryable
+func DefaultRetryOn(statusCode int, err error) bool {
+	if err != nil {
+		return IsRetryable(err)
+	}
+	return retryableStatus(statusCode)
+}
+
+// retryableStatus reports whether a response status indicates a transient
+// failure
+func retryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether the call that returned err may succeed if
+// repeated unchanged, making the same decision as the client's own retry
+// layer. API errors are classified by D3APIError.Retryable, failed part
+// uploads by their status code, and network errors and attempt timeouts
+// (including ErrRequestTimeout) are retryable. Validation errors, poll
+// timeouts, cancellation and a closed client are not.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrClientClosed) {
+		return false
+	}
+
+	var apiErr *D3APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	var validationErr *D3ValidationError
+	if errors.As(err, &validationErr) {
+		return false
+	}
+	var timeoutErr *D3TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return errors.Is(timeoutErr.Err, ErrRequestTimeout)
+	}
+	var uploadErr *D3UploadError
+	if errors.As(err, &uploadErr) && uploadErr.StatusCode != nil {
+		return retryableStatus(*uploadErr.StatusCode)
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// ExponentialBackoff returns a backoff that doubles from base up to max,
+// with up to 20% jitter so concurrent clients don't retry in lockstep
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		wait := base
+		for i := 1; i < attempt && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			wait = max
+		}
+		if wait > 0 {
+			wait -= time.Duration(rand.Int63n(int64(wait)/5 + 1))
+		}
+		return wait
+	}
+}
+
+var defaultBackoff = ExponentialBackoff(500*time.Millisecond, 30*time.Second)
+
+// shouldRetry reports whether another attempt should follow attempt, which
+// returned resp or err. Without RetryOn, error responses are classified as
+// the D3APIError the caller would receive, so retry hints in the body are
+// honored.
+func (p RetryPolicy) shouldRetry(attempt int, resp *apiResponse, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.RetryOn != nil {
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+		return p.RetryOn(statusCode, err)
+	}
+	if err != nil {
+		return IsRetryable(err)
+	}
+	return newAPIErrorFromResponse(resp).Retryable()
+}
+
+// backoff returns the wait before the given retry
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(attempt)
+	}
+	return defaultBackoff(attempt)
+}
+
+// wait returns the wait before retrying a failed attempt, honoring a
+// Retry-After header on 429 and 503 responses
+func (p RetryPolicy) wait(attempt int, resp *apiResponse) (time.Duration, bool) {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			max := p.MaxRetryAfter
+			if max <= 0 {
+				max = time.Minute
+			}
+			if wait > max {
+				wait = max
+			}
+			return wait, true
+		}
+	}
+	return p.backoff(attempt), false
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,207 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"main_task_id": "task-123"},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithRetry(RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     func(int) time.Duration { return time.Millisecond },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	operation, err := client.Convert([]FileKey{"file-key-123"}, "png", nil)
+	if err != nil {
+		t.Fatalf("Failed to create operation: %v", err)
+	}
+	if operation.MainTaskID != "task-123" || calls != 3 {
+		t.Errorf("Expected success on third attempt, got '%s' after %d calls", operation.MainTaskID, calls)
+	}
+}
+
+func TestClient_RetryPolicy_NotRetryable(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithRetry(RetryPolicy{MaxAttempts: 5}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+	if calls != 1 {
+		t.Errorf("Expected 400 not to be retried, got %d calls", calls)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		wait := backoff(attempt)
+		if wait > max || wait < max*4/5 {
+			t.Errorf("Attempt %d: expected wait in [%v, %v], got %v", attempt, max*4/5, max, wait)
+		}
+	}
+}
+
+func TestClient_RetryPolicy_RetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+	}))
+	defer server.Close()
+
+	events := []RetryEvent{}
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithRetry(RetryPolicy{
+			MaxAttempts:   2,
+			MaxRetryAfter: 10 * time.Millisecond,
+			OnRetry:       func(e RetryEvent) { events = append(events, e) },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	status, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if status.OperationStatus != "completed" {
+		t.Errorf("Expected completed status, got '%s'", status.OperationStatus)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 retry event, got %d", len(events))
+	}
+	if !events[0].RetryAfter || events[0].Wait != 10*time.Millisecond || events[0].StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected capped Retry-After wait, got %+v", events[0])
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"5":                             5 * time.Second,
+		"Fri, 01 Nov 2024 12:00:30 GMT": 30 * time.Second,
+	}
+	for value, expected := range tests {
+		wait, ok := parseRetryAfter(value, now)
+		if !ok || wait != expected {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; expected %v", value, wait, ok, expected)
+		}
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected invalid Retry-After to be rejected")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	no, yes := false, true
+	hinted := NewD3APIError("Service unavailable", http.StatusServiceUnavailable, nil, nil)
+	hinted.retryable = &no
+	partErr := NewD3UploadError("failed to upload part 1", nil)
+	partStatus := http.StatusInternalServerError
+	partErr.StatusCode = &partStatus
+	forced := NewD3APIError("Bad request", http.StatusBadRequest, nil, nil)
+	forced.retryable = &yes
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"429", NewD3APIError("Too many requests", http.StatusTooManyRequests, nil, nil), true},
+		{"404", NewD3APIError("Not found", http.StatusNotFound, nil, nil), false},
+		{"hinted not retryable", hinted, false},
+		{"hinted retryable", forced, true},
+		{"wrapped API error", fmt.Errorf("convert: %w", NewD3APIError("Bad gateway", http.StatusBadGateway, nil, nil)), true},
+		{"validation", NewD3ValidationError("file_name is required", nil), false},
+		{"poll timeout", NewD3TimeoutError(""), false},
+		{"part upload", partErr, true},
+		{"network", wrapUploadError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}, "upload failed"), true},
+		{"canceled", &url.Error{Op: "Get", URL: "https://api.example.com", Err: context.Canceled}, false},
+		{"closed", ErrClientClosed, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.expected {
+			t.Errorf("%s: expected IsRetryable %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestClient_RetryPolicy_RetryableHint(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"message":"Account suspended","retryable":false}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithRetry(RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     func(int) time.Duration { return time.Millisecond },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+	if calls != 1 {
+		t.Errorf("Expected the retryable hint to stop retries, got %d calls", calls)
+	}
+	if IsRetryable(err) {
+		t.Errorf("Expected IsRetryable to agree with the retry layer for %v", err)
+	}
+}
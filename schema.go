@@ -0,0 +1,209 @@
+package d3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaDrift describes how an API response differs in shape from the
+// struct the client decodes it into, e.g. after an API change
+type SchemaDrift struct {
+	// Endpoint is the method and route, e.g.
+	// GET /v1/biz/status/{main_task_id}
+	Endpoint string
+	// Unknown lists the response fields the client ignores, as paths such
+	// as data.files_data[].thumbnail_url
+	Unknown []string
+	// Missing lists the fields the client expects, those not tagged
+	// omitempty, that the response lacks
+	Missing []string
+}
+
+// schemaWireTypes maps response types with a custom UnmarshalJSON to the
+// struct describing the keys they accept
+var schemaWireTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(UploadResponse{}):    reflect.TypeOf(uploadResponseJSON{}),
+	reflect.TypeOf(OperationResponse{}): reflect.TypeOf(operationResponseJSON{}),
+	reflect.TypeOf(StatusResponse{}):    reflect.TypeOf(statusResponseJSON{}),
+}
+
+// schemaEnvelopeFields are top-level response fields handled by the client
+// for every endpoint rather than by the result struct
+var schemaEnvelopeFields = map[string]bool{"success": true, "message": true}
+
+// CheckSchema compares the JSON document body with the fields of v, a
+// struct or pointer to one such as *d3.StatusResponse, and returns the
+// fields present in body but not in v and the fields v expects but body
+// lacks, as sorted paths. Values of interface, map-of-interface and types
+// with their own decoding are not inspected.
+func CheckSchema(body []byte, v interface{}) (unknown, missing []string, err error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+	s := &schemaCheck{unknown: map[string]bool{}, missing: map[string]bool{}}
+	s.compare("", doc, reflect.TypeOf(v))
+	return s.sorted(s.unknown), s.sorted(s.missing), nil
+}
+
+// schemaCheck collects the differences found by compare
+type schemaCheck struct {
+	unknown map[string]bool
+	missing map[string]bool
+}
+
+func (s *schemaCheck) compare(path string, value interface{}, t reflect.Type) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || value == nil {
+		return
+	}
+	if wire, ok := schemaWireTypes[t]; ok {
+		t = wire
+	} else if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := schemaFields(t)
+		for key, field := range object {
+			f, ok := fields[key]
+			if !ok {
+				f, ok = fields[strings.ToLower(key)]
+			}
+			switch {
+			case ok:
+				s.compare(joinSchemaPath(path, key), field, f.typ)
+			case path == "" && schemaEnvelopeFields[key]:
+			default:
+				s.unknown[joinSchemaPath(path, key)] = true
+			}
+		}
+		for key, f := range fields {
+			if f.required && key == f.name && !hasSchemaKey(object, key) {
+				s.missing[joinSchemaPath(path, key)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			s.compare(path+"[]", item, t.Elem())
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range object {
+			s.compare(joinSchemaPath(path, "*"), item, t.Elem())
+		}
+	}
+}
+
+func (s *schemaCheck) sorted(paths map[string]bool) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(paths))
+	for path := range paths {
+		list = append(list, path)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// schemaField is a struct field as seen by encoding/json
+type schemaField struct {
+	name     string
+	typ      reflect.Type
+	required bool
+}
+
+// schemaFields returns the JSON fields of struct type t by name, and also
+// by lower-cased name for the case-insensitive matching encoding/json does
+func schemaFields(t reflect.Type) map[string]schemaField {
+	fields := map[string]schemaField{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, f := range schemaFields(embedded) {
+					if _, ok := fields[key]; !ok {
+						fields[key] = f
+					}
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		f := schemaField{name: name, typ: sf.Type, required: !strings.Contains(options, "omitempty")}
+		fields[name] = f
+		if lower := strings.ToLower(name); lower != name {
+			if _, ok := fields[lower]; !ok {
+				fields[lower] = f
+			}
+		}
+	}
+	return fields
+}
+
+// hasSchemaKey reports whether object has key, compared case-insensitively
+// as encoding/json does
+func hasSchemaKey(object map[string]interface{}, key string) bool {
+	if _, ok := object[key]; ok {
+		return true
+	}
+	for k := range object {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// checkSchema reports drift between resp and the request's result type to
+// the client's OnSchemaDrift hook
+func (r *apiRequest) checkSchema(resp *apiResponse, result interface{}) {
+	unknown, missing, err := CheckSchema(resp.Body, result)
+	if err != nil || (len(unknown) == 0 && len(missing) == 0) {
+		return
+	}
+	r.client.onSchemaDrift(SchemaDrift{
+		Endpoint: r.method + " " + r.route,
+		Unknown:  unknown,
+		Missing:  missing,
+	})
+}
@@ -0,0 +1,101 @@
+package d3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		v           interface{}
+		wantUnknown []string
+		wantMissing []string
+	}{
+		{
+			name: "matching",
+			body: `{"operation_status":"completed","files_data":[{"file_key":"k","status":"completed","download_link":"u"}]}`,
+			v:    &StatusResponse{},
+		},
+		{
+			name:        "drifted",
+			body:        `{"operation_status":"completed","progress":50,"files_data":[{"file_key":"k","state":"completed","thumbnail_url":"u"}]}`,
+			v:           &StatusResponse{},
+			wantUnknown: []string{"files_data[].state", "files_data[].thumbnail_url", "progress"},
+			wantMissing: []string{"files_data[].status"},
+		},
+		{
+			name: "camelCase keys are accepted",
+			body: `{"operation_status":"running","files_data":[],"operationStatus":"running","filesData":[]}`,
+			v:    StatusResponse{},
+		},
+		{
+			name: "wrapped in data",
+			body: `{"success":true,"message":"ok","data":{"main_task_id":"t"},"meta":{}}`,
+			v: &struct {
+				Data OperationResponse `json:"data"`
+			}{},
+			wantUnknown: []string{"meta"},
+		},
+		{
+			name: "missing data",
+			body: `{"success":true}`,
+			v: &struct {
+				Data OperationResponse `json:"data"`
+			}{},
+			wantMissing: []string{"data"},
+		},
+		{
+			name: "case-insensitive keys",
+			body: `{"Main_Task_ID":"t"}`,
+			v:    &OperationResponse{},
+		},
+	}
+	for _, tt := range tests {
+		unknown, missing, err := CheckSchema([]byte(tt.body), tt.v)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+			t.Errorf("%s: expected unknown %v, got %v", tt.name, tt.wantUnknown, unknown)
+		}
+		if !reflect.DeepEqual(missing, tt.wantMissing) {
+			t.Errorf("%s: expected missing %v, got %v", tt.name, tt.wantMissing, missing)
+		}
+	}
+
+	if _, _, err := CheckSchema([]byte(`not json`), &StatusResponse{}); err == nil {
+		t.Error("Expected an error for an invalid body")
+	}
+}
+
+func TestClient_OnSchemaDrift(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"queued","files_data":[],"eta_seconds":30}}`))
+	}))
+	defer server.Close()
+
+	var drifts []SchemaDrift
+	client, err := NewClient("test-key", WithBaseURL(server.URL), WithSchemaCheck(func(d SchemaDrift) {
+		drifts = append(drifts, d)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"}); err != nil {
+		t.Fatalf("Expected drift not to fail the call, got %v", err)
+	}
+	want := []SchemaDrift{{
+		Endpoint: "GET /v1/biz/status/{main_task_id}",
+		Unknown:  []string{"data.eta_seconds"},
+	}}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Errorf("Expected %+v, got %+v", want, drifts)
+	}
+}
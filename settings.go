@@ -0,0 +1,46 @@
+package d3
+
+import (
+	"errors"
+	"time"
+)
+
+// SetHeader sets a header sent with every subsequent request, replacing any
+// existing value. An empty value removes the header. It is safe to call while
+// other requests are in flight.
+func (c *Dragdropdo) SetHeader(key, value string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	// Copy on write so requests already reading the map are unaffected
+	headers := make(map[string]string, len(c.headers)+1)
+	for k, v := range c.headers {
+		headers[k] = v
+	}
+	if value == "" {
+		delete(headers, key)
+	} else {
+		headers[key] = value
+	}
+	c.headers = headers
+}
+
+// SetTimeout sets the timeout of subsequent request attempts. It is safe to
+// call while other requests are in flight.
+func (c *Dragdropdo) SetTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.timeout = timeout
+	return nil
+}
+
+// settings returns the current default headers, which must not be modified,
+// and timeout
+func (c *Dragdropdo) settings() (map[string]string, time.Duration) {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return c.headers, c.timeout
+}
@@ -0,0 +1,59 @@
+package d3
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_SetHeaderAndTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") == "slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Mutate settings while requests are in flight; run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			client.SetHeader("X-Request", fmt.Sprint(i))
+			client.SetTimeout(time.Duration(i+1) * time.Second)
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+				t.Errorf("Request failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	client.SetHeader("X-Tenant", "slow")
+	client.SetTimeout(10 * time.Millisecond)
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err == nil {
+		t.Error("Expected timeout after SetTimeout")
+	}
+
+	client.SetHeader("X-Tenant", "")
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Errorf("Expected header removal to take effect, got: %v", err)
+	}
+
+	if err := client.SetTimeout(0); err == nil {
+		t.Error("Expected error for zero timeout")
+	}
+}
@@ -0,0 +1,66 @@
+package d3
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ShareOptions represents options for creating a share link
+type ShareOptions struct {
+	ExpiresIn    time.Duration
+	Password     string
+	MaxDownloads int
+}
+
+// ShareLink represents a temporary link to a stored file
+type ShareLink struct {
+	URL               string    `json:"url"`
+	FileKey           FileKey   `json:"file_key"`
+	ExpiresAt         time.Time `json:"expires_at,omitempty"`
+	PasswordProtected bool      `json:"password_protected"`
+	MaxDownloads      int       `json:"max_downloads,omitempty"`
+}
+
+// CreateShareLink creates a temporary share link for a stored file
+func (c *Dragdropdo) CreateShareLink(fileKey FileKey, options ShareOptions) (*ShareLink, error) {
+	var v validation
+	if fileKey == "" {
+		v.add("file_key", "file_key is required")
+	}
+	if options.ExpiresIn < 0 {
+		v.add("expires_in", "expires_in must not be negative")
+	}
+	if options.MaxDownloads < 0 {
+		v.add("max_downloads", "max_downloads must not be negative")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"file_key": fileKey,
+	}
+	if options.ExpiresIn > 0 {
+		// Rounding down would turn a sub-second lifetime into 0, the server default
+		seconds := int64(options.ExpiresIn / time.Second)
+		if options.ExpiresIn%time.Second != 0 {
+			seconds++
+		}
+		body["expires_in"] = seconds
+	}
+	if options.Password != "" {
+		body["password"] = options.Password
+	}
+	if options.MaxDownloads > 0 {
+		body["max_downloads"] = options.MaxDownloads
+	}
+
+	resp, err := fetch[ShareLink](c.newRequest().SetBody(body), http.MethodPost, "/v1/biz/share-links")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return resp, nil
+}
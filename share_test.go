@@ -0,0 +1,68 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_CreateShareLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/biz/share-links" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+			return
+		}
+		if body["expires_in"] != float64(3600) {
+			t.Errorf("Expected expires_in 3600, got %v", body["expires_in"])
+		}
+		if body["max_downloads"] != float64(3) {
+			t.Errorf("Expected max_downloads 3, got %v", body["max_downloads"])
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"url":                "https://files.d3.com/s/abc",
+				"file_key":           body["file_key"],
+				"password_protected": true,
+				"max_downloads":      3,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	link, err := client.CreateShareLink("file-key-123", ShareOptions{
+		ExpiresIn:    time.Hour,
+		Password:     "secret",
+		MaxDownloads: 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create share link: %v", err)
+	}
+	if link.URL != "https://files.d3.com/s/abc" || !link.PasswordProtected {
+		t.Errorf("Unexpected share link: %+v", link)
+	}
+
+	// Partial seconds are rounded up, here to the 3600 the server expects
+	if _, err := client.CreateShareLink("file-key-123", ShareOptions{
+		ExpiresIn:    time.Hour - time.Second + time.Millisecond,
+		MaxDownloads: 3,
+	}); err != nil {
+		t.Errorf("Failed to create a share link with a fractional lifetime: %v", err)
+	}
+}
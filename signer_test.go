@@ -0,0 +1,67 @@
+package d3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACSigner_SignsRequests(t *testing.T) {
+	secret := []byte("shared-secret")
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if got := r.Header.Get("X-D3-Content-SHA256"); got != hex.EncodeToString(sum[:]) {
+			t.Errorf("Expected the body hash, got %q", got)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(strings.Join([]string{r.Method, r.URL.EscapedPath(), r.URL.Query().Encode(), r.Header.Get("X-D3-Timestamp"), hex.EncodeToString(sum[:])}, "\n")))
+		want := "HMAC-SHA256 KeyId=key-1, Signature=" + hex.EncodeToString(mac.Sum(nil))
+		if got := r.Header.Get("X-D3-Signature"); got != want {
+			t.Errorf("Expected signature %q, got %q", want, got)
+		}
+		signatures = append(signatures, r.Header.Get("X-D3-Signature"))
+		if len(signatures) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	signer, err := NewHMACSigner("key-1", secret)
+	if err != nil {
+		t.Fatalf("NewHMACSigner failed: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	signer.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	client, err := NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithSigner(signer),
+		WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: func(int) time.Duration { return 0 }}),
+		WithAuthScheme(AuthQueryParam))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("CheckSupportedOperation failed: %v", err)
+	}
+	if len(signatures) != 2 || signatures[0] == signatures[1] {
+		t.Errorf("Expected each attempt to be signed afresh, got %v", signatures)
+	}
+
+	if _, err := NewHMACSigner("", secret); err == nil {
+		t.Error("Expected an error for a missing key ID")
+	}
+}
@@ -0,0 +1,47 @@
+package d3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+)
+
+// Headers of server-side encryption with customer-provided keys (SSE-C)
+const (
+	sseCustomerAlgorithmHeader = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	sseCustomerKeyHeader       = "X-Amz-Server-Side-Encryption-Customer-Key"
+	sseCustomerKeyMD5Header    = "X-Amz-Server-Side-Encryption-Customer-Key-MD5"
+)
+
+// SSECustomerKey is a key for storage that encrypts with customer-provided
+// keys (SSE-C). Such storage rejects part uploads and completions that do
+// not carry the key, so it is sent with every presigned part PUT and the
+// complete-upload call. Set it with Config.SSECustomerKey.
+type SSECustomerKey struct {
+	// Key is the raw 256-bit key
+	Key []byte
+	// Algorithm names the cipher (default: "AES256")
+	Algorithm string
+}
+
+// validate checks the key's length
+func (k *SSECustomerKey) validate() error {
+	if len(k.Key) != 32 {
+		return NewD3ValidationError(fmt.Sprintf("SSE-C key must be 32 bytes, got %d", len(k.Key)), nil)
+	}
+	return nil
+}
+
+// headers returns the key, its MD5 and its algorithm as request headers
+func (k *SSECustomerKey) headers() map[string]string {
+	algorithm := k.Algorithm
+	if algorithm == "" {
+		algorithm = "AES256"
+	}
+	sum := md5.Sum(k.Key)
+	return map[string]string{
+		sseCustomerAlgorithmHeader: algorithm,
+		sseCustomerKeyHeader:       base64.StdEncoding.EncodeToString(k.Key),
+		sseCustomerKeyMD5Header:    base64.StdEncoding.EncodeToString(sum[:]),
+	}
+}
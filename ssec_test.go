@@ -0,0 +1,104 @@
+package d3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_UploadFile_SSECustomerKey(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 32)
+	sum := md5.Sum(key)
+	want := map[string]string{
+		"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+		"X-Amz-Server-Side-Encryption-Customer-Key":       base64.StdEncoding.EncodeToString(key),
+		"X-Amz-Server-Side-Encryption-Customer-Key-MD5":   base64.StdEncoding.EncodeToString(sum[:]),
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/biz/initiate-upload" {
+			for name, value := range want {
+				if got := r.Header.Get(name); got != value {
+					t.Errorf("Expected %s %q on %s, got %q", name, value, r.URL.Path, got)
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-1",
+					"upload_id":      "upload-id",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case "/part/1":
+			w.Header().Set("ETag", `"not-an-md5"`)
+		case "/v1/biz/complete-upload":
+			// SSE-C ETags are not MD5-based and must not fail the upload
+			w.Write([]byte(`{"data":{"etag":"not-an-md5"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, SSECustomerKey: &SSECustomerKey{Key: key}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "secret.pdf")
+	os.WriteFile(file, []byte("content"), 0644)
+	if _, err := client.UploadFile(UploadFileOptions{File: file, FileName: "secret.pdf"}); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	if got := redactHeader(http.Header{"X-Amz-Server-Side-Encryption-Customer-Key": {"secret"}}); got.Get("X-Amz-Server-Side-Encryption-Customer-Key") != redacted {
+		t.Errorf("Expected the SSE-C key to be redacted, got %v", got)
+	}
+
+	var validationErr *D3ValidationError
+	if _, err := NewDragdropdo(Config{APIKey: "test-key", SSECustomerKey: &SSECustomerKey{Key: []byte("short")}}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected D3ValidationError for a bad key, got %v", err)
+	}
+}
+
+func TestClient_HealthCheck_SSECustomerKey(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 32)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/part/1":
+			// Storage rejects SSE-C objects written without the key
+			if r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key") != base64.StdEncoding.EncodeToString(key) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`<Error><Code>InvalidRequest</Code><Message>missing customer key</Message></Error>`))
+				return
+			}
+			w.Header().Set("ETag", `"etag"`)
+		case "/v1/biz/initiate-upload":
+			w.Write([]byte(`{"data": {"file_key": "probe", "upload_id": "up-1", "presigned_urls": ["` + server.URL + `/part/1"]}}`))
+		default:
+			w.Write([]byte(`{"data": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, SSECustomerKey: &SSECustomerKey{Key: key}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	report, err := client.HealthCheck(context.Background())
+	if err != nil || !report.OK() {
+		t.Errorf("Expected the storage probe to pass with the customer key, got %v: %+v", err, report)
+	}
+}
@@ -0,0 +1,393 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StateStore persists the progress of uploads and operations so a process
+// that crashed or restarted can pick them up: UploadFile resumes a multipart
+// upload from its last stored part, and PendingJobs and Manager.Resume find
+// the operations submitted but not yet seen to finish. Set it with
+// Config.StateStore. Implementations must be safe for concurrent use.
+type StateStore interface {
+	// SaveUpload records the progress of the upload of state.File,
+	// replacing any earlier record of it
+	SaveUpload(ctx context.Context, state UploadState) error
+	// LoadUpload returns the progress of the upload of file, nil if none
+	// is recorded
+	LoadUpload(ctx context.Context, file string) (*UploadState, error)
+	// DeleteUpload forgets the upload of file
+	DeleteUpload(ctx context.Context, file string) error
+
+	// SaveJob records a submitted operation
+	SaveJob(ctx context.Context, state JobState) error
+	// LoadJobs returns the operations recorded, oldest first
+	LoadJobs(ctx context.Context) ([]JobState, error)
+	// DeleteJob forgets an operation once it has finished
+	DeleteJob(ctx context.Context, mainTaskID MainTaskID) error
+}
+
+// UploadState is the progress of a multipart upload
+type UploadState struct {
+	// File is the absolute path of the file uploaded; Size and ModTime
+	// tell whether it changed since, in which case the upload starts over
+	File     string    `json:"file"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	FileName string    `json:"file_name"`
+
+	FileKey    FileKey  `json:"file_key"`
+	UploadID   UploadID `json:"upload_id"`
+	ObjectName string   `json:"object_name,omitempty"`
+	// Parts is the number of parts the file is split into
+	Parts int `json:"parts"`
+	// Completed are the parts uploaded so far
+	Completed []UploadedPart `json:"completed"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// UploadedPart is a part stored by a multipart upload
+type UploadedPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+	// MD5 is the hex MD5 of the part's bytes, for ETag verification
+	MD5 string `json:"md5"`
+}
+
+// JobState is an operation submitted and not yet seen to finish
+type JobState struct {
+	MainTaskID  MainTaskID `json:"main_task_id"`
+	Action      Action     `json:"action"`
+	FileKeys    []FileKey  `json:"file_keys"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+}
+
+// FileStateStore is a StateStore keeping its state in a JSON file, written
+// atomically on every change. It suits a single process; several processes
+// must not share the file.
+type FileStateStore struct {
+	path string
+
+	mu sync.Mutex
+}
+
+// fileState is the content of a FileStateStore's file
+type fileState struct {
+	Uploads map[string]UploadState  `json:"uploads"`
+	Jobs    map[MainTaskID]JobState `json:"jobs"`
+}
+
+// NewFileStateStore returns a FileStateStore keeping its state in the file
+// at path, which is created on the first change
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{path: path}
+}
+
+// SaveUpload implements StateStore
+func (s *FileStateStore) SaveUpload(ctx context.Context, state UploadState) error {
+	return s.update(func(fs *fileState) { fs.Uploads[state.File] = state })
+}
+
+// LoadUpload implements StateStore
+func (s *FileStateStore) LoadUpload(ctx context.Context, file string) (*UploadState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	state, ok := fs.Uploads[file]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// DeleteUpload implements StateStore
+func (s *FileStateStore) DeleteUpload(ctx context.Context, file string) error {
+	return s.update(func(fs *fileState) { delete(fs.Uploads, file) })
+}
+
+// SaveJob implements StateStore
+func (s *FileStateStore) SaveJob(ctx context.Context, state JobState) error {
+	return s.update(func(fs *fileState) { fs.Jobs[state.MainTaskID] = state })
+}
+
+// LoadJobs implements StateStore
+func (s *FileStateStore) LoadJobs(ctx context.Context) ([]JobState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]JobState, 0, len(fs.Jobs))
+	for _, job := range fs.Jobs {
+		jobs = append(jobs, job)
+	}
+	sortJobStates(jobs)
+	return jobs, nil
+}
+
+// DeleteJob implements StateStore
+func (s *FileStateStore) DeleteJob(ctx context.Context, mainTaskID MainTaskID) error {
+	return s.update(func(fs *fileState) { delete(fs.Jobs, mainTaskID) })
+}
+
+// update applies change to the stored state and writes it back
+func (s *FileStateStore) update(change func(*fileState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, err := s.read()
+	if err != nil {
+		return err
+	}
+	change(fs)
+
+	data, err := json.MarshalIndent(fs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// read returns the stored state, empty if the file does not exist yet;
+// s.mu must be held
+func (s *FileStateStore) read() (*fileState, error) {
+	fs := &fileState{}
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, fs); err != nil {
+			return nil, fmt.Errorf("failed to parse state %s: %w", s.path, err)
+		}
+	}
+	if fs.Uploads == nil {
+		fs.Uploads = map[string]UploadState{}
+	}
+	if fs.Jobs == nil {
+		fs.Jobs = map[MainTaskID]JobState{}
+	}
+	return fs, nil
+}
+
+// sortJobStates orders jobs oldest first
+func sortJobStates(jobs []JobState) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].SubmittedAt.Equal(jobs[j].SubmittedAt) {
+			return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt)
+		}
+		return jobs[i].MainTaskID < jobs[j].MainTaskID
+	})
+}
+
+// PendingJobs returns the Jobs of the operations recorded in the
+// StateStore as submitted but not yet seen to finish, oldest first, so a
+// restarted process can wait for them. It returns none without a
+// StateStore.
+func (c *Dragdropdo) PendingJobs(ctx context.Context) ([]*Job, error) {
+	if c.stateStore == nil {
+		return nil, nil
+	}
+	states, err := c.stateStore.LoadJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, len(states))
+	for i, state := range states {
+		jobs[i] = c.Job(state.MainTaskID)
+	}
+	return jobs, nil
+}
+
+// saveJob records a submitted operation in the StateStore, if any
+func (c *Dragdropdo) saveJob(ctx context.Context, options OperationOptions, mainTaskID MainTaskID) {
+	if c.stateStore == nil {
+		return
+	}
+	c.stateFailed("save_job", c.stateStore.SaveJob(ctx, JobState{
+		MainTaskID:  mainTaskID,
+		Action:      options.Action,
+		FileKeys:    options.FileKeys,
+		SubmittedAt: time.Now(),
+	}))
+}
+
+// deleteJob forgets a finished operation in the StateStore, if any
+func (c *Dragdropdo) deleteJob(ctx context.Context, mainTaskID MainTaskID) {
+	if c.stateStore == nil {
+		return
+	}
+	c.stateFailed("delete_job", c.stateStore.DeleteJob(ctx, mainTaskID))
+}
+
+// stateFailed logs a failure to update the StateStore, which only costs the
+// ability to resume and so does not fail the call
+func (c *Dragdropdo) stateFailed(op string, err error) {
+	if err == nil {
+		return
+	}
+	c.logEvent(slog.LevelWarn, "state.failed", []slog.Attr{
+		slog.String("op", op),
+		slog.String("error", err.Error()),
+	})
+}
+
+// resumableUploads reports whether uploads are recorded in the StateStore.
+// Encrypted uploads are not: each is encrypted afresh, so parts stored by an
+// earlier run would not match.
+func (c *Dragdropdo) resumableUploads(ctx context.Context) bool {
+	return c.stateStore != nil && c.encryption == nil && !c.isDryRun(ctx)
+}
+
+// uploadRecorder keeps the progress of one upload in the StateStore
+type uploadRecorder struct {
+	c     *Dragdropdo
+	ctx   context.Context
+	state UploadState
+}
+
+// loadUpload returns the recorded progress of uploading options.File, nil
+// if there is none or the file changed since
+func (c *Dragdropdo) loadUpload(ctx context.Context, options UploadFileOptions, info os.FileInfo) *UploadState {
+	if !c.resumableUploads(ctx) {
+		return nil
+	}
+	file, err := filepath.Abs(options.File)
+	if err != nil {
+		return nil
+	}
+	saved, err := c.stateStore.LoadUpload(ctx, file)
+	if err != nil {
+		c.stateFailed("load_upload", err)
+		return nil
+	}
+	if saved == nil {
+		return nil
+	}
+	valid := saved.Size == info.Size() && saved.ModTime.Equal(info.ModTime()) &&
+		saved.FileName == options.FileName && saved.Parts > 0 && saved.UploadID != ""
+	for _, part := range saved.Completed {
+		valid = valid && part.PartNumber >= 1 && part.PartNumber <= saved.Parts
+	}
+	if !valid {
+		c.stateFailed("delete_upload", c.stateStore.DeleteUpload(ctx, file))
+		return nil
+	}
+	return saved
+}
+
+// recordUpload starts recording the progress of an initiated upload, nil
+// without a StateStore. completed are the parts stored by an earlier run.
+func (c *Dragdropdo) recordUpload(ctx context.Context, options UploadFileOptions, info os.FileInfo, upload AbortUploadOptions, parts int, completed []UploadedPart) *uploadRecorder {
+	if !c.resumableUploads(ctx) {
+		return nil
+	}
+	file, err := filepath.Abs(options.File)
+	if err != nil {
+		return nil
+	}
+	r := &uploadRecorder{
+		c: c,
+		// Progress is recorded, and forgotten, even as ctx is cancelled
+		ctx: context.WithoutCancel(ctx),
+		state: UploadState{
+			File:       file,
+			Size:       info.Size(),
+			ModTime:    info.ModTime(),
+			FileName:   options.FileName,
+			FileKey:    upload.FileKey,
+			UploadID:   upload.UploadID,
+			ObjectName: upload.ObjectName,
+			Parts:      parts,
+			Completed:  append([]UploadedPart(nil), completed...),
+		},
+	}
+	r.save()
+	return r
+}
+
+// partDone records a stored part; calls must be serialized
+func (r *uploadRecorder) partDone(part UploadedPart) {
+	if r == nil {
+		return
+	}
+	r.state.Completed = append(r.state.Completed, part)
+	r.save()
+}
+
+// forget removes the upload's record once it completed or was aborted
+func (r *uploadRecorder) forget() {
+	if r == nil {
+		return
+	}
+	r.c.stateFailed("delete_upload", r.c.stateStore.DeleteUpload(r.ctx, r.state.File))
+}
+
+func (r *uploadRecorder) save() {
+	r.state.UpdatedAt = time.Now()
+	r.c.stateFailed("save_upload", r.c.stateStore.SaveUpload(r.ctx, r.state))
+}
+
+// resumeUpload continues the upload recorded in saved, returning it with
+// fresh presigned URLs for the parts still to upload; the URLs of stored
+// parts are empty
+func (c *Dragdropdo) resumeUpload(ctx context.Context, options UploadFileOptions, saved *UploadState) (*UploadResponse, error) {
+	upload := AbortUploadOptions{FileKey: saved.FileKey, UploadID: saved.UploadID, ObjectName: saved.ObjectName}
+	stored := map[int]bool{}
+	for _, part := range saved.Completed {
+		stored[part.PartNumber] = true
+	}
+	var partNumbers []int
+	for n := 1; n <= saved.Parts; n++ {
+		if !stored[n] {
+			partNumbers = append(partNumbers, n)
+		}
+	}
+
+	urls := make([]string, saved.Parts)
+	if len(partNumbers) > 0 {
+		refreshed, err := c.refreshPresignedURLs(ctx, upload, partNumbers, options.Headers, options.RequestTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if len(refreshed) != len(partNumbers) {
+			return nil, newUploadError(ErrPresignedURLMismatch, fmt.Sprintf("requested %d presigned URLs to resume but received %d", len(partNumbers), len(refreshed)))
+		}
+		for j, n := range partNumbers {
+			urls[n-1] = refreshed[j]
+		}
+	}
+
+	resp := &UploadResponse{
+		FileKey:       saved.FileKey,
+		UploadID:      saved.UploadID,
+		ObjectName:    saved.ObjectName,
+		PresignedURLs: urls,
+	}
+	resp.syncAliases()
+	return resp, nil
+}
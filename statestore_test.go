@@ -0,0 +1,206 @@
+package d3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileStateStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "d3.json")
+	store := NewFileStateStore(path)
+
+	if saved, err := store.LoadUpload(ctx, "/data/a.pdf"); err != nil || saved != nil {
+		t.Errorf("Expected no upload before the file exists, got %v, %v", saved, err)
+	}
+
+	upload := UploadState{
+		File:      "/data/a.pdf",
+		Size:      3000,
+		FileKey:   "file-key-1",
+		UploadID:  "upload-id-1",
+		Parts:     3,
+		Completed: []UploadedPart{{PartNumber: 1, ETag: `"etag-1"`, MD5: "abc"}},
+	}
+	if err := store.SaveUpload(ctx, upload); err != nil {
+		t.Fatalf("SaveUpload failed: %v", err)
+	}
+	now := time.Now().UTC()
+	store.SaveJob(ctx, JobState{MainTaskID: "task-2", Action: ActionZip, SubmittedAt: now})
+	store.SaveJob(ctx, JobState{MainTaskID: "task-1", Action: ActionMerge, SubmittedAt: now.Add(-time.Minute)})
+
+	// A new store on the same file sees the same state
+	reopened := NewFileStateStore(path)
+	saved, err := reopened.LoadUpload(ctx, "/data/a.pdf")
+	if err != nil || saved == nil || !reflect.DeepEqual(*saved, upload) {
+		t.Errorf("Expected %+v, got %+v, %v", upload, saved, err)
+	}
+	jobs, err := reopened.LoadJobs(ctx)
+	if err != nil || len(jobs) != 2 || jobs[0].MainTaskID != "task-1" || jobs[1].MainTaskID != "task-2" {
+		t.Errorf("Expected task-1 and task-2, oldest first, got %+v, %v", jobs, err)
+	}
+
+	reopened.DeleteUpload(ctx, "/data/a.pdf")
+	reopened.DeleteJob(ctx, "task-1")
+	if saved, _ := store.LoadUpload(ctx, "/data/a.pdf"); saved != nil {
+		t.Errorf("Expected the upload to be deleted, got %+v", saved)
+	}
+	if jobs, _ := store.LoadJobs(ctx); len(jobs) != 1 || jobs[0].MainTaskID != "task-2" {
+		t.Errorf("Expected only task-2 left, got %+v", jobs)
+	}
+}
+
+func TestClient_UploadFile_ResumesFromStateStore(t *testing.T) {
+	content := strings.Repeat("a", 1000) + strings.Repeat("b", 1000) + strings.Repeat("c", 1000)
+	tmpFile := filepath.Join(t.TempDir(), "resume.bin")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	var mu sync.Mutex
+	var initiates int
+	var refreshed []int
+	failPart3 := true
+	sums := map[string][md5.Size]byte{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			initiates++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": []string{server.URL + "/part/1", server.URL + "/part/2", server.URL + "/part/3"},
+				},
+			})
+		case r.URL.Path == "/v1/biz/refresh-upload-urls":
+			var body struct {
+				PartNumbers []int `json:"part_numbers"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			refreshed = body.PartNumbers
+			urls := []string{}
+			for _, n := range body.PartNumbers {
+				urls = append(urls, fmt.Sprintf("%s/part/%d", server.URL, n))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"presigned_urls": urls}})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			if r.URL.Path == "/part/3" && failPart3 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			sums[r.URL.Path] = md5.Sum(body)
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, strings.TrimPrefix(r.URL.Path, "/part/")))
+		case r.URL.Path == "/v1/biz/complete-upload":
+			var body struct {
+				Parts []struct {
+					ETag       string `json:"etag"`
+					PartNumber int    `json:"part_number"`
+				} `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			for i, part := range body.Parts {
+				if want := fmt.Sprintf("etag-%d", i+1); part.PartNumber != i+1 || part.ETag != want {
+					t.Errorf("Expected part %d with ETag %s, got %+v", i+1, want, part)
+				}
+			}
+			etag := compositeETag([][md5.Size]byte{sums["/part/1"], sums["/part/2"], sums["/part/3"]})
+			fmt.Fprintf(w, `{"data":{"etag":%q}}`, etag)
+		}
+	}))
+	defer server.Close()
+
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, ChunkSize: 1000, StateStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	options := UploadFileOptions{File: tmpFile, FileName: "resume.bin"}
+	if _, err := client.UploadFile(options); err == nil {
+		t.Fatal("Expected the first upload to fail on part 3")
+	}
+	saved, _ := store.LoadUpload(context.Background(), tmpFile)
+	if saved == nil || len(saved.Completed) != 2 {
+		t.Fatalf("Expected 2 stored parts recorded, got %+v", saved)
+	}
+
+	mu.Lock()
+	failPart3 = false
+	mu.Unlock()
+	var progress []UploadProgress
+	options.OnProgress = func(p UploadProgress) { progress = append(progress, p) }
+	resp, err := client.UploadFile(options)
+	if err != nil {
+		t.Fatalf("Expected the resumed upload to succeed, got %v", err)
+	}
+	if resp.FileKey != "file-key-123" || initiates != 1 || !reflect.DeepEqual(refreshed, []int{3}) {
+		t.Errorf("Expected only part 3 to be requested again, got %d initiates, refreshed %v", initiates, refreshed)
+	}
+	if resp.FileKeyAlias != resp.FileKey || resp.UploadIDAlias != "upload-id-456" {
+		t.Errorf("Expected the resumed upload to fill the aliases, got %+v", resp)
+	}
+	if len(progress) != 1 || progress[0].CurrentPart != 3 || progress[0].BytesUploaded != 3000 {
+		t.Errorf("Expected progress to count the stored parts, got %+v", progress)
+	}
+	if saved, _ := store.LoadUpload(context.Background(), tmpFile); saved != nil {
+		t.Errorf("Expected the record to be removed after completion, got %+v", saved)
+	}
+}
+
+func TestClient_StateStoreJobs(t *testing.T) {
+	server, _ := newOperationServer(t, 0)
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, StateStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	job, err := client.Submit(ctx, OperationOptions{Action: ActionZip, FileKeys: []FileKey{"file-key-1"}})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// A restarted process finds the operation and waits for it
+	restarted, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, StateStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	pending, err := restarted.PendingJobs(ctx)
+	if err != nil || len(pending) != 1 || pending[0].ID() != job.ID() {
+		t.Fatalf("Expected pending job %s, got %v, %v", job.ID(), pending, err)
+	}
+	manager := NewManager(restarted, ManagerOptions{Wait: WaitOptions{Interval: 5 * time.Millisecond}})
+	ops, err := manager.Resume(ctx)
+	if err != nil || len(ops) != 1 {
+		t.Fatalf("Expected 1 resumed operation, got %v, %v", ops, err)
+	}
+	if results, err := ops[0].Wait(ctx); err != nil || len(results) != 1 {
+		t.Errorf("Expected the resumed operation's result, got %+v, %v", results, err)
+	}
+	if ops[0].Job().ID() != job.ID() {
+		t.Errorf("Expected the resumed operation to be %s, got %s", job.ID(), ops[0].Job().ID())
+	}
+
+	if pending, _ := restarted.PendingJobs(ctx); len(pending) != 0 {
+		t.Errorf("Expected no pending jobs once finished, got %v", pending)
+	}
+}
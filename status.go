@@ -0,0 +1,64 @@
+package d3
+
+// Operation statuses reported in StatusResponse.OperationStatus
+const (
+	StatusQueued  = "queued"
+	StatusRunning = "running"
+	// StatusCompleted means every file was processed successfully
+	StatusCompleted = "completed"
+	// StatusFailed means no file was processed successfully
+	StatusFailed = "failed"
+	// StatusPartiallyCompleted means the operation finished with some files
+	// completed and others failed; see FileTaskStatus for each file
+	StatusPartiallyCompleted = "partially_completed"
+	// StatusCancelled means the operation was cancelled before finishing
+	StatusCancelled = "cancelled"
+)
+
+// IsTerminalStatus reports whether an operation or file task in status has
+// finished and will not change again. The US spelling "canceled" is
+// accepted as well.
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case StatusCompleted, StatusFailed, StatusPartiallyCompleted, StatusCancelled, "canceled":
+		return true
+	}
+	return false
+}
+
+// Done reports whether the operation has finished, successfully or not
+func (r StatusResponse) Done() bool {
+	return IsTerminalStatus(r.OperationStatus)
+}
+
+// CompletedFiles returns the file tasks that completed successfully, e.g.
+// the usable results of a partially completed operation
+func (r StatusResponse) CompletedFiles() []FileTaskStatus {
+	return r.filesWithStatus(StatusCompleted)
+}
+
+// FailedFiles returns the file tasks that failed
+func (r StatusResponse) FailedFiles() []FileTaskStatus {
+	return r.filesWithStatus(StatusFailed)
+}
+
+// finishedFiles counts the file tasks of status that have finished
+func finishedFiles(status *StatusResponse) int {
+	n := 0
+	for _, file := range status.FilesData {
+		if IsTerminalStatus(file.Status) {
+			n++
+		}
+	}
+	return n
+}
+
+func (r StatusResponse) filesWithStatus(status string) []FileTaskStatus {
+	var files []FileTaskStatus
+	for _, file := range r.FilesData {
+		if file.Status == status {
+			files = append(files, file)
+		}
+	}
+	return files
+}
@@ -0,0 +1,60 @@
+package d3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_PollStatus_PartiallyCompleted(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		if polls == 1 {
+			w.Write([]byte(`{"data":{"operation_status":"running","files_data":[]}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"operation_status":"partially_completed","files_data":[
+			{"file_key":"file-1","status":"completed","download_link":"https://example.com/1"},
+			{"file_key":"file-2","status":"failed","error_message":"corrupt file"}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	status, err := client.PollStatus(PollStatusOptions{
+		StatusOptions: StatusOptions{MainTaskID: "task-123"},
+		Interval:      5 * time.Millisecond,
+		Timeout:       time.Second,
+	})
+	if err != nil {
+		t.Fatalf("PollStatus failed: %v", err)
+	}
+	if polls != 2 || status.OperationStatus != StatusPartiallyCompleted {
+		t.Errorf("Expected polling to stop at partially_completed after 2 polls, got %q after %d", status.OperationStatus, polls)
+	}
+	if completed := status.CompletedFiles(); len(completed) != 1 || completed[0].FileKey != "file-1" {
+		t.Errorf("Expected file-1 completed, got %+v", completed)
+	}
+	if failed := status.FailedFiles(); len(failed) != 1 || failed[0].ErrorMessage != "corrupt file" {
+		t.Errorf("Expected file-2 failed, got %+v", failed)
+	}
+}
+
+func TestIsTerminalStatus(t *testing.T) {
+	for _, status := range []string{StatusCompleted, StatusFailed, StatusPartiallyCompleted, StatusCancelled, "canceled"} {
+		if !IsTerminalStatus(status) {
+			t.Errorf("Expected %q to be terminal", status)
+		}
+	}
+	for _, status := range []string{StatusQueued, StatusRunning, "", "unknown"} {
+		if IsTerminalStatus(status) {
+			t.Errorf("Expected %q not to be terminal", status)
+		}
+	}
+}
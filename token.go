@@ -0,0 +1,87 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Token is a short-lived API credential
+type Token struct {
+	Value string
+	// Expiry is when the token stops being valid. A zero Expiry never expires.
+	Expiry time.Time
+}
+
+// TokenSource fetches fresh tokens, e.g. from an auth endpoint
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+// Token calls f
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// credentialsInvalidator is implemented by providers that cache credentials
+// and can drop them after the API rejects them with 401
+type credentialsInvalidator interface {
+	Invalidate()
+}
+
+// RefreshingCredentials is a CredentialsProvider that caches tokens from a
+// TokenSource and refreshes them shortly before they expire. When the API
+// still answers 401, the client invalidates the cached token and retries the
+// request once with a fresh one.
+type RefreshingCredentials struct {
+	source        TokenSource
+	refreshBefore time.Duration
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewRefreshingCredentials creates a provider refreshing tokens from source
+// refreshBefore ahead of their expiry (default: 1 minute)
+func NewRefreshingCredentials(source TokenSource, refreshBefore time.Duration) *RefreshingCredentials {
+	if refreshBefore <= 0 {
+		refreshBefore = time.Minute
+	}
+	return &RefreshingCredentials{
+		source:        source,
+		refreshBefore: refreshBefore,
+	}
+}
+
+// APIKey returns the cached token, fetching a new one if it is missing or
+// about to expire
+func (r *RefreshingCredentials) APIKey(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != nil && (r.token.Expiry.IsZero() || time.Now().Add(r.refreshBefore).Before(r.token.Expiry)) {
+		return r.token.Value, nil
+	}
+
+	token, err := r.source.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token == nil || token.Value == "" {
+		return "", errors.New("token source returned an empty token")
+	}
+	r.token = token
+
+	return token.Value, nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one
+func (r *RefreshingCredentials) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.token = nil
+}
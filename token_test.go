@@ -0,0 +1,65 @@
+package d3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRefreshingCredentials_RefreshBeforeExpiry(t *testing.T) {
+	fetches := 0
+	creds := NewRefreshingCredentials(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		fetches++
+		return &Token{Value: fmt.Sprintf("token-%d", fetches), Expiry: time.Now().Add(30 * time.Second)}, nil
+	}), 10*time.Second)
+
+	first, _ := creds.APIKey(context.Background())
+	second, _ := creds.APIKey(context.Background())
+	if first != "token-1" || second != "token-1" {
+		t.Errorf("Expected cached token, got '%s' and '%s'", first, second)
+	}
+
+	// A token expiring within the refresh window is replaced
+	creds.token.Expiry = time.Now().Add(5 * time.Second)
+	third, _ := creds.APIKey(context.Background())
+	if third != "token-2" {
+		t.Errorf("Expected refreshed token, got '%s'", third)
+	}
+}
+
+func TestClient_RefreshingCredentials_Retries401(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"operation_status":"completed"}}`))
+	}))
+	defer server.Close()
+
+	fetches := 0
+	client, err := NewClient("",
+		WithBaseURL(server.URL),
+		WithCredentials(NewRefreshingCredentials(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+			fetches++
+			return &Token{Value: fmt.Sprintf("token-%d", fetches), Expiry: time.Now().Add(time.Hour)}, nil
+		}), 0)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	status, err := client.GetStatus(StatusOptions{MainTaskID: "task-123"})
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if status.OperationStatus != "completed" || calls != 2 || fetches != 2 {
+		t.Errorf("Expected one retry with a fresh token, got status '%s' after %d calls, %d fetches", status.OperationStatus, calls, fetches)
+	}
+}
@@ -0,0 +1,174 @@
+package d3
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPVersion selects the HTTP protocol version negotiated over TLS
+type HTTPVersion int
+
+const (
+	// HTTPVersionAuto negotiates HTTP/2 when the server supports it
+	HTTPVersionAuto HTTPVersion = iota
+	// HTTPVersion1 forces HTTP/1.1, for endpoints that misbehave on HTTP/2
+	HTTPVersion1
+	// HTTPVersion2 attempts HTTP/2 even when a custom dialer or TLS
+	// configuration would otherwise disable it
+	HTTPVersion2
+)
+
+// ConnectionOptions tunes the connections used for API calls or presigned
+// part uploads. Zero fields keep the transport's defaults.
+type ConnectionOptions struct {
+	// HTTPVersion forces HTTP/1.1 or HTTP/2 (default: negotiated)
+	HTTPVersion HTTPVersion
+	// MaxIdleConns limits idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits idle connections kept per host (net/http
+	// default: 2, which is low for concurrent part uploads)
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits connections per host, including those in use
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept
+	IdleConnTimeout time.Duration
+	// KeepAlive is the TCP keep-alive probe interval. Negative disables TCP
+	// keep-alive probes.
+	KeepAlive time.Duration
+	// DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+	// ReadBufferSize and WriteBufferSize size the per-connection buffers
+	// (net/http default: 4KB); larger write buffers can speed up part uploads
+	ReadBufferSize  int
+	WriteBufferSize int
+}
+
+// tuneTransport returns transport with options applied, or transport itself
+// when there is nothing to apply. dialTimeout is kept when the dialer is
+// replaced to change KeepAlive.
+func tuneTransport(transport http.RoundTripper, options ConnectionOptions, dialTimeout time.Duration) (http.RoundTripper, error) {
+	if options == (ConnectionOptions{}) {
+		return transport, nil
+	}
+
+	t, err := cloneTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.MaxIdleConns > 0 {
+		t.MaxIdleConns = options.MaxIdleConns
+	}
+	if options.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	}
+	if options.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = options.MaxConnsPerHost
+	}
+	if options.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = options.IdleConnTimeout
+	}
+	if options.KeepAlive != 0 {
+		t.DialContext = newDialer(dialTimeout, options.KeepAlive).DialContext
+	}
+	if options.DisableKeepAlives {
+		t.DisableKeepAlives = true
+	}
+	if options.ReadBufferSize > 0 {
+		t.ReadBufferSize = options.ReadBufferSize
+	}
+	if options.WriteBufferSize > 0 {
+		t.WriteBufferSize = options.WriteBufferSize
+	}
+
+	switch options.HTTPVersion {
+	case HTTPVersion1:
+		// A non-nil, empty TLSNextProto disables HTTP/2
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.NextProtos = []string{"http/1.1"}
+	case HTTPVersion2:
+		t.ForceAttemptHTTP2 = true
+	}
+
+	return t, nil
+}
+
+// newHTTPTransport builds the base transport for API calls and presigned
+// part uploads, before ConnectionOptions are applied. A nil result means
+// http.DefaultTransport, which already honors HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY.
+func newHTTPTransport(config Config) (http.RoundTripper, error) {
+	transport := config.Transport
+	if transport == nil && config.HTTPClient != nil {
+		transport = config.HTTPClient.Transport
+	}
+	if config.ProxyURL == "" && config.TLSConfig == nil && config.DialTimeout == 0 &&
+		config.TLSHandshakeTimeout == 0 && config.ResponseHeaderTimeout == 0 {
+		return transport, nil
+	}
+
+	t, err := cloneTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if config.TLSConfig != nil {
+		t.TLSClientConfig = config.TLSConfig.Clone()
+	}
+
+	if config.DialTimeout > 0 {
+		t.DialContext = newDialer(config.DialTimeout, 0).DialContext
+	}
+	if config.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = config.TLSHandshakeTimeout
+	}
+	if config.ResponseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = config.ResponseHeaderTimeout
+	}
+
+	return t, nil
+}
+
+// newDialer returns a dialer with net/http's defaults for zero values
+func newDialer(timeout, keepAlive time.Duration) *net.Dialer {
+	timeout = durationOr(timeout, DefaultDialTimeout)
+	if keepAlive == 0 {
+		keepAlive = 30 * time.Second
+	}
+	return &net.Dialer{Timeout: timeout, KeepAlive: keepAlive}
+}
+
+// cloneTransport returns a copy of transport that can be tuned without
+// affecting the caller's value
+func cloneTransport(transport http.RoundTripper) (*http.Transport, error) {
+	switch t := transport.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport).Clone(), nil
+	case *http.Transport:
+		return t.Clone(), nil
+	default:
+		return nil, errors.New("transport settings require Transport to be an *http.Transport")
+	}
+}
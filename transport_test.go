@@ -0,0 +1,183 @@
+package d3
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClient_ProxyURL(t *testing.T) {
+	proxied := 0
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied++
+		if r.URL.Host != "api.example.test" {
+			t.Errorf("Expected proxied request for api.example.test, got '%s'", r.URL.Host)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient("test-key",
+		WithBaseURL("http://api.example.test"),
+		WithProxy(proxy.URL),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"})
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if !result.Supported || proxied != 1 {
+		t.Errorf("Expected request to go through the proxy, proxied %d", proxied)
+	}
+
+	if _, err := NewClient("test-key", WithProxy("ftp://proxy")); err == nil {
+		t.Error("Expected error for unsupported proxy scheme")
+	}
+}
+
+func TestNewClient_TLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	// Without the test CA the server certificate is rejected
+	client, err := NewClient("test-key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err == nil {
+		t.Error("Expected certificate verification error")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	client, err = NewClient("test-key",
+		WithBaseURL(server.URL),
+		WithTLSConfig(&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+}
+
+func TestNewClient_ConnectionOptions(t *testing.T) {
+	client, err := NewDragdropdo(Config{
+		APIKey:         "test-key",
+		APIConnections: ConnectionOptions{MaxIdleConnsPerHost: 4},
+		UploadConnections: ConnectionOptions{
+			MaxIdleConnsPerHost: 32,
+			MaxConnsPerHost:     64,
+			IdleConnTimeout:     time.Minute,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	transport, ok := client.partClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport for part uploads, got %T", client.partClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != 32 || transport.MaxConnsPerHost != 64 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("Upload connection options not applied: %+v", transport)
+	}
+	if http.DefaultTransport.(*http.Transport).MaxConnsPerHost == 64 {
+		t.Error("Expected the default transport to be left untouched")
+	}
+
+	if _, err := NewDragdropdo(Config{
+		APIKey:            "test-key",
+		Transport:         &countingTransport{},
+		UploadConnections: ConnectionOptions{MaxConnsPerHost: 8},
+	}); err == nil {
+		t.Error("Expected error tuning a non-*http.Transport")
+	}
+}
+
+func TestNewClient_HTTPVersion(t *testing.T) {
+	var proto string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.Proto
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	for _, tc := range []struct {
+		version  HTTPVersion
+		expected string
+	}{
+		{HTTPVersionAuto, "HTTP/2.0"},
+		{HTTPVersion1, "HTTP/1.1"},
+		{HTTPVersion2, "HTTP/2.0"},
+	} {
+		client, err := NewClient("test-key",
+			WithBaseURL(server.URL),
+			WithTLSConfig(&tls.Config{RootCAs: roots}),
+			WithAPIConnections(ConnectionOptions{HTTPVersion: tc.version}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if proto != tc.expected {
+			t.Errorf("Expected %s for HTTPVersion %d, got %s", tc.expected, tc.version, proto)
+		}
+	}
+}
+
+func TestNewClient_GranularTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"supported":true,"ext":"pdf"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:                "test-key",
+		BaseURL:               server.URL,
+		Timeout:               time.Minute,
+		DialTimeout:           5 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Millisecond,
+		UploadPartTimeout:     time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.CheckSupportedOperation(SupportedOperationOptions{Ext: "pdf"}); err == nil {
+		t.Error("Expected response header timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected the response header timeout to fire before the total timeout, took %v", elapsed)
+	}
+
+	if client.partClient.Timeout != time.Hour {
+		t.Errorf("Expected part upload timeout 1h, got %v", client.partClient.Timeout)
+	}
+	transport := client.partClient.Transport.(*http.Transport)
+	if transport.ResponseHeaderTimeout != 20*time.Millisecond {
+		t.Errorf("Expected part uploads to share the response header timeout, got %v", transport.ResponseHeaderTimeout)
+	}
+}
@@ -0,0 +1,145 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// newBenchmarkServer serves the upload endpoints, discarding part bodies,
+// so benchmarks measure the client's side of the multipart engine
+func newBenchmarkServer(b *testing.B) *httptest.Server {
+	b.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			var body struct {
+				Parts int `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			urls := make([]string, body.Parts)
+			for i := range urls {
+				urls[i] = fmt.Sprintf("%s/part/%d", server.URL, i+1)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       "file-key-123",
+					"upload_id":      "upload-id-456",
+					"presigned_urls": urls,
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/part/"):
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.Write([]byte(`{"data":{"file_key":"file-key-123"}}`))
+		}
+	}))
+	b.Cleanup(server.Close)
+	return server
+}
+
+// reportUploadAllocs reports, next to the usual -benchmem figures, the
+// bytes allocated per part uploaded and per byte uploaded ("copies/MB": how
+// many times each megabyte of the file was copied into fresh memory). Both
+// include the test server's allocations, which are small and constant.
+func reportUploadAllocs(b *testing.B, before *runtime.MemStats, size int64, parts int) {
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	allocated := float64(after.TotalAlloc - before.TotalAlloc)
+	b.ReportMetric(allocated/float64(b.N*parts), "B/part")
+	b.ReportMetric(allocated/float64(int64(b.N)*size), "copies/MB")
+}
+
+func BenchmarkUploadFile(b *testing.B) {
+	const mb = 1024 * 1024
+	cases := []struct {
+		name        string
+		size        int64
+		chunkSize   int64
+		concurrency int
+	}{
+		{"1MB", mb, 5 * mb, 1},
+		{"32MB_5MBParts", 32 * mb, 5 * mb, 1},
+		{"32MB_5MBParts_Concurrent", 32 * mb, 5 * mb, 4},
+		{"64MB_16MBParts", 64 * mb, 16 * mb, 4},
+	}
+
+	server := newBenchmarkServer(b)
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			file := filepath.Join(b.TempDir(), "bench.bin")
+			if err := os.WriteFile(file, nil, 0644); err != nil {
+				b.Fatal(err)
+			}
+			if err := os.Truncate(file, bc.size); err != nil {
+				b.Fatal(err)
+			}
+			client, err := NewDragdropdo(Config{
+				APIKey:            "test-key",
+				BaseURL:           server.URL,
+				ChunkSize:         bc.chunkSize,
+				UploadConcurrency: bc.concurrency,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			parts := int((bc.size + bc.chunkSize - 1) / bc.chunkSize)
+			options := UploadFileOptions{File: file, FileName: "bench.bin"}
+
+			b.SetBytes(bc.size)
+			b.ReportAllocs()
+			var before runtime.MemStats
+			runtime.ReadMemStats(&before)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.UploadFile(options); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			reportUploadAllocs(b, &before, bc.size, parts)
+		})
+	}
+}
+
+func BenchmarkUploadPart(b *testing.B) {
+	const size = 5 * 1024 * 1024
+	server := newBenchmarkServer(b)
+	file := filepath.Join(b.TempDir(), "part.bin")
+	if err := os.WriteFile(file, make([]byte, size), 0644); err != nil {
+		b.Fatal(err)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(size)
+	b.ReportAllocs()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := client.uploadPart(context.Background(), f, server.URL+"/part/1", 0, size, "application/octet-stream", 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	reportUploadAllocs(b, &before, size, 1)
+}
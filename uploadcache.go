@@ -0,0 +1,131 @@
+package d3
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UploadCache remembers which file key content was uploaded as, keyed by
+// the hex SHA-256 of the bytes and the upload's extension, MIME type and
+// folder, so UploadFile can return the earlier key instead of uploading
+// identical bytes again. Set it with
+// Config.UploadCache. Implementations must be safe for concurrent use and
+// decide how long entries stay valid; stored files expire on the server, so
+// entries should not outlive them.
+type UploadCache interface {
+	// Get returns the file key stored for key, if any and still valid
+	Get(ctx context.Context, key string) (FileKey, bool, error)
+	// Put stores fileKey for key
+	Put(ctx context.Context, key string, fileKey FileKey) error
+}
+
+// MemoryUploadCache is an in-memory UploadCache whose entries expire a TTL
+// after they were stored
+type MemoryUploadCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]uploadCacheEntry
+}
+
+type uploadCacheEntry struct {
+	fileKey FileKey
+	expires time.Time
+}
+
+// NewMemoryUploadCache returns a MemoryUploadCache whose entries are valid
+// for ttl, or DefaultUploadCacheTTL if not positive
+func NewMemoryUploadCache(ttl time.Duration) *MemoryUploadCache {
+	return &MemoryUploadCache{
+		ttl:     durationOr(ttl, DefaultUploadCacheTTL),
+		now:     time.Now,
+		entries: map[string]uploadCacheEntry{},
+	}
+}
+
+// Get implements UploadCache
+func (m *MemoryUploadCache) Get(ctx context.Context, key string) (FileKey, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+	if !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return "", false, nil
+	}
+	return entry.fileKey, true, nil
+}
+
+// Put implements UploadCache. Expired entries are dropped as it goes.
+func (m *MemoryUploadCache) Put(ctx context.Context, key string, fileKey FileKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for k, entry := range m.entries {
+		if !now.Before(entry.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = uploadCacheEntry{fileKey: fileKey, expires: now.Add(m.ttl)}
+	return nil
+}
+
+// cachedUpload looks the content of options.File up in the UploadCache. It
+// returns the response for a cached upload, nil if there is none, and the
+// key to cache a new upload under, empty if the file could not be hashed.
+// Uploads with a different extension, MimeType or folder are cached
+// separately, since the server stores the file under its name and type.
+func (c *Dragdropdo) cachedUpload(ctx context.Context, options UploadFileOptions) (*UploadResponse, string) {
+	record, err := hashFile(options.File)
+	if err != nil {
+		c.uploadCacheFailed(err)
+		return nil, ""
+	}
+	ext := strings.ToLower(filepath.Ext(options.FileName))
+	key := record.SHA256 + ":" + ext + ":" + options.MimeType
+	if options.FolderID != "" {
+		key += ":" + options.FolderID
+	}
+
+	fileKey, ok, err := c.uploadCache.Get(ctx, key)
+	if err != nil {
+		c.uploadCacheFailed(err)
+		return nil, key
+	}
+	if !ok {
+		return nil, key
+	}
+
+	c.logInfo("upload.cached",
+		slog.String("file_key", string(fileKey)),
+		slog.String("file_name", options.FileName),
+		slog.Int64("size", record.Size))
+	if options.OnProgress != nil {
+		options.OnProgress(UploadProgress{
+			BytesUploaded: record.Size,
+			TotalBytes:    record.Size,
+			Percentage:    100,
+		})
+	}
+	if c.preflight != nil {
+		c.preflight.rememberFile(fileKey, options.FileName)
+	}
+	resp := &UploadResponse{FileKey: fileKey}
+	resp.syncAliases()
+	return resp, key
+}
+
+// uploadCacheFailed logs a failure of the UploadCache, which only costs a
+// repeated upload and so does not fail the call
+func (c *Dragdropdo) uploadCacheFailed(err error) {
+	c.logEvent(slog.LevelWarn, "upload_cache.failed", []slog.Attr{
+		slog.String("error", err.Error()),
+	})
+}
@@ -0,0 +1,103 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryUploadCache_Expires(t *testing.T) {
+	now := time.Now()
+	cache := NewMemoryUploadCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	cache.Put(ctx, "sum", "file-key-1")
+	if key, ok, _ := cache.Get(ctx, "sum"); !ok || key != "file-key-1" {
+		t.Errorf("Expected file-key-1, got %q, %v", key, ok)
+	}
+	now = now.Add(time.Minute)
+	if key, ok, _ := cache.Get(ctx, "sum"); ok {
+		t.Errorf("Expected the entry to expire, got %q", key)
+	}
+	if _, ok, _ := cache.Get(ctx, "other"); ok {
+		t.Error("Expected no entry for unknown content")
+	}
+}
+
+func TestClient_UploadFile_Cache(t *testing.T) {
+	var uploads int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/biz/initiate-upload":
+			uploads++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"file_key":       fmt.Sprintf("file-key-%d", uploads),
+					"upload_id":      "upload-id",
+					"presigned_urls": []string{server.URL + "/part/1"},
+				},
+			})
+		case "/part/1":
+			w.Header().Set("ETag", `"etag"`)
+		case "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL, UploadCache: NewMemoryUploadCache(0)})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.txt")
+	second := filepath.Join(dir, "b.txt")
+	os.WriteFile(first, []byte("same bytes"), 0644)
+	os.WriteFile(second, []byte("same bytes"), 0644)
+
+	upload := func(options UploadFileOptions) FileKey {
+		t.Helper()
+		resp, err := client.UploadFile(options)
+		if err != nil {
+			t.Fatalf("Upload failed: %v", err)
+		}
+		return resp.FileKey
+	}
+
+	upload(UploadFileOptions{File: first, FileName: "a.txt"})
+	var progress UploadProgress
+	key := upload(UploadFileOptions{File: second, FileName: "b.txt", OnProgress: func(p UploadProgress) { progress = p }})
+	if key != "file-key-1" || uploads != 1 {
+		t.Errorf("Expected identical content to reuse file-key-1, got %s after %d uploads", key, uploads)
+	}
+	resp, err := client.UploadFile(UploadFileOptions{File: second, FileName: "b.txt"})
+	if err != nil || resp.FileKeyAlias != "file-key-1" {
+		t.Errorf("Expected a cached upload to fill FileKeyAlias, got %+v, %v", resp, err)
+	}
+	if progress.Percentage != 100 || progress.BytesUploaded != 10 {
+		t.Errorf("Expected a cached upload to report completion, got %+v", progress)
+	}
+
+	if key := upload(UploadFileOptions{File: second, FileName: "b.txt", SkipCache: true}); key != "file-key-2" {
+		t.Errorf("Expected SkipCache to upload again, got %s", key)
+	}
+	if key := upload(UploadFileOptions{File: second, FileName: "b.txt", FolderID: "folder-1"}); key != "file-key-3" {
+		t.Errorf("Expected another folder to upload again, got %s", key)
+	}
+	if key := upload(UploadFileOptions{File: second, FileName: "b.md"}); key != "file-key-4" {
+		t.Errorf("Expected another extension to upload again, got %s", key)
+	}
+	if key := upload(UploadFileOptions{File: second, FileName: "b.txt", MimeType: "text/csv"}); key != "file-key-5" {
+		t.Errorf("Expected another MIME type to upload again, got %s", key)
+	}
+}
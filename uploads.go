@@ -0,0 +1,139 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PendingUpload represents a multipart upload that was initiated but never
+// completed or aborted
+type PendingUpload struct {
+	FileKey     FileKey   `json:"file_key"`
+	UploadID    UploadID  `json:"upload_id"`
+	ObjectName  string    `json:"object_name,omitempty"`
+	FileName    string    `json:"file_name,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	InitiatedAt time.Time `json:"initiated_at"`
+}
+
+// AbortUploadOptions represents options for aborting a multipart upload
+type AbortUploadOptions struct {
+	FileKey    FileKey
+	UploadID   UploadID
+	ObjectName string
+}
+
+// pendingUploadsResponse is the data of a pending uploads response
+type pendingUploadsResponse struct {
+	Uploads []PendingUpload `json:"uploads"`
+}
+
+// ListPendingUploads lists multipart uploads that are still in progress
+func (c *Dragdropdo) ListPendingUploads() ([]PendingUpload, error) {
+	resp, err := fetch[pendingUploadsResponse](c.newRequest(), http.MethodGet, "/v1/biz/pending-uploads")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending uploads: %w", err)
+	}
+
+	return resp.Uploads, nil
+}
+
+// AbortUpload aborts a multipart upload and releases its stored parts
+func (c *Dragdropdo) AbortUpload(options AbortUploadOptions) error {
+	var v validation
+	if options.FileKey == "" {
+		v.add("file_key", "file_key is required")
+	}
+	if options.UploadID == "" {
+		v.add("upload_id", "upload_id is required")
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	return c.abortUpload(context.Background(), options)
+}
+
+// abortUpload aborts a multipart upload using ctx
+func (c *Dragdropdo) abortUpload(ctx context.Context, options AbortUploadOptions) error {
+	_, err := c.newRequest().
+		SetContext(ctx).
+		SetBody(map[string]interface{}{
+			"file_key":    options.FileKey,
+			"upload_id":   options.UploadID,
+			"object_name": options.ObjectName,
+		}).
+		Post("/v1/biz/abort-upload")
+
+	if err != nil {
+		return fmt.Errorf("failed to abort upload: %w", err)
+	}
+
+	return nil
+}
+
+// cancelUpload returns the error for an upload whose context ended after
+// partsDone of totalParts parts. When the caller cancelled it (parent is
+// done) the multipart upload is aborted here; otherwise Close is cancelling
+// operations and aborts it itself.
+func (c *Dragdropdo) cancelUpload(parent, ctx context.Context, upload AbortUploadOptions, partsDone, totalParts int) *D3UploadError {
+	cause := ctx.Err()
+	message := fmt.Sprintf("upload cancelled after %d of %d parts: %v", partsDone, totalParts, cause)
+	if parent.Err() != nil {
+		c.life.untrackUpload(upload.UploadID)
+		abortCtx := context.WithValue(context.Background(), operationContextKey{}, true)
+		if err := c.abortUpload(abortCtx, upload); err != nil {
+			cause = errors.Join(cause, err)
+			message += fmt.Sprintf(" (%v)", err)
+		}
+	}
+
+	e := newUploadError(cause, message)
+	e.PartsCompleted = partsDone
+	e.TotalParts = totalParts
+	return e
+}
+
+// AbortStaleUploads aborts every pending upload initiated more than olderThan
+// ago and returns the uploads that were aborted. Aborting continues past
+// individual failures; the returned error then reports how many failed.
+func (c *Dragdropdo) AbortStaleUploads(olderThan time.Duration) ([]PendingUpload, error) {
+	pending, err := c.ListPendingUploads()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	aborted := []PendingUpload{}
+	failed := 0
+	var firstErr error
+
+	for _, upload := range pending {
+		if !upload.InitiatedAt.Before(cutoff) {
+			continue
+		}
+		err := c.AbortUpload(AbortUploadOptions{
+			FileKey:    upload.FileKey,
+			UploadID:   upload.UploadID,
+			ObjectName: upload.ObjectName,
+		})
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		aborted = append(aborted, upload)
+	}
+
+	if failed > 0 {
+		return aborted, fmt.Errorf("failed to abort %d stale uploads: %w", failed, firstErr)
+	}
+
+	return aborted, nil
+}
@@ -0,0 +1,63 @@
+package d3
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_AbortStaleUploads(t *testing.T) {
+	aborted := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response map[string]interface{}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/biz/pending-uploads":
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"uploads": []map[string]interface{}{
+						{"file_key": "stale", "upload_id": "upload-1", "initiated_at": time.Now().Add(-48 * time.Hour)},
+						{"file_key": "fresh", "upload_id": "upload-2", "initiated_at": time.Now().Add(-time.Minute)},
+					},
+				},
+			}
+		case r.Method == "POST" && r.URL.Path == "/v1/biz/abort-upload":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+				return
+			}
+			aborted = append(aborted, body["upload_id"].(string))
+			response = map[string]interface{}{
+				"data": map[string]interface{}{},
+			}
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	result, err := client.AbortStaleUploads(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to abort stale uploads: %v", err)
+	}
+
+	if len(result) != 1 || result[0].FileKey != "stale" {
+		t.Errorf("Expected only the stale upload to be aborted, got %+v", result)
+	}
+	if len(aborted) != 1 || aborted[0] != "upload-1" {
+		t.Errorf("Expected abort-upload for upload-1, got %v", aborted)
+	}
+}
@@ -0,0 +1,60 @@
+package d3
+
+import (
+	"errors"
+	"strings"
+)
+
+// maxParts is the most parts a multipart upload may have
+const maxParts = 100
+
+// FieldError describes one invalid input. The Details of a
+// D3ValidationError for invalid call options are a []FieldError listing
+// every invalid input, not just the first.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// Err is the underlying cause, if any, e.g. ErrFileNotFound
+	Err error `json:"-"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// validation collects invalid inputs so they are reported together
+type validation struct {
+	fields []FieldError
+}
+
+// add records an invalid field
+func (v *validation) add(field, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message})
+}
+
+// addErr records an invalid field caused by err
+func (v *validation) addErr(field string, err error, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message, Err: err})
+}
+
+// err returns nil if no field was invalid, otherwise a D3ValidationError
+// whose message joins the field messages and whose Details are the
+// []FieldError. errors.Is matches the fields' causes.
+func (v *validation) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(v.fields))
+	var causes []error
+	for i, field := range v.fields {
+		messages[i] = field.Message
+		if field.Err != nil {
+			causes = append(causes, field.Err)
+		}
+	}
+
+	e := NewD3ValidationError(strings.Join(messages, "; "), v.fields)
+	e.Err = errors.Join(causes...)
+	return e
+}
@@ -0,0 +1,60 @@
+package d3
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClient_UploadFile_ReportsAllInvalidInputs(t *testing.T) {
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.UploadFile(UploadFileOptions{File: "does-not-exist.pdf", Parts: 101})
+	var validationErr *D3ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *D3ValidationError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("Expected errors.Is to match ErrFileNotFound, got %v", err)
+	}
+
+	fields, ok := validationErr.Details.([]FieldError)
+	if !ok {
+		t.Fatalf("Expected []FieldError details, got %T", validationErr.Details)
+	}
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.Field)
+	}
+	if got := strings.Join(names, ","); got != "file_name,file,parts" {
+		t.Errorf("Expected fields file_name,file,parts, got %s", got)
+	}
+	if !strings.Contains(err.Error(), "file_name is required; file not found") {
+		t.Errorf("Expected the message to list every invalid input, got %q", err.Error())
+	}
+}
+
+func TestClient_CreateOperation_ReportsAllInvalidInputs(t *testing.T) {
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.ResetPdfPassword(nil, "", "", nil)
+	var validationErr *D3ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *D3ValidationError, got %T: %v", err, err)
+	}
+	fields, _ := validationErr.Details.([]FieldError)
+	if len(fields) != 3 {
+		t.Errorf("Expected 3 field errors, got %v", fields)
+	}
+
+	err = client.AbortUpload(AbortUploadOptions{UploadID: "upload-id"})
+	if !errors.As(err, &validationErr) || validationErr.Message != "file_key is required" {
+		t.Errorf("Expected a single field error to keep its message, got %v", err)
+	}
+}
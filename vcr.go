@@ -0,0 +1,216 @@
+package d3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects whether a Cassette records or replays
+type CassetteMode int
+
+const (
+	// CassetteReplay answers requests from the cassette file only, so tests
+	// run without network access or credentials
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests to the network and records them,
+	// replacing the cassette file on Save
+	CassetteRecord
+	// CassetteAuto replays if the cassette file exists and records
+	// otherwise
+	CassetteAuto
+)
+
+// Cassette is an http.RoundTripper that records real API exchanges to a
+// file and replays them in tests, VCR style. Credentials, passwords and URL
+// signatures are scrubbed before anything is written. Use it as
+// Config.Transport (or with WithTransport); in replay mode any API key
+// will do.
+//
+//	cassette, err := d3.NewCassette("testdata/convert.json", d3.CassetteAuto, nil)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer cassette.Save()
+//	client, err := d3.NewClient(os.Getenv("D3_API_KEY"), d3.WithTransport(cassette))
+//
+// Requests are matched on method and URL, with signatures scrubbed, in
+// recorded order, so repeated calls such as status polls replay in turn.
+type Cassette struct {
+	path      string
+	recording bool
+	next      http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a scrubbed request in a cassette
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a scrubbed response in a cassette
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// cassetteFile is the JSON layout of a cassette file
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NewCassette opens the cassette at path in mode. Recorded requests are
+// sent with next (default: http.DefaultTransport).
+func NewCassette(path string, mode CassetteMode, next http.RoundTripper) (*Cassette, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c := &Cassette{path: path, next: next}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case mode == CassetteRecord, mode == CassetteAuto && errors.Is(err, os.ErrNotExist):
+		c.recording = true
+		return c, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return c, nil
+}
+
+// Recording reports whether the cassette records rather than replays
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+// RoundTrip records or replays req
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    redactURL(req.URL.String()),
+		Header: redactHeader(req.Header),
+	}
+	if len(body) > 0 {
+		recorded.Body = redactBody(body, 0)
+	}
+
+	if c.recording {
+		return c.record(req, recorded)
+	}
+	return c.replay(req, recorded)
+}
+
+func (c *Cassette) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+		},
+	}
+	if len(body) > 0 {
+		interaction.Response.Body = redactBody(body, 0)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, interaction)
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Request.Method != recorded.Method || interaction.Request.URL != recorded.URL {
+			continue
+		}
+		c.used[i] = true
+
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoRecording, recorded.Method, recorded.URL)
+}
+
+// Save writes the recorded interactions to the cassette file, creating its
+// directory. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if !c.recording {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
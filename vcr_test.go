@@ -0,0 +1,87 @@
+package d3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(tmpFile, []byte("hello"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	cassettePath := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/biz/initiate-upload":
+			w.Write([]byte(`{"data":{"file_key":"file-key-123","upload_id":"upload-id-456","presigned_urls":["` + server.URL + `/part/1?X-Amz-Signature=secret-sig"]}}`))
+		case r.URL.Path == "/part/1":
+			w.Header().Set("ETag", `"etag-1"`)
+		case r.URL.Path == "/v1/biz/complete-upload":
+			w.Write([]byte(`{"data":{"file_key":"file-key-123"}}`))
+		case r.URL.Path == "/v1/biz/do":
+			w.Write([]byte(`{"data":{"main_task_id":"task-123"}}`))
+		}
+	}))
+
+	recorder, err := NewCassette(cassettePath, CassetteAuto, nil)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	if !recorder.Recording() {
+		t.Fatal("Expected a missing cassette to record")
+	}
+	client, err := NewClient("live-secret-key", WithBaseURL(server.URL), WithTransport(recorder))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "doc.txt"}); err != nil {
+		t.Fatalf("Recorded upload failed: %v", err)
+	}
+	if _, err := client.LockPdf([]FileKey{"file-key-123"}, "pdf-secret", nil); err != nil {
+		t.Fatalf("Recorded operation failed: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Failed to save cassette: %v", err)
+	}
+	server.Close()
+
+	data, _ := os.ReadFile(cassettePath)
+	for _, secret := range []string{"live-secret-key", "secret-sig", "pdf-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be scrubbed from the cassette", secret)
+		}
+	}
+
+	player, err := NewCassette(cassettePath, CassetteAuto, nil)
+	if err != nil {
+		t.Fatalf("Failed to open cassette: %v", err)
+	}
+	if player.Recording() {
+		t.Fatal("Expected an existing cassette to replay")
+	}
+	client, err = NewClient("any-key", WithBaseURL(server.URL), WithTransport(player))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	upload, err := client.UploadFile(UploadFileOptions{File: tmpFile, FileName: "doc.txt"})
+	if err != nil || upload.FileKey != "file-key-123" {
+		t.Fatalf("Expected the upload to replay, got %+v, %v", upload, err)
+	}
+	op, err := client.LockPdf([]FileKey{"file-key-123"}, "pdf-secret", nil)
+	if err != nil || op.MainTaskID != "task-123" {
+		t.Fatalf("Expected the operation to replay, got %+v, %v", op, err)
+	}
+
+	if _, err := client.GetUsage(); !errors.Is(err, ErrNoRecording) {
+		t.Errorf("Expected ErrNoRecording for an unrecorded request, got %v", err)
+	}
+}
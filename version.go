@@ -0,0 +1,19 @@
+package d3
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Version is the version of this client library
+const Version = "1.1.0"
+
+// userAgent returns the User-Agent sent with every request, e.g.
+// "d3-go-client/v1.1.0 (linux/amd64; go1.21.0) my-app/2.3"
+func userAgent(suffix string) string {
+	ua := fmt.Sprintf("d3-go-client/v%s (%s/%s; %s)", Version, runtime.GOOS, runtime.GOARCH, runtime.Version())
+	if suffix != "" {
+		ua += " " + suffix
+	}
+	return ua
+}
@@ -0,0 +1,85 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WaitOptions represents options for WaitForOperation
+type WaitOptions struct {
+	// Interval and Timeout override the client's polling defaults
+	Interval time.Duration
+	Timeout  time.Duration
+	// OnUpdate is called with each status received
+	OnUpdate func(StatusResponse)
+	// Progress follows the files of the operation as they finish
+	Progress ProgressReporter
+	// Headers and RequestTimeout apply to each status request
+	Headers        map[string]string
+	RequestTimeout time.Duration
+}
+
+// PartialFailure is returned by WaitForOperation when the operation finished
+// without every file completing: partially completed, failed or cancelled.
+// The results are returned with it, so completed outputs remain usable.
+type PartialFailure struct {
+	MainTaskID      MainTaskID
+	OperationStatus string
+	// Completed are the results of the files that completed, Failed those
+	// of the files that did not
+	Completed []Result
+	Failed    []Result
+}
+
+func (e *PartialFailure) Error() string {
+	message := fmt.Sprintf("operation %s %s: %d of %d files did not complete",
+		e.MainTaskID, e.OperationStatus, len(e.Failed), len(e.Completed)+len(e.Failed))
+	if len(e.Failed) > 0 && e.Failed[0].ErrorMessage != "" {
+		message += fmt.Sprintf(" (%s: %s)", e.Failed[0].FileKey, e.Failed[0].ErrorMessage)
+	}
+	return message
+}
+
+// WaitForOperation polls the operation until it finishes, as PollStatus
+// does, and returns the Result of each file. If any file did not complete,
+// the results are returned together with a *PartialFailure. Outputs that
+// could not be probed, see Results, are reported in the error as well.
+func (c *Dragdropdo) WaitForOperation(ctx context.Context, mainTaskID MainTaskID, opts WaitOptions) ([]Result, error) {
+	status, err := c.pollStatus(ctx, PollStatusOptions{
+		StatusOptions: StatusOptions{
+			MainTaskID:     mainTaskID,
+			Headers:        opts.Headers,
+			RequestTimeout: opts.RequestTimeout,
+		},
+		Interval: opts.Interval,
+		Timeout:  opts.Timeout,
+		OnUpdate: opts.OnUpdate,
+		Progress: opts.Progress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results, resolveErr := c.Results(ctx, status)
+
+	failure := &PartialFailure{
+		MainTaskID:      mainTaskID,
+		OperationStatus: status.OperationStatus,
+	}
+	for _, result := range results {
+		if result.Status == StatusCompleted {
+			failure.Completed = append(failure.Completed, result)
+		} else {
+			failure.Failed = append(failure.Failed, result)
+		}
+	}
+	if len(failure.Failed) > 0 || status.OperationStatus != StatusCompleted {
+		if resolveErr != nil {
+			return results, errors.Join(failure, resolveErr)
+		}
+		return results, failure
+	}
+	return results, resolveErr
+}
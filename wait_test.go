@@ -0,0 +1,147 @@
+package d3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForOperation(t *testing.T) {
+	var polls int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/biz/status/task-123":
+			status := StatusRunning
+			if atomic.AddInt32(&polls, 1) > 1 {
+				status = StatusPartiallyCompleted
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"operation_status": status,
+					"files_data": []map[string]interface{}{
+						{"file_key": "file-key-1", "status": StatusCompleted, "download_link": server.URL + "/files/out.pdf"},
+						{"file_key": "file-key-2", "status": StatusFailed, "error_message": "corrupt input"},
+					},
+				},
+			})
+		case "/files/out.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF"))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var updates int
+	results, err := client.WaitForOperation(context.Background(), "task-123", WaitOptions{
+		Interval: 10 * time.Millisecond,
+		OnUpdate: func(StatusResponse) { updates++ },
+	})
+	var failure *PartialFailure
+	if !errors.As(err, &failure) {
+		t.Fatalf("Expected PartialFailure, got %v", err)
+	}
+	if updates != 2 {
+		t.Errorf("Expected 2 updates, got %d", updates)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Name != "out.pdf" || results[0].Size != 4 {
+		t.Errorf("Expected out.pdf of 4 bytes, got %q of %d bytes", results[0].Name, results[0].Size)
+	}
+	if failure.OperationStatus != StatusPartiallyCompleted || len(failure.Completed) != 1 || len(failure.Failed) != 1 {
+		t.Errorf("Unexpected partial failure: %+v", failure)
+	}
+	if !strings.Contains(failure.Error(), "corrupt input") {
+		t.Errorf("Expected error to name the failure, got %q", failure.Error())
+	}
+}
+
+func TestClient_WaitForOperation_Completed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"operation_status": "completed", "files_data": [{"file_key": "file-key-1", "status": "completed"}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	results, err := client.WaitForOperation(context.Background(), "task-123", WaitOptions{})
+	if err != nil {
+		t.Fatalf("Failed to wait for operation: %v", err)
+	}
+	if len(results) != 1 || results[0].FileKey != "file-key-1" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+func TestClient_WaitForOperation_ProbeFailure(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/files/out.pdf" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"operation_status": StatusCompleted,
+				"files_data": []map[string]interface{}{
+					{"file_key": "file-key-1", "status": StatusCompleted, "download_link": server.URL + "/files/out.pdf"},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	results, err := client.WaitForOperation(context.Background(), "task-123", WaitOptions{})
+	if err == nil || !strings.Contains(err.Error(), "file-key-1") {
+		t.Errorf("Expected the probe failure to be reported, got %v", err)
+	}
+	if len(results) != 1 || results[0].Size != -1 || results[0].DownloadLink == "" {
+		t.Errorf("Expected the result kept with its download link, got %+v", results)
+	}
+}
+
+func TestClient_WaitForOperation_Context(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"operation_status": "running"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDragdropdo(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForOperation(ctx, "task-123", WaitOptions{Interval: 10 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline exceeded, got %v", err)
+	}
+}
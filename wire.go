@@ -0,0 +1,78 @@
+package d3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// Transport carries the client's API requests and part uploads, so
+// protocols other than direct HTTP, such as a gRPC gateway, an internal
+// relay or a unix socket to a sidecar, can be swapped in without changing
+// how the client builds, retries and decodes requests. Set it with
+// Config.CustomTransport; the default sends both over HTTP with the
+// client's connection pools. Implementations must be safe for concurrent
+// use.
+type Transport interface {
+	// DoJSON sends an API request to url, the endpoint's base URL with the
+	// request's path and query, with body as JSON or no body if nil.
+	// Authentication, hooks, retries, failover and decoding are done by the
+	// client; an error means no response was received.
+	DoJSON(ctx context.Context, method, url string, header http.Header, body []byte) (*TransportResponse, error)
+	// PutPart uploads body, one part of a multipart upload, to its
+	// presigned URL
+	PutPart(ctx context.Context, url string, header http.Header, body []byte) (*TransportResponse, error)
+}
+
+// TransportResponse is a response received by a Transport
+type TransportResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// apiResponse represents a raw API response
+type apiResponse = TransportResponse
+
+// IsSuccess reports whether the response has a 2xx status code
+func (r *TransportResponse) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// httpTransport is the default Transport: API requests go through the
+// build's apiBackend and parts through the part upload client
+type httpTransport struct {
+	api   apiBackend
+	parts *http.Client
+}
+
+// DoJSON implements Transport
+func (t *httpTransport) DoJSON(ctx context.Context, method, url string, header http.Header, body []byte) (*TransportResponse, error) {
+	return t.api.do(ctx, method, url, header, body)
+}
+
+// PutPart implements Transport
+func (t *httpTransport) PutPart(ctx context.Context, url string, header http.Header, body []byte) (*TransportResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	resp, err := t.parts.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &TransportResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package d3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// relayTransport answers requests itself, as a relay or sidecar would
+type relayTransport struct {
+	mu         sync.Mutex
+	calls      []string
+	partStatus int
+}
+
+func (r *relayTransport) DoJSON(ctx context.Context, method, url string, header http.Header, body []byte) (*TransportResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, method+" "+url)
+	switch {
+	case strings.HasSuffix(url, "/v1/biz/initiate-upload"):
+		return &TransportResponse{StatusCode: http.StatusOK, Body: []byte(`{"data":{"file_key":"file-key-1","upload_id":"upload-id","presigned_urls":["relay://storage/part/1?X-Amz-Signature=secret"]}}`)}, nil
+	case strings.HasSuffix(url, "/v1/biz/complete-upload"):
+		return &TransportResponse{StatusCode: http.StatusOK, Body: []byte(`{"data":{}}`)}, nil
+	}
+	return &TransportResponse{StatusCode: http.StatusNotFound, Body: []byte(`{"message":"not found"}`)}, nil
+}
+
+func (r *relayTransport) PutPart(ctx context.Context, url string, header http.Header, body []byte) (*TransportResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, "PUT "+url)
+	if r.partStatus != 0 {
+		return &TransportResponse{StatusCode: r.partStatus, Body: []byte(`<Error><Code>AccessDenied</Code><Message>denied</Message></Error>`)}, nil
+	}
+	return &TransportResponse{StatusCode: http.StatusOK, Header: http.Header{"Etag": {`"etag"`}}}, nil
+}
+
+func TestClient_CustomTransport(t *testing.T) {
+	relay := &relayTransport{}
+	client, err := NewClient("test-key", WithBaseURL("relay://api"), WithCustomTransport(relay))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "doc.pdf")
+	os.WriteFile(file, []byte("content"), 0644)
+
+	resp, err := client.UploadFile(UploadFileOptions{File: file, FileName: "doc.pdf"})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if resp.FileKey != "file-key-1" {
+		t.Errorf("Expected file-key-1, got %s", resp.FileKey)
+	}
+	want := []string{
+		"POST relay://api/v1/biz/initiate-upload",
+		"PUT relay://storage/part/1?X-Amz-Signature=secret",
+		"POST relay://api/v1/biz/complete-upload",
+	}
+	if strings.Join(relay.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected calls %v, got %v", want, relay.calls)
+	}
+
+	relay.partStatus = http.StatusForbidden
+	_, err = client.UploadFile(UploadFileOptions{File: file, FileName: "doc.pdf"})
+	var uploadErr *D3UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.StatusCode == nil || *uploadErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected a D3UploadError with status 403, got %v", err)
+	}
+	if !strings.Contains(uploadErr.Error(), "AccessDenied") || uploadErr.Endpoint != "PUT relay://storage/part/1" {
+		t.Errorf("Expected the storage error without the signature, got %q at %q", uploadErr.Error(), uploadErr.Endpoint)
+	}
+}